@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/files"
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+)
+
+func export(args []string) (bool, error) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	flags.Usage = usage
+	format := flags.String("format", "", "output format, pem for keys and hex for signatures by default")
+	publicFile := flags.String("public", "", "public keys file or keystore of the group")
+	sigFile := flags.String("sig", "", "file containing a signature")
+	out := flags.String("out", "", "output file instead of the standard output")
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 || (*publicFile == "") == (*sigFile == "") {
+		usage()
+		os.Exit(2)
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if *publicFile != "" {
+		if *format == "" {
+			*format = string(eddsa.FormatPEM)
+		}
+		if data, err = exportGroupKey(*publicFile, eddsa.Format(*format)); err != nil {
+			return false, err
+		}
+	} else {
+		if *format == "" {
+			*format = string(eddsa.FormatHex)
+		}
+		if data, err = exportSignature(*sigFile, eddsa.Format(*format)); err != nil {
+			return false, err
+		}
+	}
+	switch eddsa.Format(*format) {
+	case eddsa.FormatHex, eddsa.FormatBase64, eddsa.FormatJWK, eddsa.FormatJWS:
+		data = append(data, '\n')
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(data)
+		return err == nil, err
+	}
+	if err = files.WriteNew(*out, data, 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func exportGroupKey(filename string, format eddsa.Format) ([]byte, error) {
+	key, err := readGroupKey(filename)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := eddsa.NewPublicKeyFromEd25519(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return pk.Export(format)
+}
+
+func exportSignature(filename string, format eddsa.Format) ([]byte, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	sigBytes, err := decodeSignature(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	sig, err := eddsa.NewSignatureFromEd25519(sigBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return sig.Export(format)
+}
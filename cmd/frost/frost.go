@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/keystore"
+)
+
+func usage() {
+	cmd := filepath.Base(os.Args[0])
+	fmt.Printf(`usage: %[1]v verify [-json] [-mode MODE] -message FILE -sig FILE (-public FILE | -key HEX)
+       %[1]v inspect [-json] FILE...
+       %[1]v export [-format FORMAT] [-out FILE] (-public FILE | -sig FILE)
+
+verify   checks a signature of the message in FILE, both with crypto/ed25519 and with
+         eddsa.PublicKey.Verify. The signature is read in binary or hexadecimal.
+         The group key is read from a public keys file or a keystore, or given in hexadecimal.
+         The signature is also checked with the rules of each MODE: strict (crypto/ed25519),
+         cofactored (RFC 8032) and zip215. With -mode, the signature is valid if it is
+         valid for MODE, as for consensus systems; otherwise both implementations must accept it.
+inspect  prints a summary of public keys files, keystores, secret shares, signatures
+         and protocol messages. Public keys files are validated: the shares must be
+         consistent with the threshold, and the group key with the shares.
+         Keystores are not decrypted, and secrets are never printed.
+export   writes the group key or a signature in a standard format.
+         The FORMATs of keys are raw, hex, base64, der, pem (PKIX) and jwk (JSON Web Key).
+         The FORMATs of signatures are raw, hex, base64 and jws (base64url).
+
+The exit status is 1 if a signature or a file is not valid.
+With -json, the result is written as JSON.
+`, cmd)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var (
+		valid bool
+		err   error
+	)
+	switch os.Args[1] {
+	case "verify":
+		valid, err = verify(os.Args[2:])
+	case "inspect":
+		valid, err = inspect(os.Args[2:])
+	case "export":
+		valid, err = export(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if !valid {
+		os.Exit(1)
+	}
+}
+
+// verification is the result of verify.
+type verification struct {
+	Valid     bool   `json:"valid"`
+	GroupKey  string `json:"group_key"`
+	Signature string `json:"signature"`
+
+	// Ed25519 is the result of ed25519.Verify.
+	Ed25519 bool `json:"ed25519"`
+
+	// FROST is the result of eddsa.PublicKey.Verify,
+	// which is not defined if the signature or the key cannot be represented in the ristretto group.
+	FROST      *bool  `json:"frost"`
+	FROSTError string `json:"frost_error,omitempty"`
+
+	// Modes are the results of eddsa.VerifyMode.Verify for every mode.
+	Modes map[string]bool `json:"modes"`
+}
+
+func verify(args []string) (bool, error) {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	flags.Usage = usage
+	messageFile := flags.String("message", "", "file containing the signed message")
+	sigFile := flags.String("sig", "", "file containing the signature")
+	publicFile := flags.String("public", "", "public keys file or keystore of the group")
+	keyHex := flags.String("key", "", "group key in hexadecimal")
+	jsonOutput := flags.Bool("json", false, "write the result as JSON")
+	modeName := flags.String("mode", "", "verification rules deciding the validity: strict, cofactored or zip215")
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 || *messageFile == "" || *sigFile == "" || (*publicFile == "") == (*keyHex == "") {
+		usage()
+		os.Exit(2)
+	}
+	var mode eddsa.VerifyMode
+	if *modeName != "" {
+		var err error
+		if mode, err = eddsa.ParseVerifyMode(*modeName); err != nil {
+			return false, fmt.Errorf("-mode: %w", err)
+		}
+	}
+
+	message, err := ioutil.ReadFile(*messageFile)
+	if err != nil {
+		return false, err
+	}
+	sigData, err := ioutil.ReadFile(*sigFile)
+	if err != nil {
+		return false, err
+	}
+	sig, err := decodeSignature(sigData)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", *sigFile, err)
+	}
+	var key ed25519.PublicKey
+	if *publicFile != "" {
+		if key, err = readGroupKey(*publicFile); err != nil {
+			return false, err
+		}
+	} else if key, err = hex.DecodeString(*keyHex); err != nil || len(key) != ed25519.PublicKeySize {
+		return false, errors.New("-key: not a hexadecimal Ed25519 public key")
+	}
+
+	result := verification{
+		GroupKey:  hex.EncodeToString(key),
+		Signature: hex.EncodeToString(sig),
+		Ed25519:   ed25519.Verify(key, message, sig),
+	}
+	if frostValid, err := verifyFROST(key, message, sig); err != nil {
+		result.FROSTError = err.Error()
+	} else {
+		result.FROST = &frostValid
+	}
+	result.Modes = make(map[string]bool, len(eddsa.VerifyModes))
+	for _, m := range eddsa.VerifyModes {
+		result.Modes[m.String()] = m.Verify(key, message, sig)
+	}
+	if *modeName != "" {
+		result.Valid = result.Modes[mode.String()]
+	} else {
+		// Both implementations must agree when the signature can be represented in the ristretto group.
+		result.Valid = result.Ed25519 && (result.FROST == nil || *result.FROST)
+	}
+
+	if *jsonOutput {
+		return result.Valid, printJSON(result)
+	}
+	fmt.Printf("Group Key:  %v\n", result.GroupKey)
+	fmt.Printf("Signature:  %v\n", result.Signature)
+	fmt.Printf("ed25519:    %v\n", validString(result.Ed25519))
+	if result.FROST != nil {
+		fmt.Printf("FROST:      %v\n", validString(*result.FROST))
+	} else {
+		fmt.Printf("FROST:      not checked (%v)\n", result.FROSTError)
+	}
+	for _, m := range eddsa.VerifyModes {
+		fmt.Printf("%-11v %v\n", m.String()+":", validString(result.Modes[m.String()]))
+	}
+	if result.Valid {
+		fmt.Println("Success: the signature is valid")
+	} else {
+		fmt.Println("Failure: the signature is not valid")
+	}
+	return result.Valid, nil
+}
+
+// verifyFROST verifies an Ed25519 signature with eddsa.PublicKey.Verify.
+func verifyFROST(key ed25519.PublicKey, message, sig []byte) (bool, error) {
+	pk, err := eddsa.NewPublicKeyFromEd25519(key)
+	if err != nil {
+		return false, fmt.Errorf("group key: %w", err)
+	}
+	signature, err := eddsa.NewSignatureFromEd25519(sig)
+	if err != nil {
+		return false, err
+	}
+	return pk.Verify(message, signature), nil
+}
+
+// decodeSignature returns the Ed25519 signature in data,
+// which is either binary or hexadecimal, as written by cmd/signer and cmd/frostd.
+func decodeSignature(data []byte) ([]byte, error) {
+	if len(data) == ed25519.SignatureSize {
+		return data, nil
+	}
+	sig, err := hex.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return nil, errors.New("not an Ed25519 signature")
+	}
+	return sig, nil
+}
+
+// readGroupKey returns the group key in a public keys file or a keystore.
+func readGroupKey(filename string) (ed25519.PublicKey, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if metadata, err := keystore.ReadMetadata(data); err == nil {
+		return metadata.GroupKey.ToEd25519(), nil
+	}
+	var public eddsa.Public
+	if err = json.Unmarshal(data, &public); err != nil {
+		return nil, fmt.Errorf("%s: neither a public keys file nor a keystore: %w", filename, err)
+	}
+	return public.GroupKey.ToEd25519(), nil
+}
+
+func validString(valid bool) string {
+	if valid {
+		return "valid"
+	}
+	return "invalid"
+}
+
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", " ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
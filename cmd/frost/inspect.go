@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/keystore"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+// Types of files recognized by inspect.
+const (
+	typePublic    = "public"
+	typeKeystore  = "keystore"
+	typeShare     = "share"
+	typeSignature = "signature"
+	typeMessage   = "message"
+	typeUnknown   = "unknown"
+)
+
+// summary describes a file. Only the fields relevant to its type are set.
+// Keys and shares are in the Ed25519 format, in hexadecimal.
+type summary struct {
+	File  string `json:"file"`
+	Type  string `json:"type"`
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+
+	Party     uint16            `json:"party,omitempty"`
+	Threshold *uint16           `json:"threshold,omitempty"`
+	Parties   []uint16          `json:"parties,omitempty"`
+	GroupKey  string            `json:"group_key,omitempty"`
+	Shares    map[uint16]string `json:"shares,omitempty"`
+	Created   *time.Time        `json:"created,omitempty"`
+
+	// PublicShare is the public key of a plaintext secret share.
+	PublicShare string `json:"public_share,omitempty"`
+
+	Signature string `json:"signature,omitempty"`
+
+	MessageType string `json:"message_type,omitempty"`
+	From        uint16 `json:"from,omitempty"`
+	To          uint16 `json:"to,omitempty"`
+	Session     string `json:"session,omitempty"`
+}
+
+func inspect(args []string) (bool, error) {
+	flags := flag.NewFlagSet("inspect", flag.ExitOnError)
+	flags.Usage = usage
+	jsonOutput := flags.Bool("json", false, "write the result as JSON")
+	_ = flags.Parse(args)
+	if flags.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	summaries := make([]*summary, 0, flags.NArg())
+	valid := true
+	for _, filename := range flags.Args() {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return false, err
+		}
+		s := inspectData(data)
+		s.File = filename
+		valid = valid && s.Valid
+		summaries = append(summaries, s)
+	}
+
+	if *jsonOutput {
+		return valid, printJSON(summaries)
+	}
+	for i, s := range summaries {
+		if i > 0 {
+			fmt.Println()
+		}
+		s.print()
+	}
+	return valid, nil
+}
+
+// inspectData recognizes the type of a file from its content, and summarizes it.
+func inspectData(data []byte) *summary {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &fields); err != nil {
+			return &summary{Type: typeUnknown, Error: err.Error()}
+		}
+		switch {
+		case fields["ciphertext"] != nil:
+			return inspectKeystore(data)
+		case fields["shares"] != nil:
+			return inspectPublic(data)
+		case fields["secret"] != nil:
+			return inspectShare(data)
+		}
+		return &summary{Type: typeUnknown, Error: "unrecognized JSON file"}
+	}
+
+	if sig, err := decodeSignature(data); err == nil {
+		return inspectSignature(sig)
+	}
+
+	var msg messages.Message
+	if err := msg.UnmarshalBinary(data); err == nil {
+		return &summary{
+			Type:        typeMessage,
+			Valid:       true,
+			MessageType: msg.Type.String(),
+			From:        uint16(msg.From),
+			To:          uint16(msg.To),
+			Session:     msg.SessionID.String(),
+		}
+	}
+	return &summary{Type: typeUnknown, Error: "unrecognized file"}
+}
+
+func inspectPublic(data []byte) *summary {
+	s := &summary{Type: typePublic}
+	// UnmarshalJSON checks the group key against the interpolation of all the shares.
+	var public eddsa.Public
+	if err := json.Unmarshal(data, &public); err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	threshold := uint16(public.Threshold)
+	s.Threshold = &threshold
+	s.Parties = ids(public.PartyIDs)
+	s.GroupKey = hex.EncodeToString(public.GroupKey.ToEd25519())
+	s.Shares = make(map[uint16]string, len(public.Shares))
+	for id, share := range public.Shares {
+		s.Shares[uint16(id)] = hex.EncodeToString(share.BytesEd25519())
+	}
+	if err := public.Validate(); err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	s.Valid = true
+	return s
+}
+
+func inspectKeystore(data []byte) *summary {
+	s := &summary{Type: typeKeystore}
+	metadata, err := keystore.ReadMetadata(data)
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	threshold := uint16(metadata.Threshold)
+	s.Party = uint16(metadata.ID)
+	s.Threshold = &threshold
+	s.GroupKey = hex.EncodeToString(metadata.GroupKey.ToEd25519())
+	s.Created = &metadata.Created
+	s.Valid = true
+	return s
+}
+
+func inspectShare(data []byte) *summary {
+	s := &summary{Type: typeShare}
+	var secret eddsa.SecretShare
+	if err := json.Unmarshal(data, &secret); err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	s.Party = uint16(secret.ID)
+	s.PublicShare = hex.EncodeToString(secret.Public.BytesEd25519())
+	switch {
+	case secret.ID == 0:
+		s.Error = "party ID 0 is not valid"
+	case secret.Secret.Equal(ristretto.NewScalar()) == 1:
+		s.Error = "the secret share is 0"
+	default:
+		s.Valid = true
+	}
+	return s
+}
+
+func inspectSignature(sig []byte) *summary {
+	s := &summary{
+		Type:      typeSignature,
+		Signature: hex.EncodeToString(sig),
+	}
+	// A signature which cannot be parsed may still be a valid Ed25519 signature, but not one produced by FROST.
+	if _, err := eddsa.NewSignatureFromEd25519(sig); err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	s.Valid = true
+	return s
+}
+
+func (s *summary) print() {
+	fmt.Printf("File:       %v\n", s.File)
+	fmt.Printf("Type:       %v\n", s.Type)
+	if s.Party != 0 {
+		fmt.Printf("Party:      %d\n", s.Party)
+	}
+	if s.Threshold != nil {
+		if s.Parties != nil {
+			fmt.Printf("Threshold:  %d of %d parties\n", *s.Threshold+1, len(s.Parties))
+			fmt.Printf("Parties:    %v\n", s.Parties)
+		} else {
+			fmt.Printf("Threshold:  %d parties\n", *s.Threshold+1)
+		}
+	}
+	if s.GroupKey != "" {
+		fmt.Printf("Group Key:  %v\n", s.GroupKey)
+	}
+	for _, id := range s.Parties {
+		if share, ok := s.Shares[id]; ok {
+			fmt.Printf("Share %-5d %v\n", id, share)
+		}
+	}
+	if s.Created != nil {
+		fmt.Printf("Created:    %v\n", s.Created.Format(time.RFC3339))
+	}
+	if s.PublicShare != "" {
+		fmt.Printf("Public:     %v\n", s.PublicShare)
+		fmt.Println("Warning:    the secret share is not encrypted, see cmd/keystore")
+	}
+	if s.Signature != "" {
+		fmt.Printf("R:          %v\n", s.Signature[:64])
+		fmt.Printf("S:          %v\n", s.Signature[64:])
+	}
+	if s.MessageType != "" {
+		fmt.Printf("Message:    %v\n", s.MessageType)
+		fmt.Printf("From:       %d\n", s.From)
+		if s.To != 0 {
+			fmt.Printf("To:         %d\n", s.To)
+		} else {
+			fmt.Println("To:         all parties")
+		}
+		fmt.Printf("Session:    %v\n", s.Session)
+	}
+	if s.Valid {
+		fmt.Println("Status:     valid")
+	} else {
+		fmt.Printf("Status:     invalid: %v\n", s.Error)
+	}
+}
+
+func ids(partyIDs party.IDSlice) []uint16 {
+	out := make([]uint16, 0, len(partyIDs))
+	for _, id := range partyIDs {
+		out = append(out, uint16(id))
+	}
+	return out
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/network"
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/passphrase"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/keystore"
+)
+
+func usage() {
+	cmd := filepath.Base(os.Args[0])
+	fmt.Printf(`usage: %[1]v -config FILE
+
+frostd is a signer node which holds the encrypted secret share of one party,
+and runs keygen and sign sessions with the other parties when requested over
+a local HTTP/JSON API:
+
+  POST /v1/keygen                  {"session": HEX, "threshold": T}
+  POST /v1/sign                    {"session": HEX, "signers": [ID, ...], "message": BASE64}
+  GET  /v1/sessions/HEX            status of a session
+  GET  /v1/sessions/HEX/signature  signature produced by a sign session
+  GET  /v1/key                     public keys of the group
+  GET  /v1/jwks                    group key as a JSON Web Key Set, to verify JWTs signed by the group
+
+Every party must be asked to start the same session, with the same parameters.
+Requests with a body must have the Content-Type application/json.
+
+When the API listens on TCP, every request except GET /v1/jwks must carry the header
+  Authorization: Bearer TOKEN
+where TOKEN is the first line of "token_file". A "listen" address unix:PATH serves the
+API on a unix socket which only the owner of frostd can connect to, and then the token
+is optional.
+
+The configuration FILE contains
+  {
+    "id": 1,
+    "listen": "127.0.0.1:7700" or "unix:/run/frostd/api.sock",
+    "token_file": "/etc/frostd/token",
+    "keystore": "/var/lib/frostd/keystore.json",
+    "passphrase_file": "/etc/frostd/passphrase",
+    "peers": "/etc/frostd/peers.json",
+    "transport": {"dir": DIR, "mailbox_key": FILE} or {"relay": URL, "mailbox_key": FILE}
+                 or {"cert": FILE, "key": FILE, "ca": FILE},
+    "timeout": "10m"
+  }
+The peers file is the same as for cmd/keygen. The keystore is created by the first
+keygen session if it does not exist.
+The passphrase is read from the first line of "passphrase_file", from the %[2]v
+environment variable, or from the standard input.
+`, cmd, passphrase.EnvVar)
+}
+
+// config is the content of the configuration file.
+type config struct {
+	ID             uint16            `json:"id"`
+	Listen         string            `json:"listen"`
+	TokenFile      string            `json:"token_file"`
+	Keystore       string            `json:"keystore"`
+	PassphraseFile string            `json:"passphrase_file"`
+	Peers          string            `json:"peers"`
+	Transport      network.Transport `json:"transport"`
+	Timeout        duration          `json:"timeout"`
+}
+
+// duration is a time.Duration encoded in JSON as a string such as "10m".
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration(parsed)
+	return nil
+}
+
+func main() {
+	configFile := flag.String("config", "", "configuration file")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 0 || *configFile == "" {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := run(*configFile); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(configFile string) error {
+	cfg := config{
+		Listen:  "127.0.0.1:7700",
+		Timeout: duration(10 * time.Minute),
+	}
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("%s: %w", configFile, err)
+	}
+	if cfg.Keystore == "" {
+		return fmt.Errorf("%s: a keystore is required", configFile)
+	}
+
+	peers, partyIDs, err := network.ReadPeers(cfg.Peers)
+	if err != nil {
+		return err
+	}
+	selfID := party.ID(cfg.ID)
+	if !partyIDs.Contains(selfID) {
+		return fmt.Errorf("%s: no entry for party %d", cfg.Peers, cfg.ID)
+	}
+
+	var token []byte
+	if cfg.TokenFile != "" {
+		if token, err = readToken(cfg.TokenFile); err != nil {
+			return err
+		}
+	}
+	socket := strings.HasPrefix(cfg.Listen, "unix:")
+	if !socket && token == nil {
+		return fmt.Errorf("%s: a token_file is required when listening on TCP", configFile)
+	}
+
+	node := &node{
+		id:           selfID,
+		peers:        peers,
+		partyIDs:     partyIDs,
+		transport:    cfg.Transport,
+		timeout:      time.Duration(cfg.Timeout),
+		keystoreFile: cfg.Keystore,
+		token:        token,
+		sessions:     map[string]*session{},
+	}
+
+	// The keystore is opened now, so that the passphrase is not needed later.
+	keystoreData, err := ioutil.ReadFile(cfg.Keystore)
+	switch {
+	case err == nil:
+		p, err := passphrase.Read(cfg.PassphraseFile, "Keystore passphrase")
+		if err != nil {
+			return err
+		}
+		if node.keys, err = keystore.Open(keystoreData, p); err != nil {
+			return fmt.Errorf("%s: %w", cfg.Keystore, err)
+		}
+		if node.keys.Metadata.ID != selfID {
+			return fmt.Errorf("%s: keystore of party %d, but this is party %d", cfg.Keystore, node.keys.Metadata.ID, cfg.ID)
+		}
+		log.Printf("party %d: opened keystore %v with group key %x", cfg.ID, cfg.Keystore, node.keys.Metadata.GroupKey.ToEd25519())
+	case os.IsNotExist(err):
+		if node.passphrase, err = passphrase.New(cfg.PassphraseFile, "New keystore passphrase"); err != nil {
+			return err
+		}
+		log.Printf("party %d: no keystore, waiting for a keygen session", cfg.ID)
+	default:
+		return err
+	}
+
+	var listener net.Listener
+	if socket {
+		path := strings.TrimPrefix(cfg.Listen, "unix:")
+		if listener, err = listenUnix(path); err != nil {
+			return err
+		}
+		defer os.Remove(path)
+	} else if listener, err = net.Listen("tcp", cfg.Listen); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	node.ctx = ctx
+	server := &http.Server{
+		Handler:      node.handler(),
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+		log.Printf("party %d: shutting down", cfg.ID)
+		cancel()
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	addr := listener.Addr().String()
+	if socket {
+		// The socket was created under a temporary name.
+		addr = cfg.Listen
+	}
+	log.Printf("party %d: listening on %v", cfg.ID, addr)
+	if err = server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	<-done
+	node.wait()
+	return nil
+}
+
+// readToken returns the first line of the token file.
+func readToken(filename string) ([]byte, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		data = data[:i]
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%s: empty token", filename)
+	}
+	return data, nil
+}
+
+// listenUnix listens on a unix socket at path, which only the owner can connect to.
+// The socket is created in a private directory and then moved to path, so that it is never accessible to others.
+// A socket left by a previous run is replaced.
+func listenUnix(path string) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		_ = os.Remove(path)
+	}
+	dir, err := ioutil.TempDir(filepath.Dir(path), ".frostd")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tmp := filepath.Join(dir, "api.sock")
+	listener, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.Chmod(tmp, 0600); err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
@@ -0,0 +1,480 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/files"
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/network"
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/jws"
+	"github.com/taurusgroup/frost-ed25519/pkg/keystore"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+	"github.com/taurusgroup/frost-ed25519/pkg/transport"
+)
+
+const (
+	// maxRequestSize bounds the size of the body of a request, and therefore of a message to sign.
+	maxRequestSize = 1 << 20
+
+	// sessionRetention is the time during which a finished session can be queried.
+	sessionRetention = 24 * time.Hour
+)
+
+// node runs the sessions of one party.
+type node struct {
+	id           party.ID
+	peers        map[party.ID]network.Peer
+	partyIDs     party.IDSlice
+	transport    network.Transport
+	timeout      time.Duration
+	keystoreFile string
+	// token is required in the Authorization header of requests, unless it is nil
+	token []byte
+
+	// ctx is canceled when the node shuts down, and aborts all sessions
+	ctx context.Context
+	wg  sync.WaitGroup
+
+	mtx sync.Mutex
+	// keys is nil until a keygen session has finished, and passphrase is then used to create the keystore
+	keys       *keystore.Keystore
+	passphrase []byte
+	keygen     bool
+	// busy is true while a session is using a transport which does not support concurrent sessions
+	busy     bool
+	sessions map[string]*session
+}
+
+// session is a keygen or sign session started through the API.
+type session struct {
+	id       messages.SessionID
+	kind     string
+	started  time.Time
+	state    *state.State
+	finished time.Time
+	err      error
+	// signature is the output of a sign session, and groupKey of a keygen session
+	signature *eddsa.Signature
+	groupKey  *eddsa.PublicKey
+}
+
+// sessionStatus is the JSON encoding of a session.
+type sessionStatus struct {
+	Session   messages.SessionID `json:"session"`
+	Type      string             `json:"type"`
+	Status    string             `json:"status"`
+	Round     int                `json:"round"`
+	Missing   []uint16           `json:"missing,omitempty"`
+	Error     string             `json:"error,omitempty"`
+	Culprit   uint16             `json:"culprit,omitempty"`
+	Started   time.Time          `json:"started"`
+	Finished  *time.Time         `json:"finished,omitempty"`
+	Signature string             `json:"signature,omitempty"`
+	GroupKey  string             `json:"group_key,omitempty"`
+}
+
+type keygenRequest struct {
+	Session   messages.SessionID `json:"session"`
+	Threshold uint16             `json:"threshold"`
+}
+
+type signRequest struct {
+	Session messages.SessionID `json:"session"`
+	Signers []uint16           `json:"signers"`
+	Message []byte             `json:"message"`
+}
+
+type keyResponse struct {
+	ID        uint16        `json:"id"`
+	Threshold uint16        `json:"threshold"`
+	Parties   []uint16      `json:"parties"`
+	GroupKey  string        `json:"group_key"`
+	Public    *eddsa.Public `json:"public"`
+	Created   time.Time     `json:"created"`
+}
+
+type signatureResponse struct {
+	Session   messages.SessionID `json:"session"`
+	Signature string             `json:"signature"`
+	GroupKey  string             `json:"group_key"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (n *node) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/keygen", n.handleKeygen)
+	mux.HandleFunc("/v1/sign", n.handleSign)
+	mux.HandleFunc("/v1/key", n.handleKey)
+	mux.HandleFunc("/v1/jwks", n.handleJWKS)
+	mux.HandleFunc("/v1/sessions/", n.handleSession)
+	return n.authenticate(mux)
+}
+
+// authenticate requires the bearer token of n for all requests to next, except for the JWKS,
+// which is public and fetched by the services verifying tokens.
+func (n *node) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n.token != nil && r.URL.Path != "/v1/jwks" {
+			authorization := r.Header.Get("Authorization")
+			token := strings.TrimPrefix(authorization, "Bearer ")
+			if len(token) == len(authorization) || subtle.ConstantTimeCompare([]byte(token), n.token) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, http.StatusUnauthorized, errors.New("a valid bearer token is required"))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (n *node) handleKeygen(w http.ResponseWriter, r *http.Request) {
+	var req keygenRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Session == (messages.SessionID{}) {
+		writeError(w, http.StatusBadRequest, errors.New("a session ID is required"))
+		return
+	}
+	threshold := party.Size(req.Threshold)
+	if threshold == 0 || threshold >= n.partyIDs.N() {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("threshold must be between 1 and %d", n.partyIDs.N()-1))
+		return
+	}
+
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	if n.keys != nil || n.keygen {
+		writeError(w, http.StatusConflict, errors.New("this node already has a key"))
+		return
+	}
+	s, output, err := frost.NewKeygenState(n.ctx, n.id, n.partyIDs, threshold, n.timeout, state.WithSessionID(req.Session))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	sess, err := n.start("keygen", req.Session, s, func(sess *session) error {
+		if err := n.saveKeystore(output.SecretKey, output.Public); err != nil {
+			return err
+		}
+		sess.groupKey = output.Public.GroupKey
+		return nil
+	})
+	if err != nil {
+		s.Expire()
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	n.keygen = true
+	writeJSON(w, http.StatusAccepted, sess.status())
+}
+
+func (n *node) handleSign(w http.ResponseWriter, r *http.Request) {
+	var req signRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Session == (messages.SessionID{}) {
+		writeError(w, http.StatusBadRequest, errors.New("a session ID is required"))
+		return
+	}
+	signers := make([]party.ID, 0, len(req.Signers))
+	for _, id := range req.Signers {
+		signers = append(signers, party.ID(id))
+	}
+
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	if n.keys == nil {
+		writeError(w, http.StatusConflict, errors.New("this node has no key yet"))
+		return
+	}
+	s, output, err := frost.NewSignState(n.ctx, party.NewIDSlice(signers), n.keys.Secret, n.keys.Public, req.Message, n.timeout,
+		state.WithSessionID(req.Session))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	sess, err := n.start("sign", req.Session, s, func(sess *session) error {
+		sess.signature = output.Signature
+		sess.groupKey = n.keys.Public.GroupKey
+		return nil
+	})
+	if err != nil {
+		s.Expire()
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, sess.status())
+}
+
+func (n *node) handleKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	n.mtx.Lock()
+	keys := n.keys
+	n.mtx.Unlock()
+	if keys == nil {
+		writeError(w, http.StatusNotFound, errors.New("this node has no key yet"))
+		return
+	}
+	writeJSON(w, http.StatusOK, keyResponse{
+		ID:        uint16(keys.Metadata.ID),
+		Threshold: uint16(keys.Metadata.Threshold),
+		Parties:   numbers(keys.Public.PartyIDs),
+		GroupKey:  hex.EncodeToString(keys.Metadata.GroupKey.ToEd25519()),
+		Public:    keys.Public,
+		Created:   keys.Metadata.Created,
+	})
+}
+
+// handleJWKS serves the group key as a JSON Web Key Set, for services verifying the tokens signed by the group.
+func (n *node) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	n.mtx.Lock()
+	keys := n.keys
+	n.mtx.Unlock()
+	if keys == nil {
+		writeError(w, http.StatusNotFound, errors.New("this node has no key yet"))
+		return
+	}
+	writeJSON(w, http.StatusOK, jws.NewJWKS(keys.Metadata.GroupKey))
+}
+
+// handleSession serves /v1/sessions/HEX and /v1/sessions/HEX/signature.
+func (n *node) handleSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	path := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/sessions/"), "/")
+	if len(path) > 2 || (len(path) == 2 && path[1] != "signature") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	var id messages.SessionID
+	if err := id.UnmarshalText([]byte(path[0])); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	n.mtx.Lock()
+	sess, ok := n.sessions[id.String()]
+	var status sessionStatus
+	if ok {
+		status = sess.status()
+	}
+	n.mtx.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("unknown session"))
+		return
+	}
+
+	if len(path) == 1 {
+		writeJSON(w, http.StatusOK, status)
+		return
+	}
+	if status.Type != "sign" {
+		writeError(w, http.StatusNotFound, errors.New("not a sign session"))
+		return
+	}
+	if status.Signature == "" {
+		writeJSON(w, http.StatusConflict, status)
+		return
+	}
+	writeJSON(w, http.StatusOK, signatureResponse{
+		Session:   status.Session,
+		Signature: status.Signature,
+		GroupKey:  status.GroupKey,
+	})
+}
+
+// start runs s in the background, and calls finish with the session once it completed successfully.
+// It must be called with n.mtx held, and the session is only updated with n.mtx held.
+func (n *node) start(kind string, id messages.SessionID, s *state.State, finish func(*session) error) (*session, error) {
+	n.prune()
+	if _, ok := n.sessions[id.String()]; ok {
+		return nil, errors.New("session ID already used")
+	}
+	if n.busy {
+		return nil, errors.New("another session is running, and the transport does not support concurrent sessions")
+	}
+	comm, err := n.transport.Open(n.id, id, n.peers, n.timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	sess := &session{
+		id:      id,
+		kind:    kind,
+		started: time.Now().UTC(),
+		state:   s,
+	}
+	n.sessions[id.String()] = sess
+	exclusive := !n.transport.Concurrent()
+	n.busy = exclusive
+	log.Printf("party %d: %s session %v started", n.id, kind, id)
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		err := transport.Run(s, comm)
+		comm.Done()
+
+		n.mtx.Lock()
+		defer n.mtx.Unlock()
+		if err == nil {
+			err = finish(sess)
+		}
+		if kind == "keygen" {
+			n.keygen = false
+		}
+		if exclusive {
+			n.busy = false
+		}
+		sess.err = err
+		sess.finished = time.Now().UTC()
+		if err != nil {
+			log.Printf("party %d: %s session %v failed: %v", n.id, kind, id, err)
+		} else {
+			log.Printf("party %d: %s session %v finished", n.id, kind, id)
+		}
+	}()
+	return sess, nil
+}
+
+// saveKeystore writes the keystore created by a keygen session, and uses it for the following sessions.
+// It must be called with n.mtx held.
+func (n *node) saveKeystore(secret *eddsa.SecretShare, public *eddsa.Public) error {
+	data, err := keystore.Seal(secret, public, n.passphrase)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(n.keystoreFile), 0700); err != nil {
+		return err
+	}
+	if err = files.WriteNew(n.keystoreFile, data, 0600); err != nil {
+		return err
+	}
+	if n.keys, err = keystore.Open(data, n.passphrase); err != nil {
+		return err
+	}
+	for i := range n.passphrase {
+		n.passphrase[i] = 0
+	}
+	n.passphrase = nil
+	log.Printf("party %d: keystore written to %v", n.id, n.keystoreFile)
+	return nil
+}
+
+// prune forgets the sessions which finished more than sessionRetention ago.
+// It must be called with n.mtx held.
+func (n *node) prune() {
+	for key, sess := range n.sessions {
+		if !sess.finished.IsZero() && time.Since(sess.finished) > sessionRetention {
+			delete(n.sessions, key)
+		}
+	}
+}
+
+// wait blocks until all sessions have stopped.
+func (n *node) wait() {
+	n.wg.Wait()
+}
+
+// status must be called with n.mtx held.
+func (sess *session) status() sessionStatus {
+	status := sessionStatus{
+		Session: sess.id,
+		Type:    sess.kind,
+		Status:  "running",
+		Round:   sess.state.RoundNumber(),
+		Started: sess.started,
+	}
+	switch {
+	case sess.finished.IsZero():
+		status.Missing = numbers(sess.state.MissingParties())
+	case sess.err != nil:
+		status.Status = "failed"
+		status.Error = sess.err.Error()
+		var stateErr *state.Error
+		if errors.As(sess.err, &stateErr) {
+			status.Culprit = uint16(stateErr.PartyID)
+		}
+	default:
+		status.Status = "done"
+	}
+	if !sess.finished.IsZero() {
+		finished := sess.finished
+		status.Finished = &finished
+	}
+	if sess.signature != nil {
+		status.Signature = hex.EncodeToString(sess.signature.ToEd25519())
+	}
+	if sess.groupKey != nil {
+		status.GroupKey = hex.EncodeToString(sess.groupKey.ToEd25519())
+	}
+	return status
+}
+
+// numbers returns the IDs as integers, since the API encodes them as JSON numbers.
+func numbers(ids party.IDSlice) []uint16 {
+	out := make([]uint16, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, uint16(id))
+	}
+	return out
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return false
+	}
+	// Browsers can only send other types to another origin without asking it first.
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, errors.New("the request must be application/json"))
+		return false
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
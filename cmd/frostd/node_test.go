@@ -0,0 +1,118 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/keystore"
+)
+
+const testToken = "0123456789abcdef"
+
+func newTestNode(t *testing.T, token string) *node {
+	partyIDs := helpers.GenerateSet(2)
+	_, secrets := helpers.GenerateSecrets(partyIDs, 1)
+	public := helpers.GeneratePublic(1, secrets)
+	data, err := keystore.Seal(secrets[partyIDs[0]], public, []byte("passphrase"))
+	require.NoError(t, err)
+	keys, err := keystore.Open(data, []byte("passphrase"))
+	require.NoError(t, err)
+
+	n := &node{
+		id:       partyIDs[0],
+		partyIDs: partyIDs,
+		keys:     keys,
+		sessions: map[string]*session{},
+	}
+	if token != "" {
+		n.token = []byte(token)
+	}
+	return n
+}
+
+func serve(h http.Handler, method, path, authorization, contentType, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if authorization != "" {
+		r.Header.Set("Authorization", authorization)
+	}
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestHandler_Authentication(t *testing.T) {
+	h := newTestNode(t, testToken).handler()
+	keygen := `{"session": "0123456789abcdef0123456789abcdef", "threshold": 1}`
+
+	for _, authorization := range []string{"", "Bearer", "Bearer wrong", "Basic " + testToken, testToken} {
+		w := serve(h, http.MethodGet, "/v1/key", authorization, "", "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code, authorization)
+		assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+		w = serve(h, http.MethodPost, "/v1/keygen", authorization, "application/json", keygen)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, authorization)
+	}
+
+	w := serve(h, http.MethodGet, "/v1/key", "Bearer "+testToken, "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	// The node already has a key, so the request is valid but refused.
+	w = serve(h, http.MethodPost, "/v1/keygen", "Bearer "+testToken, "application/json", keygen)
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+
+	// The JWKS is public.
+	w = serve(h, http.MethodGet, "/v1/jwks", "", "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Without a token, as on a unix socket, all requests are accepted.
+	h = newTestNode(t, "").handler()
+	w = serve(h, http.MethodGet, "/v1/key", "", "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHandler_ContentType(t *testing.T) {
+	h := newTestNode(t, testToken).handler()
+	sign := `{"session": "0123456789abcdef0123456789abcdef", "signers": [1, 2], "message": "aGVsbG8="}`
+
+	// Browsers send these types to other origins without a preflight request.
+	for _, contentType := range []string{"", "text/plain", "application/x-www-form-urlencoded", "multipart/form-data; boundary=x"} {
+		for _, path := range []string{"/v1/keygen", "/v1/sign"} {
+			w := serve(h, http.MethodPost, path, "Bearer "+testToken, contentType, sign)
+			assert.Equal(t, http.StatusUnsupportedMediaType, w.Code, "%s %s", path, contentType)
+		}
+	}
+
+	w := serve(h, http.MethodPost, "/v1/sign", "Bearer "+testToken, "application/json; charset=utf-8", `{"unknown": 1}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListenUnix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "frostd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "api.sock")
+
+	for i := 0; i < 2; i++ {
+		// The socket of the previous iteration is replaced.
+		listener, err := listenUnix(path)
+		require.NoError(t, err)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.True(t, info.Mode()&os.ModeSocket != 0)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		require.NoError(t, listener.Close())
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "the temporary directory should be removed")
+}
@@ -0,0 +1,70 @@
+// Package files reads and writes the files of the command line tools.
+package files
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/passphrase"
+	"github.com/taurusgroup/frost-ed25519/pkg/keystore"
+)
+
+// OpenKeystore decrypts the keystore in filename with the passphrase obtained by passphrase.Read.
+func OpenKeystore(filename, passphraseFile string) (*keystore.Keystore, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	p, err := passphrase.Read(passphraseFile, "Keystore passphrase")
+	if err != nil {
+		return nil, err
+	}
+	ks, err := keystore.Open(data, p)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return ks, nil
+}
+
+// WriteNew writes data to a file which must not exist yet, and syncs it to disk.
+// The file is removed if it could not be written completely.
+func WriteNew(filename string, data []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(filename)
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		_ = os.Remove(filename)
+		return err
+	}
+	return f.Close()
+}
+
+// Destroy overwrites the content of a file with zeros before removing it.
+func Destroy(filename string) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err == nil {
+		_, err = f.WriteAt(make([]byte, info.Size()), 0)
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Remove(filename)
+}
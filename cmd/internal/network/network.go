@@ -0,0 +1,211 @@
+// Package network configures the transport between the parties for the command line tools.
+//
+// The peers file contains an entry for every party, including this one:
+//
+//	{
+//	  "1": {"address": "host1:7000", "name": "party1.example.com", "mailbox": "<public mailbox key>"},
+//	  ...
+//	}
+//
+// Only the fields used by the transport are required.
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/files"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/transport"
+)
+
+// Peer is an entry of the peers file.
+type Peer struct {
+	Address string             `json:"address,omitempty"`
+	Name    string             `json:"name,omitempty"`
+	Mailbox *ristretto.Element `json:"mailbox,omitempty"`
+}
+
+// ReadPeers reads a peers file, and returns its entries with the sorted IDs of all parties.
+func ReadPeers(filename string) (map[party.ID]Peer, party.IDSlice, error) {
+	var peers map[party.ID]Peer
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = json.Unmarshal(data, &peers); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	ids := make([]party.ID, 0, len(peers))
+	for id := range peers {
+		if id == 0 {
+			return nil, nil, fmt.Errorf("%s: party ID 0 is not valid", filename)
+		}
+		ids = append(ids, id)
+	}
+	return peers, party.NewIDSlice(ids), nil
+}
+
+// ParseSigners parses the comma separated IDs of the -signers flag.
+func ParseSigners(list string) (party.IDSlice, error) {
+	var ids []party.ID
+	for _, field := range strings.Split(list, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(field), 10, 16)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("-signers: invalid party ID %q", field)
+		}
+		ids = append(ids, party.ID(id))
+	}
+	return party.NewIDSlice(ids), nil
+}
+
+// mailboxKey is the content of a mailbox key file.
+type mailboxKey struct {
+	Secret []byte             `json:"secret"`
+	Public *ristretto.Element `json:"public"`
+}
+
+// NewMailboxKey creates a new mailbox key in filename, which must not exist, and returns its public key.
+func NewMailboxKey(filename string) (*ristretto.Element, error) {
+	secret, public := transport.NewMailboxKey()
+	data, err := json.MarshalIndent(mailboxKey{
+		Secret: secret.Bytes(),
+		Public: public,
+	}, "", " ")
+	if err != nil {
+		return nil, err
+	}
+	if err = files.WriteNew(filename, data, 0600); err != nil {
+		return nil, err
+	}
+	return public, nil
+}
+
+// TransportUsage describes the flags registered by Transport.RegisterFlags, for the usage of the commands.
+const TransportUsage = `  -dir DIR -mailbox-key FILE          exchange encrypted messages through a shared directory
+  -relay URL -mailbox-key FILE        exchange encrypted messages through a relay (see cmd/relay)
+  -cert FILE -key FILE -ca FILE       connect to the other parties directly over TLS
+`
+
+// Transport selects how messages are exchanged with the peers.
+// Either Directory or RelayURL must be set together with MailboxKey, or Cert, Key and CA must be set.
+type Transport struct {
+	// Directory is a directory shared by all parties.
+	Directory string `json:"dir,omitempty"`
+
+	// RelayURL is the URL of a relay, see cmd/relay.
+	RelayURL string `json:"relay,omitempty"`
+
+	// MailboxKey is the file containing the mailbox key of this party.
+	MailboxKey string `json:"mailbox_key,omitempty"`
+
+	// Cert, Key and CA are the files containing the TLS certificate and private key of this party,
+	// and the certificates of the CAs which issued the certificates of the peers.
+	Cert string `json:"cert,omitempty"`
+	Key  string `json:"key,omitempty"`
+	CA   string `json:"ca,omitempty"`
+}
+
+// RegisterFlags defines the flags which set the fields of t.
+func (t *Transport) RegisterFlags(flags *flag.FlagSet) {
+	flags.StringVar(&t.Directory, "dir", "", "shared directory")
+	flags.StringVar(&t.RelayURL, "relay", "", "URL of the relay")
+	flags.StringVar(&t.MailboxKey, "mailbox-key", "", "mailbox key of this party")
+	flags.StringVar(&t.Cert, "cert", "", "TLS certificate of this party")
+	flags.StringVar(&t.Key, "key", "", "TLS private key of this party")
+	flags.StringVar(&t.CA, "ca", "", "CA certificates of the peers")
+}
+
+// Concurrent returns true if several sessions can be run at the same time.
+// A TCP communicator listens on the address of this party, which can only be used by one session.
+func (t *Transport) Concurrent() bool {
+	return t.Cert == ""
+}
+
+// Open returns a Communicator for the session with the given ID, whose Timeout is timeout.
+func (t *Transport) Open(selfID party.ID, sessionID messages.SessionID, peers map[party.ID]Peer, timeout time.Duration) (transport.Communicator, error) {
+	switch {
+	case t.Directory != "" || t.RelayURL != "":
+		return t.openMailbox(selfID, sessionID, peers, timeout)
+	case t.Cert != "":
+		return t.openTCP(selfID, peers, timeout)
+	default:
+		return nil, errors.New("a transport is required")
+	}
+}
+
+func (t *Transport) openMailbox(selfID party.ID, sessionID messages.SessionID, peers map[party.ID]Peer, timeout time.Duration) (transport.Communicator, error) {
+	if t.MailboxKey == "" {
+		return nil, errors.New("a mailbox key is required")
+	}
+	data, err := ioutil.ReadFile(t.MailboxKey)
+	if err != nil {
+		return nil, err
+	}
+	var key mailboxKey
+	if err = json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("%s: %w", t.MailboxKey, err)
+	}
+	secret, err := ristretto.NewScalar().SetCanonicalBytes(key.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.MailboxKey, err)
+	}
+
+	publics := make(map[party.ID]*ristretto.Element, len(peers))
+	for id, p := range peers {
+		if id != selfID && p.Mailbox == nil {
+			return nil, fmt.Errorf("party %d has no mailbox key", id)
+		}
+		publics[id] = p.Mailbox
+	}
+	return transport.NewMailbox(transport.MailboxConfig{
+		ID:        selfID,
+		SessionID: sessionID,
+		RelayURL:  t.RelayURL,
+		Directory: t.Directory,
+		Secret:    secret,
+		Peers:     publics,
+		Timeout:   timeout,
+	})
+}
+
+func (t *Transport) openTCP(selfID party.ID, peers map[party.ID]Peer, timeout time.Duration) (transport.Communicator, error) {
+	cert, err := tls.LoadX509KeyPair(t.Cert, t.Key)
+	if err != nil {
+		return nil, err
+	}
+	caData, err := ioutil.ReadFile(t.CA)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("%s: no certificate found", t.CA)
+	}
+
+	directory := make(map[party.ID]transport.Peer, len(peers))
+	for id, p := range peers {
+		directory[id] = transport.Peer{Address: p.Address, Name: p.Name}
+	}
+	return transport.NewTCPCommunicator(transport.Config{
+		ID:    selfID,
+		Peers: directory,
+		TLS: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			RootCAs:      pool,
+			ClientCAs:    pool,
+			MinVersion:   tls.VersionTLS12,
+		},
+		Timeout: timeout,
+	})
+}
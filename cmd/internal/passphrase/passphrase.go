@@ -0,0 +1,93 @@
+// Package passphrase obtains the passphrase of a keystore for the command line tools.
+//
+// The passphrase is read from the first line of a file if one is given,
+// otherwise from the FROST_PASSPHRASE environment variable,
+// and otherwise from the standard input after printing a prompt on the standard error.
+// When the standard input is a terminal, the passphrase is not echoed.
+package passphrase
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// EnvVar is the environment variable holding the passphrase.
+const EnvVar = "FROST_PASSPHRASE"
+
+var stdin = bufio.NewReader(os.Stdin)
+
+// Read returns the passphrase of an existing keystore, and uses prompt when asking for it.
+func Read(file, prompt string) ([]byte, error) {
+	if file != "" {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+			data = data[:i]
+		}
+		if len(data) == 0 {
+			return nil, fmt.Errorf("%s: empty passphrase", file)
+		}
+		return data, nil
+	}
+	if env := os.Getenv(EnvVar); env != "" {
+		return []byte(env), nil
+	}
+	return ask(prompt)
+}
+
+// New returns the passphrase for a new keystore.
+// When it is typed in, it is asked twice, and both must be the same.
+func New(file, prompt string) ([]byte, error) {
+	if file != "" || os.Getenv(EnvVar) != "" {
+		return Read(file, prompt)
+	}
+	p, err := ask(prompt)
+	if err != nil {
+		return nil, err
+	}
+	confirm, err := ask("Repeat " + prompt)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(p, confirm) {
+		return nil, errors.New("passphrases do not match")
+	}
+	return p, nil
+}
+
+func ask(prompt string) ([]byte, error) {
+	fmt.Fprintf(os.Stderr, "%s: ", prompt)
+	line, err := readLine()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if len(line) == 0 {
+		return nil, errors.New("empty passphrase")
+	}
+	return line, nil
+}
+
+// readLine reads a line from the standard input without echo if it is a terminal,
+// and otherwise reads it as is, so that the passphrase can be piped.
+func readLine() ([]byte, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		line, err := term.ReadPassword(fd)
+		// The newline typed by the user was not echoed.
+		fmt.Fprintln(os.Stderr)
+		return line, err
+	}
+	line, err := stdin.ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return nil, err
+	}
+	return bytes.TrimRight(line, "\r\n"), nil
+}
@@ -1,143 +1,176 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
-	"strconv"
+	"time"
 
-	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/files"
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/network"
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/passphrase"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost"
-	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
-	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/keystore"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
 	"github.com/taurusgroup/frost-ed25519/pkg/state"
+	"github.com/taurusgroup/frost-ed25519/pkg/transport"
 )
 
-const maxN = 100
-
 func usage() {
 	cmd := filepath.Base(os.Args[0])
-	fmt.Printf("usage: %v t n\nwhere 0 < t < n < %v\n", cmd, maxN)
+	fmt.Printf(`usage: %[1]v run -id ID -t T -peers FILE -session HEX [-out DIR] [-passphrase FILE] [-timeout D] TRANSPORT
+       %[1]v mailbox-key FILE
+
+Each party runs its own instance of the key generation, and writes its secret share to
+the keystore DIR/keystore-ID.json, encrypted with a passphrase, and the public keys of
+all parties to DIR/public.json. Neither file may exist yet, so that an existing share
+is never replaced.
+The passphrase is read from the first line of the -passphrase FILE, from the %[2]v
+environment variable, or from the standard input.
+
+TRANSPORT is one of
+%[3]v
+The peers FILE contains an entry for every party, including this one:
+  {
+    "1": {"address": "host1:7000", "name": "party1.example.com", "mailbox": "<public mailbox key>"},
+    ...
+  }
+Only the fields used by the TRANSPORT are required.
+
+mailbox-key creates a new mailbox key in FILE, and prints its public key for the peers file.
+`, cmd, passphrase.EnvVar, network.TransportUsage)
 }
 
 func main() {
-	if len(os.Args) != 3 {
+	if len(os.Args) < 2 {
 		usage()
-		return
+		os.Exit(2)
 	}
 
 	var err error
-	var t int
-	var n int
-
-	t, err = strconv.Atoi(os.Args[1])
-	if err != nil {
-		fmt.Println(err)
+	switch os.Args[1] {
+	case "run":
+		err = run(os.Args[2:])
+	case "mailbox-key":
+		if len(os.Args) != 3 {
+			usage()
+			os.Exit(2)
+		}
+		err = newMailboxKey(os.Args[2])
+	default:
 		usage()
-		return
+		os.Exit(2)
 	}
-	n, err = strconv.Atoi(os.Args[2])
 	if err != nil {
 		fmt.Println(err)
-		usage()
-		return
+		os.Exit(1)
 	}
-	if (n > maxN) || (t >= n) {
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	flags.Usage = usage
+	id := flags.Uint("id", 0, "ID of this party")
+	threshold := flags.Uint("t", 0, "threshold, such that t+1 parties are required to sign")
+	peersFile := flags.String("peers", "", "file containing the peers of all parties")
+	sessionHex := flags.String("session", "", "hexadecimal ID of this execution, which must be the same for all parties and never reused")
+	out := flags.String("out", ".", "directory where the output is written")
+	passphraseFile := flags.String("passphrase", "", "file containing the passphrase of the keystore")
+	timeout := flags.Duration("timeout", 10*time.Minute, "abort when no message was received for this long, or 0 to wait indefinitely")
+	var t network.Transport
+	t.RegisterFlags(flags)
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 {
 		usage()
-		return
+		os.Exit(2)
 	}
 
-	partyIDs := helpers.GenerateSet(party.ID(n))
-
-	// structure holding parties' state and output
-	states := map[party.ID]*state.State{}
-	outputs := map[party.ID]*keygen.Output{}
-
-	// create a state for each party
-	for _, id := range partyIDs {
-		states[id], outputs[id], err = frost.NewKeygenState(id, partyIDs, party.Size(t), 0)
-		if err != nil {
-			fmt.Println(err)
-			return
-		}
+	selfID := party.ID(*id)
+	if *id == 0 || *id > math.MaxUint16 {
+		return errors.New("a valid -id is required")
 	}
-
-	msgsOut1 := make([][]byte, 0, n)
-	msgsOut2 := make([][]byte, 0, n*(n-1)/2)
-
-	for _, s := range states {
-		msgs1, err := helpers.PartyRoutine(nil, s)
-		if err != nil {
-			fmt.Println(err)
-			return
-		}
-		msgsOut1 = append(msgsOut1, msgs1...)
+	var sessionID messages.SessionID
+	if err := sessionID.UnmarshalText([]byte(*sessionHex)); err != nil {
+		return fmt.Errorf("-session: %w", err)
 	}
 
-	for _, s := range states {
-		msgs2, err := helpers.PartyRoutine(msgsOut1, s)
-		if err != nil {
-			fmt.Println(err)
-			return
-		}
-		msgsOut2 = append(msgsOut2, msgs2...)
+	peers, partyIDs, err := network.ReadPeers(*peersFile)
+	if err != nil {
+		return err
+	}
+	if !partyIDs.Contains(selfID) {
+		return fmt.Errorf("%s: no entry for party %d", *peersFile, selfID)
 	}
 
-	for _, s := range states {
-		_, err := helpers.PartyRoutine(msgsOut2, s)
-		if err != nil {
-			fmt.Println(err)
-			return
+	// The output files are checked before the protocol, since it cannot be run a second time with the same session.
+	keystoreFile := filepath.Join(*out, fmt.Sprintf("keystore-%d.json", selfID))
+	publicFile := filepath.Join(*out, "public.json")
+	for _, filename := range []string{keystoreFile, publicFile} {
+		if _, err = os.Stat(filename); err == nil {
+			return fmt.Errorf("%s already exists", filename)
+		} else if !os.IsNotExist(err) {
+			return err
 		}
 	}
 
-	// Get the public data
-	fmt.Println("Group Key:")
-	id0 := partyIDs[0]
-	if err = states[id0].WaitForError(); err != nil {
-		fmt.Println(err)
-		return
+	// The passphrase is obtained first, for the same reason.
+	p, err := passphrase.New(*passphraseFile, "Keystore passphrase")
+	if err != nil {
+		return err
 	}
-	public := outputs[id0].Public
-	secrets := make(map[party.ID]*eddsa.SecretShare, n)
-	groupKey := public.GroupKey
-	fmt.Printf("  %x\n\n", groupKey.ToEd25519())
 
-	for _, id := range partyIDs {
-		if err := states[id].WaitForError(); err != nil {
-			fmt.Println(err)
-			return
-		}
-		shareSecret := outputs[id].SecretKey
-		sharePublic := public.Shares[id]
-		secrets[id] = shareSecret
-		fmt.Printf("Party %d:\n  secret: %x\n  public: %x\n", id, shareSecret.Secret.Bytes(), sharePublic.Bytes())
+	comm, err := t.Open(selfID, sessionID, peers, *timeout)
+	if err != nil {
+		return err
 	}
+	defer comm.Done()
 
-	// TODO: write JSON file, to take as input by CLI signer
-	type KeyGenOutput struct {
-		Secrets map[party.ID]*eddsa.SecretShare
-		Shares  *eddsa.Public
+	if err = os.MkdirAll(*out, 0755); err != nil {
+		return err
 	}
 
-	kgOutput := KeyGenOutput{
-		Secrets: secrets,
-		Shares:  public,
+	s, output, err := frost.NewKeygenState(context.Background(), selfID, partyIDs, party.Size(*threshold), comm.Timeout(),
+		state.WithSessionID(sessionID))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("party %d: waiting for %d other parties\n", selfID, partyIDs.N()-1)
+	if err = transport.Run(s, comm); err != nil {
+		return err
 	}
 
-	var jsonData []byte
-	jsonData, err = json.MarshalIndent(kgOutput, "", " ")
+	keystoreData, err := keystore.Seal(output.SecretKey, output.Public, p)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
+	}
+	publicData, err := json.MarshalIndent(output.Public, "", " ")
+	if err != nil {
+		return err
+	}
+	if err = files.WriteNew(keystoreFile, keystoreData, 0600); err != nil {
+		return err
+	}
+	if err = files.WriteNew(publicFile, publicData, 0644); err != nil {
+		return err
 	}
 
-	filename := "keygenout.json"
-
-	_ = ioutil.WriteFile(filename, jsonData, 0644)
+	fmt.Printf("Group Key:\n  %x\n", output.Public.GroupKey.ToEd25519())
+	fmt.Printf("Success: secret share written to %v, public keys to %v\n", keystoreFile, publicFile)
+	return nil
+}
 
-	fmt.Printf("Success: output written to %v\n", filename)
+func newMailboxKey(filename string) error {
+	public, err := network.NewMailboxKey(filename)
+	if err != nil {
+		return err
+	}
+	publicText, _ := public.MarshalText()
+	fmt.Printf("Public mailbox key:\n  %s\n", publicText)
+	return nil
 }
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/files"
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/passphrase"
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/keystore"
+)
+
+func usage() {
+	cmd := filepath.Base(os.Args[0])
+	fmt.Printf(`usage: %[1]v create -share FILE -public FILE -out FILE [-passphrase FILE]
+       %[1]v open [-passphrase FILE] [-public FILE] KEYSTORE
+       %[1]v passwd [-passphrase FILE] [-new-passphrase FILE] KEYSTORE
+       %[1]v list KEYSTORE|DIR...
+
+create  encrypts a plaintext secret share and the public keys of its group into a new keystore.
+open    decrypts a keystore, checks its content, and prints its metadata.
+        The public keys of the group can be written to a file with -public.
+passwd  changes the passphrase of a keystore.
+list    prints the metadata of keystores, or of all keystores in a directory, without decrypting them.
+
+Passphrases are read from the first line of the given FILE, from the %[2]v
+environment variable, or from the standard input.
+`, cmd, passphrase.EnvVar)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "create":
+		err = create(os.Args[2:])
+	case "open":
+		err = open(os.Args[2:])
+	case "passwd":
+		err = passwd(os.Args[2:])
+	case "list":
+		err = list(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func create(args []string) error {
+	flags := flag.NewFlagSet("create", flag.ExitOnError)
+	flags.Usage = usage
+	shareFile := flags.String("share", "", "plaintext secret share")
+	publicFile := flags.String("public", "", "public keys of all parties")
+	out := flags.String("out", "", "file where the keystore is written")
+	passphraseFile := flags.String("passphrase", "", "file containing the passphrase")
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 || *out == "" {
+		usage()
+		os.Exit(2)
+	}
+
+	var secret eddsa.SecretShare
+	if err := readJSON(*shareFile, &secret); err != nil {
+		return err
+	}
+	var public eddsa.Public
+	if err := readJSON(*publicFile, &public); err != nil {
+		return err
+	}
+	p, err := passphrase.New(*passphraseFile, "Passphrase")
+	if err != nil {
+		return err
+	}
+	data, err := keystore.Seal(&secret, &public, p)
+	if err != nil {
+		return err
+	}
+	if err = files.WriteNew(*out, data, 0600); err != nil {
+		return err
+	}
+
+	fmt.Printf("Success: keystore of party %d written to %v\n", secret.ID, *out)
+	fmt.Printf("The plaintext share %v can now be deleted.\n", *shareFile)
+	return nil
+}
+
+func open(args []string) error {
+	flags := flag.NewFlagSet("open", flag.ExitOnError)
+	flags.Usage = usage
+	passphraseFile := flags.String("passphrase", "", "file containing the passphrase")
+	publicFile := flags.String("public", "", "file where the public keys of the group are written")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	data, err := ioutil.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	p, err := passphrase.Read(*passphraseFile, "Passphrase")
+	if err != nil {
+		return err
+	}
+	ks, err := keystore.Open(data, p)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Party:      %d\n", ks.Metadata.ID)
+	fmt.Printf("Threshold:  %d of %d parties\n", ks.Metadata.Threshold+1, ks.Public.PartyIDs.N())
+	fmt.Printf("Parties:    %v\n", ks.Public.PartyIDs)
+	fmt.Printf("Group Key:  %x\n", ks.Metadata.GroupKey.ToEd25519())
+	fmt.Printf("Created:    %v\n", ks.Metadata.Created.Format(time.RFC3339))
+
+	if *publicFile != "" {
+		publicData, err := json.MarshalIndent(ks.Public, "", " ")
+		if err != nil {
+			return err
+		}
+		if err = ioutil.WriteFile(*publicFile, publicData, 0644); err != nil {
+			return err
+		}
+		fmt.Printf("Public keys written to %v\n", *publicFile)
+	}
+	return nil
+}
+
+func passwd(args []string) error {
+	flags := flag.NewFlagSet("passwd", flag.ExitOnError)
+	flags.Usage = usage
+	passphraseFile := flags.String("passphrase", "", "file containing the current passphrase")
+	newPassphraseFile := flags.String("new-passphrase", "", "file containing the new passphrase")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+	filename := flags.Arg(0)
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	oldPassphrase, err := passphrase.Read(*passphraseFile, "Current passphrase")
+	if err != nil {
+		return err
+	}
+	if *newPassphraseFile == "" && os.Getenv(passphrase.EnvVar) != "" {
+		return fmt.Errorf("-new-passphrase is required when %s is set", passphrase.EnvVar)
+	}
+	newPassphrase, err := passphrase.New(*newPassphraseFile, "New passphrase")
+	if err != nil {
+		return err
+	}
+	newData, err := keystore.ChangePassphrase(data, oldPassphrase, newPassphrase, keystore.DefaultParams)
+	if err != nil {
+		return err
+	}
+
+	// Replace the keystore atomically, so that it is never lost.
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(newData); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), filename); err != nil {
+		return err
+	}
+
+	fmt.Printf("Success: passphrase of %v changed\n", filename)
+	return nil
+}
+
+func list(args []string) error {
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	var files []string
+	// explicit are the files given as arguments, which must be keystores
+	explicit := map[string]bool{}
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			explicit[arg] = true
+			continue
+		}
+		entries, err := ioutil.ReadDir(arg)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.Mode().IsRegular() && strings.HasSuffix(entry.Name(), ".json") {
+				files = append(files, filepath.Join(arg, entry.Name()))
+			}
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tPARTY\tTHRESHOLD\tGROUP KEY\tCREATED")
+	for _, filename := range files {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		metadata, err := keystore.ReadMetadata(data)
+		if err != nil {
+			if !explicit[filename] {
+				// other files in the directory, such as public.json, are skipped
+				continue
+			}
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%x\t%s\n", filename, metadata.ID, metadata.Threshold,
+			metadata.GroupKey.ToEd25519(), metadata.Created.Format(time.RFC3339))
+	}
+	return w.Flush()
+}
+
+func readJSON(filename string, v interface{}) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	return nil
+}
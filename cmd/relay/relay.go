@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/transport"
+)
+
+func usage() {
+	cmd := filepath.Base(os.Args[0])
+	fmt.Printf("usage: %v [-addr host:port] [-retention duration] [-max-size bytes] [-cert file -key file]\n", cmd)
+	flag.PrintDefaults()
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	retention := flag.Duration("retention", transport.DefaultRetention, "time during which messages are kept")
+	maxSize := flag.Int64("max-size", transport.DefaultRelaySize, "total size of the messages kept in all mailboxes")
+	certFile := flag.String("cert", "", "TLS certificate, if the relay should serve HTTPS")
+	keyFile := flag.String("key", "", "TLS private key")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() != 0 || (*certFile == "") != (*keyFile == "") {
+		usage()
+		os.Exit(2)
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           transport.NewRelay(transport.RelayConfig{Retention: *retention, MaxTotalSize: *maxSize}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	fmt.Printf("relay listening on %v\n", *addr)
+	var err error
+	if *certFile != "" {
+		err = server.ListenAndServeTLS(*certFile, *keyFile)
+	} else {
+		err = server.ListenAndServe()
+	}
+	fmt.Println(err)
+	os.Exit(1)
+}
@@ -1,129 +1,351 @@
 package main
 
 import (
+	"context"
 	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/files"
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/network"
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/passphrase"
 	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
-	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
 	"github.com/taurusgroup/frost-ed25519/pkg/state"
 )
 
 func usage() {
 	cmd := filepath.Base(os.Args[0])
-	fmt.Printf("usage: %v <JSON file> message\n", cmd)
+	fmt.Printf(`usage: %[1]v commit -keystore FILE [-passphrase FILE] -signers ID,ID,... -session HEX -message FILE -nonce FILE -out FILE
+       %[1]v respond -keystore FILE [-passphrase FILE] -nonce FILE [-used DIR] -message FILE -out FILE COMMITMENT...
+       %[1]v aggregate -public FILE -message FILE [-out FILE] COMMITMENT... SHARE...
+
+Each signer runs one step of the signing protocol per invocation, and the resulting
+messages are exchanged as files by any means.
+
+commit     writes the nonce commitment of this party to the -out file, and keeps its secret
+           nonces in the -nonce file, encrypted with a key derived from the secret share.
+respond    reads the commitments of all signers, and writes the signature share of this party
+           to the -out file. The nonce file is destroyed, and its ID is recorded in the -used
+           directory so that a copy of it can never be used again. By default, this is the
+           directory used-nonces/ID-GROUPKEY next to the keystore, which must therefore be
+           the same file for all invocations of respond, wherever the nonce file is.
+aggregate  combines the commitments and signature shares of all signers into an Ed25519
+           signature, which is verified and written to the -out file as 64 raw bytes.
+
+The keystore and public FILEs are the output of cmd/keygen, and -session must be a new
+hexadecimal ID for every signature, shared by all signers.
+The passphrase of the keystore is read from the first line of the -passphrase FILE,
+from the %[2]v environment variable, or from the standard input.
+`, cmd, passphrase.EnvVar)
 }
 
 func main() {
-
-	if len(os.Args) != 3 {
+	if len(os.Args) < 2 {
 		usage()
-		return
+		os.Exit(2)
 	}
 
-	filename := os.Args[1]
-	message := []byte(os.Args[2])
-
 	var err error
-
-	type KeyGenOutput struct {
-		Secrets map[party.ID]*eddsa.SecretShare
-		Shares  *eddsa.Public
+	switch os.Args[1] {
+	case "commit":
+		err = commit(os.Args[2:])
+	case "respond":
+		err = respond(os.Args[2:])
+	case "aggregate":
+		err = aggregate(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
 	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
 
-	var kgOutput KeyGenOutput
+// nonceFile is the content of the file written by commit, and consumed by respond.
+type nonceFile struct {
+	Party       party.ID   `json:"party"`
+	Signers     []party.ID `json:"signers"`
+	MessageHash []byte     `json:"message_hash"`
+	// Snapshot is the suspended state of the protocol, which contains the secret nonces.
+	Snapshot []byte `json:"snapshot"`
+}
+
+func commit(args []string) error {
+	flags := flag.NewFlagSet("commit", flag.ExitOnError)
+	flags.Usage = usage
+	keystoreFile := flags.String("keystore", "", "keystore of this party")
+	passphraseFile := flags.String("passphrase", "", "file containing the passphrase of the keystore")
+	signersList := flags.String("signers", "", "comma separated IDs of the signers, including this party")
+	sessionHex := flags.String("session", "", "hexadecimal ID of this signature, which must be the same for all signers and never reused")
+	messageFile := flags.String("message", "", "file containing the message to sign")
+	nonceName := flags.String("nonce", "", "file where the secret nonces are written")
+	out := flags.String("out", "", "file where the commitment is written")
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 || *nonceName == "" || *out == "" {
+		usage()
+		os.Exit(2)
+	}
 
-	var jsonData []byte
-	jsonData, err = ioutil.ReadFile(filename)
+	ks, err := files.OpenKeystore(*keystoreFile, *passphraseFile)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
+	}
+	secret, public := ks.Secret, ks.Public
+	signers, err := network.ParseSigners(*signersList)
+	if err != nil {
+		return err
+	}
+	var sessionID messages.SessionID
+	if err = sessionID.UnmarshalText([]byte(*sessionHex)); err != nil {
+		return fmt.Errorf("-session: %w", err)
+	}
+	message, err := ioutil.ReadFile(*messageFile)
+	if err != nil {
+		return err
 	}
 
-	err = json.Unmarshal(jsonData, &kgOutput)
+	s, _, err := frost.NewSignState(context.Background(), signers, secret, public, message, 0,
+		state.WithSessionID(sessionID))
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
+	}
+	msgs := s.ProcessAll()
+	if len(msgs) != 1 {
+		return fmt.Errorf("expected one commitment, got %d messages: %v", len(msgs), s.Err())
+	}
+	commitment, err := msgs[0].MarshalBinary()
+	if err != nil {
+		return err
+	}
+	snapshot, err := s.Suspend(nonceKey(secret))
+	if err != nil {
+		return err
 	}
 
-	// get n and t from the keygen output
-	var n party.Size
-	var t party.Size
+	messageHash := sha512.Sum512(message)
+	nonceData, err := json.MarshalIndent(nonceFile{
+		Party:       secret.ID,
+		Signers:     signers,
+		MessageHash: messageHash[:],
+		Snapshot:    snapshot,
+	}, "", " ")
+	if err != nil {
+		return err
+	}
+	if err = files.WriteNew(*nonceName, nonceData, 0600); err != nil {
+		return err
+	}
+	if err = files.WriteNew(*out, commitment, 0644); err != nil {
+		_ = files.Destroy(*nonceName)
+		return err
+	}
 
-	n = kgOutput.Shares.PartyIDs.N()
-	t = kgOutput.Shares.Threshold
+	fmt.Printf("Success: commitment of party %d written to %v, secret nonces to %v\n", secret.ID, *out, *nonceName)
+	return nil
+}
 
-	fmt.Printf("(t, n) = (%v, %v)\n", t, n)
+func respond(args []string) error {
+	flags := flag.NewFlagSet("respond", flag.ExitOnError)
+	flags.Usage = usage
+	keystoreFile := flags.String("keystore", "", "keystore of this party")
+	passphraseFile := flags.String("passphrase", "", "file containing the passphrase of the keystore")
+	nonceName := flags.String("nonce", "", "file written by commit")
+	usedDir := flags.String("used", "", "directory recording the nonces already used (default: used-nonces/ID-GROUPKEY next to the keystore)")
+	messageFile := flags.String("message", "", "file containing the message to sign")
+	out := flags.String("out", "", "file where the signature share is written")
+	_ = flags.Parse(args)
+	if flags.NArg() == 0 || *nonceName == "" || *out == "" {
+		usage()
+		os.Exit(2)
+	}
 
-	partyIDs := helpers.GenerateSet(n)
-	secretShares := kgOutput.Secrets
-	publicShares := kgOutput.Shares
+	ks, err := files.OpenKeystore(*keystoreFile, *passphraseFile)
+	if err != nil {
+		return err
+	}
+	secret := ks.Secret
+	if *usedDir == "" {
+		*usedDir = defaultUsedDir(*keystoreFile, ks.Secret.ID, ks.Public.GroupKey)
+	}
+	message, err := ioutil.ReadFile(*messageFile)
+	if err != nil {
+		return err
+	}
+	commitments, err := readMessages(flags.Args())
+	if err != nil {
+		return err
+	}
 
-	// structure holding parties' state and output
-	states := map[party.ID]*state.State{}
-	outputs := map[party.ID]*sign.Output{}
+	var nonce nonceFile
+	data, err := ioutil.ReadFile(*nonceName)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, &nonce); err != nil {
+		return fmt.Errorf("%s: %w", *nonceName, err)
+	}
+	if nonce.Party != secret.ID {
+		return fmt.Errorf("%s: nonces of party %d, but the share is of party %d", *nonceName, nonce.Party, secret.ID)
+	}
+	messageHash := sha512.Sum512(message)
+	if subtle.ConstantTimeCompare(messageHash[:], nonce.MessageHash) != 1 {
+		return fmt.Errorf("%s: nonces were committed for a different message", *nonceName)
+	}
 
-	msgsOut1 := make([][]byte, 0, n)
-	msgsOut2 := make([][]byte, 0, n)
+	if err = os.MkdirAll(*usedDir, 0700); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, _, err := frost.ResumeSignState(ctx, nonce.Snapshot, nonceKey(secret), state.NewFileGuard(*usedDir), 0)
+	if err != nil {
+		if errors.Is(err, state.ErrAlreadyResumed) {
+			return fmt.Errorf("%s: nonces were already used", *nonceName)
+		}
+		return err
+	}
+	// From now on, the nonces can only be used by s.
+	if err = files.Destroy(*nonceName); err != nil {
+		return err
+	}
 
-	for _, id := range partyIDs {
-		states[id], outputs[id], err = frost.NewSignState(partyIDs, secretShares[id], publicShares, message, 0)
-		if err != nil {
-			fmt.Println()
+	for _, msg := range commitments {
+		if msg.From == secret.ID {
+			continue
+		}
+		if err = s.HandleMessage(msg); err != nil {
+			return err
+		}
+	}
+	msgs := s.ProcessAll()
+	if len(msgs) != 1 {
+		if err = s.Err(); err != nil {
+			return err
 		}
+		return fmt.Errorf("missing commitments from parties %v", s.MissingParties())
+	}
+	share, err := msgs[0].MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err = files.WriteNew(*out, share, 0644); err != nil {
+		return err
 	}
 
-	pk := publicShares.GroupKey
+	fmt.Printf("Success: signature share of party %d written to %v\n", secret.ID, *out)
+	return nil
+}
 
-	for _, s := range states {
-		msgs1, err := helpers.PartyRoutine(nil, s)
-		if err != nil {
-			fmt.Println(err)
-			return
-		}
-		msgsOut1 = append(msgsOut1, msgs1...)
+func aggregate(args []string) error {
+	flags := flag.NewFlagSet("aggregate", flag.ExitOnError)
+	flags.Usage = usage
+	publicFile := flags.String("public", "", "public keys of all parties")
+	messageFile := flags.String("message", "", "file containing the message to sign")
+	out := flags.String("out", "", "file where the signature is written")
+	_ = flags.Parse(args)
+	if flags.NArg() == 0 {
+		usage()
+		os.Exit(2)
 	}
 
-	for _, s := range states {
-		msgs2, err := helpers.PartyRoutine(msgsOut1, s)
-		if err != nil {
-			fmt.Println(err)
-			return
-		}
-		msgsOut2 = append(msgsOut2, msgs2...)
+	public, err := readPublic(*publicFile)
+	if err != nil {
+		return err
+	}
+	message, err := ioutil.ReadFile(*messageFile)
+	if err != nil {
+		return err
+	}
+	msgs, err := readMessages(flags.Args())
+	if err != nil {
+		return err
 	}
 
-	for _, s := range states {
-		_, err := helpers.PartyRoutine(msgsOut2, s)
-		if err != nil {
-			fmt.Println(err)
-			return
+	var commitments, shares []*messages.Message
+	var signers []party.ID
+	for _, msg := range msgs {
+		switch msg.Type {
+		case messages.MessageTypeSign1:
+			commitments = append(commitments, msg)
+			signers = append(signers, msg.From)
+		case messages.MessageTypeSign2:
+			shares = append(shares, msg)
+		default:
+			return fmt.Errorf("unexpected %s message from party %d", msg.Type, msg.From)
 		}
 	}
 
-	id0 := partyIDs[0]
-	sig := outputs[id0].Signature
-	if sig == nil {
-		fmt.Println("null signature")
-		return
+	sig, err := sign.Aggregate(party.NewIDSlice(signers), public, message, commitments, shares)
+	if err != nil {
+		return err
 	}
-
-	if !ed25519.Verify(pk.ToEd25519(), message, sig.ToEd25519()) {
-		fmt.Println("signature verification failed (ed25519)")
-		return
+	sigBytes := sig.ToEd25519()
+	if !ed25519.Verify(public.GroupKey.ToEd25519(), message, sigBytes) {
+		return errors.New("signature verification failed (ed25519)")
+	}
+	if *out != "" {
+		if err = files.WriteNew(*out, sigBytes, 0644); err != nil {
+			return err
+		}
 	}
 
-	if !pk.Verify(message, sig) {
-		fmt.Println("signature verification failed")
-		return
+	fmt.Printf("Success: signature is\n  %x\n", sigBytes)
+	return nil
+}
+
+// nonceKey derives the key encrypting the nonce file from the secret share.
+func nonceKey(secret *eddsa.SecretShare) []byte {
+	h := sha256.New()
+	_, _ = h.Write([]byte("FROST-Ed25519 signer nonce file"))
+	_, _ = h.Write(secret.Secret.Bytes())
+	return h.Sum(nil)
+}
+
+// defaultUsedDir returns the directory recording the nonces used with the share of id for groupKey.
+// It only depends on the keystore, so that copies of a nonce file are detected wherever they are used.
+func defaultUsedDir(keystoreFile string, id party.ID, groupKey *eddsa.PublicKey) string {
+	name := fmt.Sprintf("%d-%x", id, groupKey.ToEd25519())
+	return filepath.Join(filepath.Dir(keystoreFile), "used-nonces", name)
+}
+
+func readPublic(filename string) (*eddsa.Public, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var public eddsa.Public
+	if err = json.Unmarshal(data, &public); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
 	}
+	return &public, nil
+}
 
-	fmt.Printf("Success: signature is\nr: %x\ns: %x\n", sig.R.Bytes(), sig.S.Bytes())
+func readMessages(filenames []string) ([]*messages.Message, error) {
+	msgs := make([]*messages.Message, 0, len(filenames))
+	for _, filename := range filenames {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		var msg messages.Message
+		if err = msg.UnmarshalBinary(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		msgs = append(msgs, &msg)
+	}
+	return msgs, nil
 }
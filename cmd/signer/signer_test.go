@@ -0,0 +1,89 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/passphrase"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/keystore"
+)
+
+// setup writes the keystores of a 2-out-of-2 group and a message to dir,
+// and returns the names of the keystores and the public keys file.
+func setup(t *testing.T, dir string) (map[party.ID]string, string) {
+	partyIDs := helpers.GenerateSet(2)
+	_, secrets := helpers.GenerateSecrets(partyIDs, 1)
+	public := helpers.GeneratePublic(1, secrets)
+	require.NoError(t, os.Setenv(passphrase.EnvVar, "passphrase"))
+
+	keystores := map[party.ID]string{}
+	for _, id := range partyIDs {
+		data, err := keystore.Seal(secrets[id], public, []byte("passphrase"))
+		require.NoError(t, err)
+		keystores[id] = filepath.Join(dir, fmt.Sprintf("keystore-%d.json", id))
+		require.NoError(t, ioutil.WriteFile(keystores[id], data, 0600))
+	}
+	publicData, err := json.Marshal(public)
+	require.NoError(t, err)
+	publicFile := filepath.Join(dir, "public.json")
+	require.NoError(t, ioutil.WriteFile(publicFile, publicData, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "message"), []byte("message"), 0644))
+	return keystores, publicFile
+}
+
+func TestSigner_RespondOnce(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	keystores, publicFile := setup(t, dir)
+	message := filepath.Join(dir, "message")
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	for id, ks := range keystores {
+		require.NoError(t, commit([]string{"-keystore", ks, "-signers", "1,2", "-session", "00112233445566778899aabbccddeeff",
+			"-message", message, "-nonce", path(fmt.Sprintf("nonce-%d", id)), "-out", path(fmt.Sprintf("commitment-%d", id))}))
+	}
+
+	// A copy of the nonce file, such as a backup, is made in another directory.
+	copyDir := path("copy")
+	require.NoError(t, os.Mkdir(copyDir, 0700))
+	nonceData, err := ioutil.ReadFile(path("nonce-1"))
+	require.NoError(t, err)
+	nonceCopy := filepath.Join(copyDir, "nonce-1")
+	require.NoError(t, ioutil.WriteFile(nonceCopy, nonceData, 0600))
+
+	commitments := []string{path("commitment-1"), path("commitment-2")}
+	for id, ks := range keystores {
+		args := []string{"-keystore", ks, "-nonce", path(fmt.Sprintf("nonce-%d", id)), "-message", message,
+			"-out", path(fmt.Sprintf("share-%d", id))}
+		require.NoError(t, respond(append(args, commitments...)))
+	}
+
+	// The nonces were destroyed, and their copy is rejected.
+	_, err = os.Stat(path("nonce-1"))
+	assert.True(t, os.IsNotExist(err))
+	args := []string{"-keystore", keystores[1], "-nonce", nonceCopy, "-message", message, "-out", path("share-again")}
+	err = respond(append(args, commitments...))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nonces were already used")
+	_, err = os.Stat(path("share-again"))
+	assert.True(t, os.IsNotExist(err))
+
+	sigFile := path("signature")
+	require.NoError(t, aggregate(append([]string{"-public", publicFile, "-message", message, "-out", sigFile},
+		append(commitments, path("share-1"), path("share-2"))...)))
+	sig, err := ioutil.ReadFile(sigFile)
+	require.NoError(t, err)
+	public, err := readPublic(publicFile)
+	require.NoError(t, err)
+	assert.True(t, ed25519.Verify(public.GroupKey.ToEd25519(), []byte("message"), sig))
+}
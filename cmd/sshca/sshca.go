@@ -0,0 +1,462 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/files"
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/network"
+	"github.com/taurusgroup/frost-ed25519/cmd/internal/passphrase"
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/signer"
+	"github.com/taurusgroup/frost-ed25519/pkg/keystore"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/sshcert"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func usage() {
+	cmd := filepath.Base(os.Args[0])
+	fmt.Printf(`usage: %[1]v pubkey -public FILE [-comment TEXT]
+       %[1]v request -public FILE -key FILE -id KEYID [-principals NAME,...] [-host] [-serial N]
+               [-validity D] [-O OPTION]... -out FILE
+       %[1]v show FILE
+       %[1]v sign -request FILE -keystore FILE [-passphrase FILE] -signers ID,ID,... -peers FILE
+               -session HEX [-timeout D] TRANSPORT -out FILE
+       %[1]v attach -request FILE -sig FILE -out FILE
+
+%[1]v runs an OpenSSH certificate authority whose key is a FROST group key, so that
+issuing a certificate requires t+1 parties.
+
+pubkey   prints the group key in the authorized_keys format, for the TrustedUserCAKeys file of
+         sshd, or for a @cert-authority line in known_hosts.
+request  writes the certificate of the public key in -key, such as id_ed25519.pub, without its
+         signature. The request FILE is shared with the signers, and is also the message to sign.
+         Certificates are valid for -validity (24h by default, 0 for ever).
+         OPTIONs are those of ssh-keygen: clear, force-command=CMD, source-address=CIDR,...,
+         no-pty, permit-pty, and so on for agent-forwarding, port-forwarding,
+         x11-forwarding and user-rc. User certificates permit all of them by default.
+show     prints the content of a request, or of a -cert.pub file.
+sign     runs the signing protocol with the other signers, who must sign the same request with
+         the same -signers and -session, and writes the certificate to the -out FILE.
+attach   adds a signature produced by cmd/signer with the request as message, and writes the
+         certificate to the -out FILE.
+
+The public FILE is the public.json written by cmd/keygen, or a keystore.
+The passphrase of the keystore is read from the first line of the -passphrase FILE,
+from the %[2]v environment variable, or from the standard input.
+
+TRANSPORT is one of
+%[3]vThe peers FILE is the same as for cmd/keygen.
+`, cmd, passphrase.EnvVar, network.TransportUsage)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "pubkey":
+		err = pubkey(os.Args[2:])
+	case "request":
+		err = request(os.Args[2:])
+	case "show":
+		err = show(os.Args[2:])
+	case "sign":
+		err = sign(os.Args[2:])
+	case "attach":
+		err = attach(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func pubkey(args []string) error {
+	flags := flag.NewFlagSet("pubkey", flag.ExitOnError)
+	flags.Usage = usage
+	publicFile := flags.String("public", "", "public keys of the group, or a keystore")
+	comment := flags.String("comment", "", "comment added after the key")
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 || *publicFile == "" {
+		usage()
+		os.Exit(2)
+	}
+
+	groupKey, err := readGroupKey(*publicFile)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(sshcert.MarshalAuthorizedKey(groupKey, *comment)))
+	return nil
+}
+
+// stringList is a flag which can be given several times.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func request(args []string) error {
+	flags := flag.NewFlagSet("request", flag.ExitOnError)
+	flags.Usage = usage
+	publicFile := flags.String("public", "", "public keys of the group, or a keystore")
+	keyFile := flags.String("key", "", "public key to certify, in the authorized_keys format")
+	keyID := flags.String("id", "", "key ID, which is logged by sshd")
+	principals := flags.String("principals", "", "comma separated user or host names, or any if empty")
+	host := flags.Bool("host", false, "create a host certificate instead of a user certificate")
+	serial := flags.Uint64("serial", 0, "serial number of the certificate")
+	validity := flags.Duration("validity", 24*time.Hour, "validity period of the certificate, or 0 to never expire")
+	var options stringList
+	flags.Var(&options, "O", "certificate option, may be repeated")
+	out := flags.String("out", "", "file where the request is written")
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 || *publicFile == "" || *keyFile == "" || *keyID == "" || *out == "" {
+		usage()
+		os.Exit(2)
+	}
+
+	groupKey, err := readGroupKey(*publicFile)
+	if err != nil {
+		return err
+	}
+	keyData, err := ioutil.ReadFile(*keyFile)
+	if err != nil {
+		return err
+	}
+	_, key, _, err := sshcert.ParseAuthorizedKey(keyData)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *keyFile, err)
+	}
+
+	cert := &sshcert.Certificate{
+		Key:             key,
+		Serial:          *serial,
+		CertType:        sshcert.UserCert,
+		KeyID:           *keyID,
+		CriticalOptions: map[string]string{},
+		Extensions: map[string]string{
+			"permit-X11-forwarding":   "",
+			"permit-agent-forwarding": "",
+			"permit-port-forwarding":  "",
+			"permit-pty":              "",
+			"permit-user-rc":          "",
+		},
+		ValidAfter:   0,
+		ValidBefore:  sshcert.CertTimeInfinity,
+		SignatureKey: sshcert.MarshalPublicKey(groupKey),
+	}
+	if *host {
+		cert.CertType = sshcert.HostCert
+		cert.Extensions = map[string]string{}
+	}
+	if *principals != "" {
+		cert.ValidPrincipals = strings.Split(*principals, ",")
+	}
+	if *validity != 0 {
+		// Allow for clock skew between the CA and the servers.
+		now := time.Now()
+		cert.ValidAfter = uint64(now.Add(-5 * time.Minute).Unix())
+		cert.ValidBefore = uint64(now.Add(*validity).Unix())
+	}
+	for _, option := range options {
+		if err = applyOption(cert, option); err != nil {
+			return err
+		}
+	}
+	if *host && len(cert.CriticalOptions)+len(cert.Extensions) > 0 {
+		return errors.New("-O: host certificates have no options")
+	}
+	// The nonce is set here since the signature is computed by other processes.
+	cert.Nonce = make([]byte, 32)
+	if _, err = rand.Read(cert.Nonce); err != nil {
+		return err
+	}
+
+	data, err := cert.BytesForSigning()
+	if err != nil {
+		return err
+	}
+	if err = files.WriteNew(*out, data, 0644); err != nil {
+		return err
+	}
+	printCertificate(cert)
+	fmt.Printf("Success: request written to %v\n", *out)
+	return nil
+}
+
+// applyOption applies an option of ssh-keygen -O to cert.
+func applyOption(cert *sshcert.Certificate, option string) error {
+	name, value := option, ""
+	if i := strings.IndexByte(option, '='); i >= 0 {
+		name, value = option[:i], option[i+1:]
+	}
+	extension := func(s string) string {
+		if s == "x11-forwarding" {
+			return "permit-X11-forwarding"
+		}
+		return "permit-" + s
+	}
+	switch {
+	case name == "clear":
+		cert.Extensions = map[string]string{}
+	case name == "force-command" || name == "source-address":
+		if value == "" {
+			return fmt.Errorf("-O %s: a value is required", name)
+		}
+		cert.CriticalOptions[name] = value
+	case strings.HasPrefix(name, "no-"):
+		delete(cert.Extensions, extension(strings.TrimPrefix(name, "no-")))
+	case strings.HasPrefix(name, "permit-"):
+		cert.Extensions[extension(strings.TrimPrefix(name, "permit-"))] = ""
+	default:
+		return fmt.Errorf("-O: unknown option %q", option)
+	}
+	return nil
+}
+
+func show(args []string) error {
+	if len(args) != 1 {
+		usage()
+		os.Exit(2)
+	}
+	cert, err := readCertificate(args[0])
+	if err != nil {
+		return err
+	}
+	printCertificate(cert)
+	if len(cert.Signature) == 0 {
+		fmt.Println("Signature:   none (request)")
+		return nil
+	}
+	if err = cert.Verify(); err != nil {
+		return err
+	}
+	fmt.Println("Signature:   valid")
+	return nil
+}
+
+func sign(args []string) error {
+	flags := flag.NewFlagSet("sign", flag.ExitOnError)
+	flags.Usage = usage
+	requestFile := flags.String("request", "", "certificate request written by the request command")
+	keystoreFile := flags.String("keystore", "", "keystore of this party")
+	passphraseFile := flags.String("passphrase", "", "file containing the passphrase of the keystore")
+	signersList := flags.String("signers", "", "comma separated IDs of the signers, including this party")
+	peersFile := flags.String("peers", "", "file containing the peers of all parties")
+	sessionHex := flags.String("session", "", "hexadecimal ID of this signature, which must be the same for all signers and never reused")
+	timeout := flags.Duration("timeout", 10*time.Minute, "abort when no message was received for this long, or 0 to wait indefinitely")
+	var t network.Transport
+	t.RegisterFlags(flags)
+	out := flags.String("out", "", "file where the certificate is written")
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 || *out == "" {
+		usage()
+		os.Exit(2)
+	}
+
+	cert, err := readCertificate(*requestFile)
+	if err != nil {
+		return err
+	}
+	if len(cert.Signature) != 0 {
+		return fmt.Errorf("%s: the certificate is already signed", *requestFile)
+	}
+	signers, err := network.ParseSigners(*signersList)
+	if err != nil {
+		return err
+	}
+	var sessionID messages.SessionID
+	if err = sessionID.UnmarshalText([]byte(*sessionHex)); err != nil {
+		return fmt.Errorf("-session: %w", err)
+	}
+	peers, _, err := network.ReadPeers(*peersFile)
+	if err != nil {
+		return err
+	}
+	ks, err := files.OpenKeystore(*keystoreFile, *passphraseFile)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(cert.SignatureKey, sshcert.MarshalPublicKey(ks.Public.GroupKey)) {
+		return fmt.Errorf("%s: the request is for another CA key", *requestFile)
+	}
+	message, err := cert.BytesForSigning()
+	if err != nil {
+		return err
+	}
+
+	printCertificate(cert)
+	comm, err := t.Open(ks.Secret.ID, sessionID, peers, *timeout)
+	if err != nil {
+		return err
+	}
+	defer comm.Done()
+	s, err := signer.New(signers, ks.Secret, ks.Public, comm, state.WithSessionID(sessionID))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("party %d: waiting for %d other signers\n", ks.Secret.ID, signers.N()-1)
+	sig, err := s.Sign(nil, message, crypto.Hash(0))
+	if err != nil {
+		return err
+	}
+	return writeCertificate(cert, sig, *out)
+}
+
+func attach(args []string) error {
+	flags := flag.NewFlagSet("attach", flag.ExitOnError)
+	flags.Usage = usage
+	requestFile := flags.String("request", "", "certificate request written by the request command")
+	sigFile := flags.String("sig", "", "Ed25519 signature of the request")
+	out := flags.String("out", "", "file where the certificate is written")
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 || *sigFile == "" || *out == "" {
+		usage()
+		os.Exit(2)
+	}
+
+	cert, err := readCertificate(*requestFile)
+	if err != nil {
+		return err
+	}
+	sig, err := ioutil.ReadFile(*sigFile)
+	if err != nil {
+		return err
+	}
+	return writeCertificate(cert, sig, *out)
+}
+
+// writeCertificate sets the signature of cert, which is verified, and writes it to filename.
+func writeCertificate(cert *sshcert.Certificate, sig []byte, filename string) error {
+	if err := cert.SetSignature(sig); err != nil {
+		return err
+	}
+	data, err := cert.MarshalAuthorizedKey(cert.KeyID)
+	if err != nil {
+		return err
+	}
+	if err = files.WriteNew(filename, data, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Success: certificate written to %v\n", filename)
+	return nil
+}
+
+// readCertificate reads a request, or a certificate in the -cert.pub format.
+func readCertificate(filename string) (*sshcert.Certificate, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := sshcert.ParseCertificate(data)
+	if err != nil {
+		var certErr error
+		if cert, _, certErr = sshcert.ParseCertificateAuthorizedKey(data); certErr != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+	}
+	return cert, nil
+}
+
+func printCertificate(cert *sshcert.Certificate) {
+	algo, _ := cert.Algo()
+	certType := "user"
+	if cert.CertType == sshcert.HostCert {
+		certType = "host"
+	}
+	fmt.Printf("Type:        %v %v certificate\n", algo, certType)
+	fmt.Printf("Key ID:      %q\n", cert.KeyID)
+	fmt.Printf("Serial:      %d\n", cert.Serial)
+	if len(cert.ValidPrincipals) == 0 {
+		fmt.Println("Principals:  any")
+	} else {
+		fmt.Printf("Principals:  %v\n", strings.Join(cert.ValidPrincipals, ", "))
+	}
+	if cert.ValidAfter == 0 && cert.ValidBefore == sshcert.CertTimeInfinity {
+		fmt.Println("Valid:       forever")
+	} else {
+		fmt.Printf("Valid:       from %v to %v\n", formatTime(cert.ValidAfter), formatTime(cert.ValidBefore))
+	}
+	fmt.Printf("Options:     %v\n", formatOptions(cert.CriticalOptions))
+	fmt.Printf("Extensions:  %v\n", formatOptions(cert.Extensions))
+	fmt.Printf("Key:         %v\n", strings.TrimSpace(string(authorizedKey(cert.Key))))
+	fmt.Printf("CA Key:      %v\n", strings.TrimSpace(string(authorizedKey(cert.SignatureKey))))
+}
+
+func formatTime(t uint64) string {
+	if t > math.MaxInt64 {
+		return "forever"
+	}
+	return time.Unix(int64(t), 0).UTC().Format(time.RFC3339)
+}
+
+func formatOptions(options map[string]string) string {
+	if len(options) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(options))
+	for name, value := range options {
+		if value != "" {
+			name += "=" + strconv.Quote(value)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, " ")
+}
+
+// authorizedKey returns the wire encoding of a key in the authorized_keys format.
+func authorizedKey(blob []byte) []byte {
+	if len(blob) < 4 {
+		return nil
+	}
+	n := int(blob[0])<<24 | int(blob[1])<<16 | int(blob[2])<<8 | int(blob[3])
+	if n > len(blob)-4 {
+		return nil
+	}
+	return []byte(fmt.Sprintf("%s %s", blob[4:4+n], base64.StdEncoding.EncodeToString(blob)))
+}
+
+// readGroupKey returns the group key in a public keys file or a keystore.
+func readGroupKey(filename string) (*eddsa.PublicKey, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if metadata, err := keystore.ReadMetadata(data); err == nil {
+		return metadata.GroupKey, nil
+	}
+	var public eddsa.Public
+	if err = json.Unmarshal(data, &public); err != nil {
+		return nil, fmt.Errorf("%s: neither a public keys file nor a keystore: %w", filename, err)
+	}
+	return public.GroupKey, nil
+}
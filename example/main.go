@@ -4,6 +4,7 @@ This file is not intended to be run by itself and serves mostly as an example of
 package example
 
 import (
+	"context"
 	"crypto/ed25519"
 	"log"
 	"time"
@@ -52,7 +53,11 @@ func main() {
 	threshold := party.Size(2)
 	set := party.NewIDSlice([]party.ID{selfID, 2, 42, 8})
 
-	keygenState, keygenOutput, err := frost.NewKeygenState(selfID, set, threshold, 2*time.Second)
+	// The protocols are aborted if they have not finished within a minute
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	keygenState, keygenOutput, err := frost.NewKeygenState(ctx, selfID, set, threshold, 2*time.Second)
 	if err != nil {
 		panic(err)
 	}
@@ -74,7 +79,7 @@ func main() {
 
 	// Get a smaller set of size t+1
 	signers := party.NewIDSlice([]party.ID{selfID, 2, 8})
-	signState, signOutput, err := frost.NewSignState(signers, secretShare, public, message, 1*time.Second)
+	signState, signOutput, err := frost.NewSignState(ctx, signers, secretShare, public, message, 1*time.Second)
 	if err != nil {
 		panic(err)
 	}
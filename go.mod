@@ -5,4 +5,7 @@ go 1.14
 require (
 	filippo.io/edwards25519 v1.0.0-rc.1
 	github.com/stretchr/testify v1.6.1
+	golang.org/x/crypto v0.0.0-20211117183948-ae814b36b871
+	golang.org/x/sys v0.7.0 // indirect
+	golang.org/x/term v0.0.0-20201210144234-2321bbc49cbf
 )
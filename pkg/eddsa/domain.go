@@ -0,0 +1,52 @@
+package eddsa
+
+import (
+	"errors"
+)
+
+// dom2Prefix is the prefix of dom2(x, y) in RFC 8032, Section 5.1.
+const dom2Prefix = "SigEd25519 no Ed25519 collisions"
+
+// MaxContextLength is the maximal length of Domain.Context.
+const MaxContextLength = 255
+
+// Domain selects one of the Ed25519 variants of RFC 8032.
+// The zero Domain is pure Ed25519, which is the variant used by ed25519.Sign.
+type Domain struct {
+	// Prehashed selects Ed25519ph, where the signed message is the SHA-512 digest of the data.
+	Prehashed bool
+
+	// Context is the context string of Ed25519ctx, or of Ed25519ph.
+	// Ed25519ctx is selected when Context is not empty, and Prehashed is false.
+	Context string
+}
+
+// Validate returns an error if the Context is too long.
+func (d Domain) Validate() error {
+	if len(d.Context) > MaxContextLength {
+		return errors.New("eddsa: context is longer than 255 bytes")
+	}
+	return nil
+}
+
+// IsPure returns true if d selects pure Ed25519.
+func (d Domain) IsPure() bool {
+	return !d.Prehashed && d.Context == ""
+}
+
+// prefix returns dom2(phflag, context), which is empty for pure Ed25519.
+func (d Domain) prefix() []byte {
+	if d.IsPure() {
+		return nil
+	}
+	out := make([]byte, 0, len(dom2Prefix)+2+len(d.Context))
+	out = append(out, dom2Prefix...)
+	if d.Prehashed {
+		out = append(out, 1)
+	} else {
+		out = append(out, 0)
+	}
+	out = append(out, byte(len(d.Context)))
+	out = append(out, d.Context...)
+	return out
+}
@@ -0,0 +1,225 @@
+package eddsa
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Format is a standard encoding of Ed25519 public keys and signatures, as used by other libraries.
+// Unlike MarshalBinary and MarshalJSON, which encode ristretto elements, all formats use the ed25519 encoding
+// returned by ToEd25519.
+type Format string
+
+const (
+	// FormatRaw is the 32 byte key, or the 64 byte signature R || S.
+	FormatRaw Format = "raw"
+	// FormatHex is FormatRaw in hexadecimal.
+	FormatHex Format = "hex"
+	// FormatBase64 is FormatRaw in standard base64, with padding.
+	FormatBase64 Format = "base64"
+	// FormatDER is the PKIX SubjectPublicKeyInfo of a key (RFC 8410), in DER.
+	FormatDER Format = "der"
+	// FormatPEM is FormatDER in a PEM block of type "PUBLIC KEY".
+	FormatPEM Format = "pem"
+	// FormatJWK is a JSON Web Key of type OKP (RFC 8037).
+	FormatJWK Format = "jwk"
+	// FormatJWS is a signature in the unpadded base64url encoding of a JWS (RFC 7515).
+	FormatJWS Format = "jws"
+)
+
+var (
+	// PublicKeyFormats are the formats supported by PublicKey.Export and ImportPublicKey.
+	PublicKeyFormats = []Format{FormatRaw, FormatHex, FormatBase64, FormatDER, FormatPEM, FormatJWK}
+	// SignatureFormats are the formats supported by Signature.Export and ImportSignature.
+	SignatureFormats = []Format{FormatRaw, FormatHex, FormatBase64, FormatJWS}
+)
+
+const pemTypePublicKey = "PUBLIC KEY"
+
+// Values of the JWK members of Ed25519 keys, and of the "alg" header of their JWS.
+const (
+	JWKKeyType   = "OKP"
+	JWKCurve     = "Ed25519"
+	JWSAlgorithm = "EdDSA"
+)
+
+var errUnknownFormat = errors.New("unknown format")
+
+// JWK is a JSON Web Key holding an Ed25519 public key.
+type JWK struct {
+	KeyType string `json:"kty"`
+	Curve   string `json:"crv"`
+	// X is the ed25519 encoding of the key, in unpadded base64url.
+	X         string `json:"x"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	// D is the private key, which is rejected by PublicKey.
+	D string `json:"d,omitempty"`
+}
+
+// JWK returns the key as a JSON Web Key, whose "kid" is its Thumbprint.
+func (pk *PublicKey) JWK() *JWK {
+	return &JWK{
+		KeyType:   JWKKeyType,
+		Curve:     JWKCurve,
+		X:         base64.RawURLEncoding.EncodeToString(pk.ToEd25519()),
+		KeyID:     base64.RawURLEncoding.EncodeToString(pk.Thumbprint()),
+		Use:       "sig",
+		Algorithm: JWSAlgorithm,
+	}
+}
+
+// PublicKey returns the key in j, after checking its type and curve.
+func (j *JWK) PublicKey() (*PublicKey, error) {
+	if j.KeyType != JWKKeyType || j.Curve != JWKCurve {
+		return nil, fmt.Errorf("jwk: unsupported key type %q with curve %q", j.KeyType, j.Curve)
+	}
+	if j.D != "" {
+		return nil, errors.New("jwk: the key contains a private key")
+	}
+	if j.Algorithm != "" && j.Algorithm != JWSAlgorithm {
+		return nil, fmt.Errorf("jwk: unsupported algorithm %q", j.Algorithm)
+	}
+	key, err := base64.RawURLEncoding.DecodeString(j.X)
+	if err != nil {
+		return nil, fmt.Errorf("jwk: x: %w", err)
+	}
+	return NewPublicKeyFromEd25519(key)
+}
+
+// Thumbprint returns the SHA-256 JWK thumbprint of the key (RFC 7638),
+// which identifies the key independently of its encoding.
+func (pk *PublicKey) Thumbprint() []byte {
+	// The required members, in lexicographic order and without whitespace.
+	members := fmt.Sprintf(`{"crv":"%s","kty":"%s","x":"%s"}`,
+		JWKCurve, JWKKeyType, base64.RawURLEncoding.EncodeToString(pk.ToEd25519()))
+	digest := sha256.Sum256([]byte(members))
+	return digest[:]
+}
+
+// MarshalPKIX returns the key as a DER encoded PKIX SubjectPublicKeyInfo, as x509.MarshalPKIXPublicKey.
+func (pk *PublicKey) MarshalPKIX() ([]byte, error) {
+	return x509.MarshalPKIXPublicKey(pk.ToEd25519())
+}
+
+// ParsePKIXPublicKey parses a DER encoded PKIX SubjectPublicKeyInfo holding an Ed25519 key.
+func ParsePKIXPublicKey(der []byte) (*PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("pkix: %T is not an Ed25519 key", key)
+	}
+	return NewPublicKeyFromEd25519(edKey)
+}
+
+// Export returns the key in the given format.
+func (pk *PublicKey) Export(format Format) ([]byte, error) {
+	switch format {
+	case FormatRaw:
+		return pk.ToEd25519(), nil
+	case FormatHex:
+		return []byte(hex.EncodeToString(pk.ToEd25519())), nil
+	case FormatBase64:
+		return []byte(base64.StdEncoding.EncodeToString(pk.ToEd25519())), nil
+	case FormatDER:
+		return pk.MarshalPKIX()
+	case FormatPEM:
+		der, err := pk.MarshalPKIX()
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: pemTypePublicKey, Bytes: der}), nil
+	case FormatJWK:
+		return json.Marshal(pk.JWK())
+	}
+	return nil, fmt.Errorf("public key: %w %q", errUnknownFormat, format)
+}
+
+// ImportPublicKey parses a key exported in the given format.
+// Surrounding whitespace is ignored for text formats.
+func ImportPublicKey(data []byte, format Format) (*PublicKey, error) {
+	switch format {
+	case FormatRaw:
+		return NewPublicKeyFromEd25519(data)
+	case FormatHex:
+		key, err := hex.DecodeString(string(bytes.TrimSpace(data)))
+		if err != nil {
+			return nil, err
+		}
+		return NewPublicKeyFromEd25519(key)
+	case FormatBase64:
+		key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+		if err != nil {
+			return nil, err
+		}
+		return NewPublicKeyFromEd25519(key)
+	case FormatDER:
+		return ParsePKIXPublicKey(data)
+	case FormatPEM:
+		block, _ := pem.Decode(data)
+		if block == nil || block.Type != pemTypePublicKey {
+			return nil, errors.New("pem: no PUBLIC KEY block found")
+		}
+		return ParsePKIXPublicKey(block.Bytes)
+	case FormatJWK:
+		var j JWK
+		if err := json.Unmarshal(data, &j); err != nil {
+			return nil, err
+		}
+		return j.PublicKey()
+	}
+	return nil, fmt.Errorf("public key: %w %q", errUnknownFormat, format)
+}
+
+// Export returns the signature in the given format, using the ed25519 encoding returned by ToEd25519.
+func (sig *Signature) Export(format Format) ([]byte, error) {
+	switch format {
+	case FormatRaw:
+		return sig.ToEd25519(), nil
+	case FormatHex:
+		return []byte(hex.EncodeToString(sig.ToEd25519())), nil
+	case FormatBase64:
+		return []byte(base64.StdEncoding.EncodeToString(sig.ToEd25519())), nil
+	case FormatJWS:
+		return []byte(base64.RawURLEncoding.EncodeToString(sig.ToEd25519())), nil
+	}
+	return nil, fmt.Errorf("signature: %w %q", errUnknownFormat, format)
+}
+
+// ImportSignature parses a signature exported in the given format.
+// Surrounding whitespace is ignored for text formats.
+func ImportSignature(data []byte, format Format) (*Signature, error) {
+	var (
+		sig []byte
+		err error
+	)
+	text := string(bytes.TrimSpace(data))
+	switch format {
+	case FormatRaw:
+		sig = data
+	case FormatHex:
+		sig, err = hex.DecodeString(text)
+	case FormatBase64:
+		sig, err = base64.StdEncoding.DecodeString(text)
+	case FormatJWS:
+		sig, err = base64.RawURLEncoding.DecodeString(text)
+	default:
+		return nil, fmt.Errorf("signature: %w %q", errUnknownFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewSignatureFromEd25519(sig)
+}
@@ -0,0 +1,110 @@
+package eddsa
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicKey_Export(t *testing.T) {
+	_, pk, err := generateSignature()
+	require.NoError(t, err)
+
+	for _, format := range PublicKeyFormats {
+		data, err := pk.Export(format)
+		require.NoError(t, err, format)
+		decoded, err := ImportPublicKey(data, format)
+		require.NoError(t, err, format)
+		assert.True(t, pk.Equal(decoded), format)
+	}
+
+	// The PEM and DER formats are those of crypto/x509
+	data, err := pk.Export(FormatPEM)
+	require.NoError(t, err)
+	block, _ := pem.Decode(data)
+	require.NotNil(t, block)
+	assert.Equal(t, "PUBLIC KEY", block.Type)
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, pk.ToEd25519(), key)
+
+	_, err = pk.Export("unknown")
+	assert.Error(t, err)
+	_, err = ImportPublicKey(data, FormatDER)
+	assert.Error(t, err, "PEM is not DER")
+}
+
+func TestJWK(t *testing.T) {
+	// RFC 8037, Appendix A.2 and A.3
+	key, err := hex.DecodeString("d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a")
+	require.NoError(t, err)
+	pk, err := NewPublicKeyFromEd25519(key)
+	require.NoError(t, err)
+
+	jwk := pk.JWK()
+	assert.Equal(t, "OKP", jwk.KeyType)
+	assert.Equal(t, "Ed25519", jwk.Curve)
+	assert.Equal(t, "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo", jwk.X)
+	assert.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", jwk.KeyID)
+
+	decoded, err := ImportPublicKey([]byte(`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`), FormatJWK)
+	require.NoError(t, err)
+	assert.True(t, pk.Equal(decoded))
+
+	for _, invalid := range []string{
+		`{"kty":"OKP","crv":"X25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`,
+		`{"kty":"EC","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`,
+		`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo","alg":"ES256"}`,
+		`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A"}`,
+		`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo="}`,
+	} {
+		_, err = ImportPublicKey([]byte(invalid), FormatJWK)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestNewPublicKeyFromEd25519_Invalid(t *testing.T) {
+	identity := make([]byte, ed25519.PublicKeySize)
+	identity[0] = 1
+	_, err := NewPublicKeyFromEd25519(identity)
+	assert.Error(t, err, "identity")
+
+	// a point of order 8
+	torsion, err := hex.DecodeString("c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a")
+	require.NoError(t, err)
+	_, err = NewPublicKeyFromEd25519(torsion)
+	assert.Error(t, err, "small order")
+
+	_, err = NewPublicKeyFromEd25519(identity[:31])
+	assert.Error(t, err, "short key")
+}
+
+func TestSignature_Export(t *testing.T) {
+	sig, pk, err := generateSignature()
+	require.NoError(t, err)
+
+	for _, format := range SignatureFormats {
+		data, err := sig.Export(format)
+		require.NoError(t, err, format)
+		decoded, err := ImportSignature(data, format)
+		require.NoError(t, err, format)
+		assert.True(t, sig.Equal(decoded), format)
+	}
+
+	data, err := sig.Export(FormatJWS)
+	require.NoError(t, err)
+	raw, err := base64.RawURLEncoding.DecodeString(string(data))
+	require.NoError(t, err)
+	assert.True(t, ed25519.Verify(pk.ToEd25519(), []byte(sampleMessage), raw))
+
+	_, err = sig.Export(FormatPEM)
+	assert.Error(t, err)
+	_, err = ImportSignature(data[:len(data)-4], FormatJWS)
+	assert.Error(t, err)
+}
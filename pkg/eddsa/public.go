@@ -3,6 +3,7 @@ package eddsa
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
@@ -43,7 +44,7 @@ func NewPublic(shares map[party.ID]*ristretto.Element, threshold party.Size) (*P
 	}
 
 	if s.Threshold+1 > s.PartyIDs.N() {
-		return nil, errors.New("PublicShares: Threshold should be < N - 1")
+		return nil, errors.New("PublicShares: Threshold should be < N")
 	}
 
 	return s, nil
@@ -62,6 +63,56 @@ func computeGroupKey(partyIDs party.IDSlice, shares map[party.ID]*ristretto.Elem
 	return NewPublicKeyFromPoint(groupKey)
 }
 
+// Validate checks that the shares are the evaluations of a polynomial of degree Threshold,
+// and that GroupKey is its value at 0.
+// Unlike UnmarshalJSON, which only checks the group key against the interpolation of all the shares,
+// it detects shares which were modified consistently with the group key.
+func (s *Public) Validate() error {
+	n := s.PartyIDs.N()
+	if int(n) != len(s.Shares) {
+		return errors.New("PublicShares: PartyIDs do not match the shares")
+	}
+	if s.Threshold+1 > n {
+		return errors.New("PublicShares: Threshold should be < N")
+	}
+	identity := ristretto.NewIdentityElement()
+	for _, id := range s.PartyIDs {
+		if id == 0 {
+			return errors.New("PublicShares: party ID 0 is not valid")
+		}
+		share, ok := s.Shares[id]
+		if !ok || share == nil {
+			return fmt.Errorf("PublicShares: no share for party %d", id)
+		}
+		if share.Equal(identity) == 1 {
+			return fmt.Errorf("PublicShares: share of party %d is the identity", id)
+		}
+	}
+
+	// The first Threshold+1 shares define the polynomial, the other ones must lie on it.
+	base := s.PartyIDs[:s.Threshold+1]
+	var interpolated, tmp ristretto.Element
+	for _, at := range s.PartyIDs[s.Threshold+1:] {
+		interpolated.Set(identity)
+		for _, id := range base {
+			lagrange, err := id.LagrangeAt(at, base)
+			if err != nil {
+				return err
+			}
+			tmp.ScalarMult(lagrange, s.Shares[id])
+			interpolated.Add(&interpolated, &tmp)
+		}
+		if interpolated.Equal(s.Shares[at]) != 1 {
+			return fmt.Errorf("PublicShares: share of party %d is inconsistent with threshold %d", at, s.Threshold)
+		}
+	}
+
+	if s.GroupKey == nil || !computeGroupKey(base, s.Shares).Equal(s.GroupKey) {
+		return errors.New("PublicShares: inconsistent group key")
+	}
+	return nil
+}
+
 type sharesJSON struct {
 	Threshold int                             `json:"t"`
 	GroupKey  *PublicKey                      `json:"groupkey"`
@@ -3,6 +3,7 @@ package eddsa
 import (
 	"crypto/ed25519"
 	"encoding/json"
+	"errors"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
 )
@@ -19,8 +20,28 @@ func NewPublicKeyFromPoint(public *ristretto.Element) *PublicKey {
 	return &pk
 }
 
+// NewPublicKeyFromEd25519 returns the PublicKey whose ed25519 encoding is key, as returned by ToEd25519.
+// The encoding must be canonical, and the point must be in the prime order subgroup and different from the identity.
+func NewPublicKeyFromEd25519(key ed25519.PublicKey) (*PublicKey, error) {
+	var pk PublicKey
+	if _, err := pk.pk.SetEd25519Bytes(key); err != nil {
+		return nil, err
+	}
+	if pk.pk.Equal(ristretto.NewIdentityElement()) == 1 {
+		return nil, errors.New("eddsa: the public key is the identity")
+	}
+	return &pk, nil
+}
+
+// Verify checks the signature in the ristretto group, which is the group of FROST keys and signatures.
+// To verify arbitrary ed25519 signatures with the rules of a given system, use a VerifyMode.
 func (pk *PublicKey) Verify(message []byte, sig *Signature) bool {
-	challenge := ComputeChallenge(&sig.R, pk, message)
+	return pk.VerifyWithDomain(message, sig, Domain{})
+}
+
+// VerifyWithDomain verifies a signature of the Ed25519 variant selected by domain.
+func (pk *PublicKey) VerifyWithDomain(message []byte, sig *Signature, domain Domain) bool {
+	challenge := ComputeChallengeWithDomain(&sig.R, pk, message, domain)
 
 	// Verify the full signature here too.
 	var publicNeg, RPrime ristretto.Element
@@ -40,6 +61,18 @@ func (pk *PublicKey) ToEd25519() ed25519.PublicKey {
 	return pk.pk.BytesEd25519()
 }
 
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The key is encoded as a ristretto.Element, and not in the Ed25519 format.
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	return pk.pk.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (pk *PublicKey) UnmarshalBinary(data []byte) error {
+	_, err := pk.pk.SetCanonicalBytes(data)
+	return err
+}
+
 // MarshalJSON implements the json.Marshaler interface.
 func (pk PublicKey) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&pk.pk)
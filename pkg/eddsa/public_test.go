@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
 	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
@@ -15,7 +16,7 @@ import (
 func fakeShares(n, t party.Size) (*Public, *ristretto.Scalar) {
 	shares := make(map[party.ID]*ristretto.Element, n)
 	secret := scalar.NewScalarRandom()
-	poly := polynomial.NewPolynomial(t, secret)
+	poly := polynomial.NewPolynomial(t, secret, nil)
 	for i := 0; i < int(n); i++ {
 		id := party.RandID()
 		s := poly.Evaluate(id.Scalar())
@@ -33,7 +34,7 @@ func TestShares_GroupKey(t *testing.T) {
 	shares := make(map[party.ID]*ristretto.Element, N)
 	secret := scalar.NewScalarRandom()
 	public.ScalarBaseMult(secret)
-	poly := polynomial.NewPolynomial(T, secret)
+	poly := polynomial.NewPolynomial(T, secret, nil)
 	for i := 0; i < int(N); i++ {
 		id := party.RandID()
 		s := poly.Evaluate(id.Scalar())
@@ -75,3 +76,47 @@ func TestShares_MarshalJSON(t *testing.T) {
 		t.Error("unmarshalled is not equal")
 	}
 }
+
+func TestPublic_Validate(t *testing.T) {
+	var N, T party.Size = 10, 6
+	shares := make(map[party.ID]*ristretto.Element, N)
+	poly := polynomial.NewPolynomial(T, scalar.NewScalarRandom(), nil)
+	for id := party.ID(1); id <= N; id++ {
+		shares[id] = new(ristretto.Element).ScalarBaseMult(poly.Evaluate(id.Scalar()))
+	}
+	public, err := NewPublic(shares, T)
+	require.NoError(t, err)
+	assert.NoError(t, public.Validate())
+
+	// with a lower threshold, the extra shares are not on a polynomial of degree T-1
+	lower, err := NewPublic(shares, T-1)
+	require.NoError(t, err)
+	assert.Error(t, lower.Validate())
+
+	// with a higher threshold, the group key is the same
+	higher, err := NewPublic(shares, T+1)
+	require.NoError(t, err)
+	assert.NoError(t, higher.Validate())
+
+	// a modified share is detected, even if the group key is recomputed
+	modified := make(map[party.ID]*ristretto.Element, N)
+	for id, share := range shares {
+		modified[id] = share
+	}
+	modified[N] = new(ristretto.Element).Add(shares[N], ristretto.NewGeneratorElement())
+	public, err = NewPublic(modified, T)
+	require.NoError(t, err)
+	assert.Error(t, public.Validate())
+
+	// an inconsistent group key is detected
+	public, err = NewPublic(shares, T)
+	require.NoError(t, err)
+	public.GroupKey = NewPublicKeyFromPoint(ristretto.NewGeneratorElement())
+	assert.Error(t, public.Validate())
+
+	// the identity is not a valid share
+	modified[N] = ristretto.NewIdentityElement()
+	public, err = NewPublic(modified, T)
+	require.NoError(t, err)
+	assert.Error(t, public.Validate())
+}
@@ -28,14 +28,33 @@ func (sig *Signature) ToEd25519() []byte {
 	return out
 }
 
+// NewSignatureFromEd25519 parses a signature in the format returned by ToEd25519.
+// It fails for valid ed25519 signatures whose R has a torsion component,
+// since these cannot be produced by FROST.
+func NewSignatureFromEd25519(sig []byte) (*Signature, error) {
+	var s Signature
+	if len(sig) != MessageLengthSig {
+		return nil, fmt.Errorf("sig: %w", ErrInvalidMessage)
+	}
+	if _, err := s.R.SetEd25519Bytes(sig[:32]); err != nil {
+		return nil, fmt.Errorf("sig.R: %w", err)
+	}
+	if _, err := s.S.SetCanonicalBytes(sig[32:]); err != nil {
+		return nil, fmt.Errorf("sig.S: %w", err)
+	}
+	return &s, nil
+}
+
 // ComputeChallenge computes the value H(R, A, M), and assumes nothing about whether M is hashed.
 func ComputeChallenge(R *ristretto.Element, groupKey *PublicKey, message []byte) *ristretto.Scalar {
+	return ComputeChallengeWithDomain(R, groupKey, message, Domain{})
+}
+
+// ComputeChallengeWithDomain computes the value H(dom2(domain), R, A, M) of the Ed25519 variant selected by domain.
+// For Ed25519ph, M must be the SHA-512 digest of the data.
+func ComputeChallengeWithDomain(R *ristretto.Element, groupKey *PublicKey, message []byte, domain Domain) *ristretto.Scalar {
 	var s ristretto.Scalar
-	data := make([]byte, 0, 64+len(message))
-	data = append(data, R.BytesEd25519()...)
-	data = append(data, groupKey.ToEd25519()...)
-	data = append(data, message...)
-	digest := sha512.Sum512(data)
+	digest := challengeDigest(R.BytesEd25519(), groupKey.ToEd25519(), message, domain)
 	_, err := s.SetUniformBytes(digest[:])
 	if err != nil {
 		panic(err)
@@ -43,6 +62,17 @@ func ComputeChallenge(R *ristretto.Element, groupKey *PublicKey, message []byte)
 	return &s
 }
 
+// challengeDigest returns SHA-512(dom2(domain) || R || A || M), where R and A are Ed25519 encodings.
+func challengeDigest(R, A, message []byte, domain Domain) [64]byte {
+	prefix := domain.prefix()
+	data := make([]byte, 0, len(prefix)+len(R)+len(A)+len(message))
+	data = append(data, prefix...)
+	data = append(data, R...)
+	data = append(data, A...)
+	data = append(data, message...)
+	return sha512.Sum512(data)
+}
+
 //
 // FROSTMarshaler
 //
@@ -45,3 +45,20 @@ func TestSignatureEncode_Decode(t *testing.T) {
 	assert.Equal(t, 1, signature.R.Equal(&signatureOutput.R))
 	assert.Equal(t, 1, signature.S.Equal(&signatureOutput.S))
 }
+
+func TestNewSignatureFromEd25519(t *testing.T) {
+	signature, pk, err := generateSignature()
+	require.NoError(t, err, "failed to generate signature")
+
+	decoded, err := NewSignatureFromEd25519(signature.ToEd25519())
+	require.NoError(t, err)
+	assert.True(t, signature.Equal(decoded))
+
+	decodedPk, err := NewPublicKeyFromEd25519(pk.ToEd25519())
+	require.NoError(t, err)
+	assert.True(t, pk.Equal(decodedPk))
+	assert.True(t, decodedPk.Verify([]byte(sampleMessage), decoded))
+
+	_, err = NewSignatureFromEd25519(signature.ToEd25519()[:63])
+	assert.Error(t, err)
+}
@@ -0,0 +1,133 @@
+package eddsa
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"filippo.io/edwards25519"
+)
+
+// VerifyMode selects the validity rules of Ed25519 signatures, which differ between implementations
+// for signatures which cannot be produced by an honest signer.
+// Consensus systems must choose one, so that all their verifiers agree.
+//
+// Signatures produced by FROST, and more generally by ed25519.Sign, are valid in every mode:
+// the keys and the nonces have no torsion component, and their encodings are canonical.
+// For such keys and signatures, every mode agrees with PublicKey.Verify.
+type VerifyMode int
+
+const (
+	// VerifyStrict follows crypto/ed25519: it checks the cofactorless equation [S]B = R + [k]A
+	// by comparing the canonical encoding of [S]B - [k]A with R.
+	// The encoding of R must therefore be canonical, while A is decoded as by crypto/ed25519,
+	// which accepts non-canonical encodings. Keys of small order are accepted.
+	VerifyStrict VerifyMode = iota
+
+	// VerifyCofactored follows RFC 8032, Section 5.1.7: it checks the cofactored equation [8][S]B = [8]R + [8][k]A.
+	// A and R must be canonical encodings (Section 5.1.3), and keys of small order, for which signatures
+	// can be forged for any message, are rejected.
+	VerifyCofactored
+
+	// VerifyZIP215 follows ZIP 215, as used by Zcash and several consensus systems:
+	// it checks the cofactored equation, and accepts non-canonical encodings of A and R,
+	// as well as keys and nonces of small order, so that batch and single verification agree.
+	VerifyZIP215
+)
+
+// VerifyModes lists all modes.
+var VerifyModes = []VerifyMode{VerifyStrict, VerifyCofactored, VerifyZIP215}
+
+var verifyModeNames = map[VerifyMode]string{
+	VerifyStrict:     "strict",
+	VerifyCofactored: "cofactored",
+	VerifyZIP215:     "zip215",
+}
+
+func (m VerifyMode) String() string {
+	if name, ok := verifyModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("VerifyMode(%d)", int(m))
+}
+
+// ParseVerifyMode returns the mode whose String is name.
+func ParseVerifyMode(name string) (VerifyMode, error) {
+	for _, m := range VerifyModes {
+		if m.String() == name {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("eddsa: unknown verification mode %q", name)
+}
+
+// Verify returns true if sig is a valid pure Ed25519 signature of message by key,
+// according to the rules of m. The key and the signature are in the ed25519 encoding.
+func (m VerifyMode) Verify(key ed25519.PublicKey, message, sig []byte) bool {
+	return m.VerifyWithDomain(key, message, sig, Domain{})
+}
+
+// VerifyWithDomain is Verify for the Ed25519 variant selected by domain.
+func (m VerifyMode) VerifyWithDomain(key ed25519.PublicKey, message, sig []byte, domain Domain) bool {
+	if _, ok := verifyModeNames[m]; !ok {
+		return false
+	}
+	if len(key) != ed25519.PublicKeySize || len(sig) != ed25519.SignatureSize || domain.Validate() != nil {
+		return false
+	}
+
+	// S must be reduced in all modes, which prevents signature malleability.
+	S, err := edwards25519.NewScalar().SetCanonicalBytes(sig[32:])
+	if err != nil {
+		return false
+	}
+	A, err := m.decodePoint(key)
+	if err != nil {
+		return false
+	}
+	if m == VerifyCofactored && isSmallOrder(A) {
+		return false
+	}
+
+	// k is computed from the encodings given by the signer, and not from the decoded points.
+	digest := challengeDigest(sig[:32], key, message, domain)
+	k, err := edwards25519.NewScalar().SetUniformBytes(digest[:])
+	if err != nil {
+		return false
+	}
+
+	// RPrime = [S]B - [k]A
+	minusA := new(edwards25519.Point).Negate(A)
+	RPrime := new(edwards25519.Point).VarTimeDoubleScalarBaseMult(k, minusA, S)
+
+	if m == VerifyStrict {
+		return bytes.Equal(RPrime.Bytes(), sig[:32])
+	}
+	R, err := m.decodePoint(sig[:32])
+	if err != nil {
+		return false
+	}
+	RPrime.Subtract(RPrime, R)
+	return isSmallOrder(RPrime)
+}
+
+// decodePoint decodes a point with the rules of m.
+// All modes accept the encodings of points of the curve with y < p, and a clear sign bit when x = 0.
+// VerifyCofactored rejects the other encodings.
+func (m VerifyMode) decodePoint(in []byte) (*edwards25519.Point, error) {
+	p, err := new(edwards25519.Point).SetBytes(in)
+	if err != nil {
+		return nil, err
+	}
+	if m == VerifyCofactored && !bytes.Equal(p.Bytes(), in) {
+		return nil, errors.New("eddsa: non-canonical point encoding")
+	}
+	return p, nil
+}
+
+// isSmallOrder returns true if [8]p is the identity, including when p is the identity.
+func isSmallOrder(p *edwards25519.Point) bool {
+	var q edwards25519.Point
+	return q.MultByCofactor(p).Equal(edwards25519.NewIdentityPoint()) == 1
+}
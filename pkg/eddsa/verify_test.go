@@ -0,0 +1,171 @@
+package eddsa
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"filippo.io/edwards25519"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+const (
+	// identity is the canonical encoding of the identity point (y = 1).
+	identity = "0100000000000000000000000000000000000000000000000000000000000000"
+	// identityNonCanonical encodes the identity with y = p + 1.
+	identityNonCanonical = "eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f"
+	// identitySignBit encodes the identity with the sign bit of x = 0 set.
+	identitySignBit = "0100000000000000000000000000000000000000000000000000000000000080"
+	// torsion is a point of order 8.
+	torsion = "c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a"
+	zero    = "0000000000000000000000000000000000000000000000000000000000000000"
+)
+
+// verifyVector is a signature with its validity in each VerifyMode.
+type verifyVector struct {
+	name                       string
+	key, message, sig          []byte
+	strict, cofactored, zip215 bool
+}
+
+// scalarFromString returns a scalar derived from s.
+func scalarFromString(s string) *edwards25519.Scalar {
+	digest := sha512.Sum512([]byte(s))
+	x, _ := edwards25519.NewScalar().SetUniformBytes(digest[:])
+	return x
+}
+
+// mixedOrderSignature returns the key [a]B + torsionA, and a signature of message with the nonce [r]B + torsionR.
+// The signature satisfies the cofactored equation, but the cofactorless equation only if both torsion points are the identity.
+func mixedOrderSignature(t *testing.T, r *edwards25519.Scalar, torsionA, torsionR string, message []byte) (key, sig []byte) {
+	return mixedOrderSignatureWithDomain(t, r, torsionA, torsionR, message, Domain{})
+}
+
+func mixedOrderSignatureWithDomain(t *testing.T, r *edwards25519.Scalar, torsionA, torsionR string, message []byte, domain Domain) (key, sig []byte) {
+	a := scalarFromString("mixed order key")
+	TA, err := new(edwards25519.Point).SetBytes(decodeHex(t, torsionA))
+	require.NoError(t, err)
+	TR, err := new(edwards25519.Point).SetBytes(decodeHex(t, torsionR))
+	require.NoError(t, err)
+
+	A := new(edwards25519.Point).ScalarBaseMult(a)
+	key = A.Add(A, TA).Bytes()
+	R := new(edwards25519.Point).ScalarBaseMult(r)
+	RBytes := R.Add(R, TR).Bytes()
+	digest := challengeDigest(RBytes, key, message, domain)
+	k, err := edwards25519.NewScalar().SetUniformBytes(digest[:])
+	require.NoError(t, err)
+	S := edwards25519.NewScalar().MultiplyAdd(k, a, r)
+	return key, append(RBytes, S.Bytes()...)
+}
+
+func verifyVectors(t *testing.T) []verifyVector {
+	// RFC 8032, Section 7.1, TEST 1
+	key1 := decodeHex(t, "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a")
+	sig1 := decodeHex(t, "e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e065224901555fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b")
+
+	// S + L
+	l, _ := new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	s := new(big.Int).SetBytes(reverse(sig1[32:]))
+	sPlusL := s.Add(s, l).Bytes()
+	sigMalleable := append(append([]byte{}, sig1[:32]...), reverse(append(make([]byte, 32-len(sPlusL)), sPlusL...))...)
+
+	r := scalarFromString("mixed order nonce")
+	message := []byte("mixed order")
+	keyMixed, sigMixedR := mixedOrderSignature(t, r, identity, torsion, message)
+	// The cofactorless equation holds for a key of mixed order when k = 0 mod 8, which is not the case for this message.
+	messageMixedA := []byte("mixed order key")
+	keyMixedA, sigMixedA := mixedOrderSignature(t, r, torsion, identity, messageMixedA)
+	_, sigSmallR := mixedOrderSignature(t, edwards25519.NewScalar(), identity, torsion, message)
+	_, sigNormal := mixedOrderSignature(t, r, identity, identity, message)
+
+	frostSig, frostKey, err := generateSignature()
+	require.NoError(t, err)
+
+	return []verifyVector{
+		{"RFC 8032 TEST 1", key1, nil, sig1, true, true, true},
+		{"RFC 8032 TEST 2",
+			decodeHex(t, "3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c"),
+			decodeHex(t, "72"),
+			decodeHex(t, "92a009a9f0d4cab8720e820b5f642540a2b27b5416503f8fb3762223ebdb69da085ac1e43e15996e458f3613d0f11d8c387b2eaeb4302aeeb00d291612bb0c00"),
+			true, true, true},
+		{"FROST", frostKey.ToEd25519(), []byte(sampleMessage), frostSig.ToEd25519(), true, true, true},
+		{"wrong message", key1, []byte("x"), sig1, false, false, false},
+		{"non-canonical S", key1, nil, sigMalleable, false, false, false},
+		{"prime order", keyMixed, message, sigNormal, true, true, true},
+		{"mixed order R", keyMixed, message, sigMixedR, false, true, true},
+		{"small order R", keyMixed, message, sigSmallR, false, true, true},
+		{"mixed order A", keyMixedA, messageMixedA, sigMixedA, false, true, true},
+		// Any signature with S = 0 and R = A = identity satisfies both equations, for any message.
+		{"small order A", decodeHex(t, identity), message, decodeHex(t, identity+zero), true, false, true},
+		{"non-canonical A", decodeHex(t, identityNonCanonical), message, decodeHex(t, identity+zero), true, false, true},
+		{"non-canonical A sign bit", decodeHex(t, identitySignBit), message, decodeHex(t, identity+zero), true, false, true},
+		{"non-canonical R", decodeHex(t, identity), message, decodeHex(t, identityNonCanonical+zero), false, false, true},
+		{"non-canonical R sign bit", decodeHex(t, identity), message, decodeHex(t, identitySignBit+zero), false, false, true},
+		{"short signature", key1, nil, sig1[:63], false, false, false},
+	}
+}
+
+func reverse(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[len(b)-1-i] = b[i]
+	}
+	return out
+}
+
+func TestVerifyMode(t *testing.T) {
+	T, err := new(edwards25519.Point).SetBytes(decodeHex(t, torsion))
+	require.NoError(t, err)
+	require.True(t, isSmallOrder(T))
+	require.NotEqual(t, identity, hex.EncodeToString(T.Bytes()))
+
+	for _, v := range verifyVectors(t) {
+		assert.Equal(t, v.strict, VerifyStrict.Verify(v.key, v.message, v.sig), "%s: strict", v.name)
+		assert.Equal(t, v.cofactored, VerifyCofactored.Verify(v.key, v.message, v.sig), "%s: cofactored", v.name)
+		assert.Equal(t, v.zip215, VerifyZIP215.Verify(v.key, v.message, v.sig), "%s: zip215", v.name)
+
+		// VerifyStrict agrees with crypto/ed25519
+		assert.Equal(t, ed25519.Verify(v.key, v.message, v.sig), VerifyStrict.Verify(v.key, v.message, v.sig), v.name)
+
+		// PublicKey.Verify agrees with all modes when the key and the signature can be parsed
+		pk, errPk := NewPublicKeyFromEd25519(v.key)
+		sig, errSig := NewSignatureFromEd25519(v.sig)
+		if errPk == nil && errSig == nil {
+			assert.True(t, v.strict == v.cofactored && v.strict == v.zip215, v.name)
+			assert.Equal(t, v.strict, pk.Verify(v.message, sig), v.name)
+		}
+	}
+}
+
+func TestVerifyMode_Domain(t *testing.T) {
+	message := []byte(sampleMessage)
+	domain := Domain{Context: "foo"}
+	r := scalarFromString("nonce")
+	key, sig := mixedOrderSignatureWithDomain(t, r, identity, identity, message, domain)
+	keyMixed, sigMixed := mixedOrderSignatureWithDomain(t, r, identity, torsion, message, domain)
+
+	for _, m := range VerifyModes {
+		assert.True(t, m.VerifyWithDomain(key, message, sig, domain), m)
+		assert.False(t, m.VerifyWithDomain(key, message, sig, Domain{}), m)
+		assert.False(t, m.Verify(key, message, sig), m)
+		assert.Equal(t, m != VerifyStrict, m.VerifyWithDomain(keyMixed, message, sigMixed, domain), m)
+
+		parsed, err := ParseVerifyMode(m.String())
+		require.NoError(t, err)
+		assert.Equal(t, m, parsed)
+	}
+
+	_, err := ParseVerifyMode("lenient")
+	assert.Error(t, err)
+	assert.False(t, VerifyMode(42).Verify(key, message, sig))
+}
@@ -0,0 +1,176 @@
+// Package adversary makes a party deviate from the protocol, in order to test that honest parties detect it
+// and identify the culprit.
+//
+// A Mutation modifies the messages generated by the party before they are sent.
+// The adversarial party is otherwise honest, and is created like a regular one:
+//
+//	s, _, err := adversary.NewKeygenState(ctx, id, partyIDs, threshold, timeout, adversary.BadProof())
+//
+// Every honest party which receives a modified message should then abort with a state.Error whose PartyID is id.
+//
+// This package must only be used for testing.
+package adversary
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/zk"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// A Mutation modifies an outgoing message.
+// It is called for every message generated by the adversarial party, and ignores the messages it does not apply to.
+type Mutation func(msg *messages.Message)
+
+// Wrap returns a Round which behaves like round and all its successors,
+// except that the messages they generate are modified by mutations, in order.
+func Wrap(round state.Round, mutations ...Mutation) state.Round {
+	return &adversarialRound{
+		Round:     round,
+		mutations: mutations,
+	}
+}
+
+type adversarialRound struct {
+	state.Round
+	mutations []Mutation
+}
+
+func (round *adversarialRound) GenerateMessages() ([]*messages.Message, *state.Error) {
+	msgs, err := round.Round.GenerateMessages()
+	for _, msg := range msgs {
+		for _, mutate := range round.mutations {
+			mutate(msg)
+		}
+	}
+	return msgs, err
+}
+
+func (round *adversarialRound) NextRound() state.Round {
+	next := round.Round.NextRound()
+	if next == nil {
+		return nil
+	}
+	return Wrap(next, round.mutations...)
+}
+
+// SetRandom forwards the source of randomness to the wrapped round, so that it supports state.WithRandom.
+func (round *adversarialRound) SetRandom(random io.Reader) {
+	if r, ok := round.Round.(state.RandomizedRound); ok {
+		r.SetRandom(random)
+	}
+}
+
+// NewKeygenState is like frost.NewKeygenState, for a party whose messages are modified by mutations.
+func NewKeygenState(ctx context.Context, selfID party.ID, partyIDs party.IDSlice, threshold party.Size, timeout time.Duration, mutations ...Mutation) (*state.State, *keygen.Output, error) {
+	round, output, err := keygen.NewRound(selfID, partyIDs, threshold)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := state.NewBaseState(ctx, Wrap(round, mutations...), timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, output, nil
+}
+
+// NewSignState is like frost.NewSignState, for a party whose messages are modified by mutations.
+func NewSignState(ctx context.Context, partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, message []byte, timeout time.Duration, mutations ...Mutation) (*state.State, *sign.Output, error) {
+	round, output, err := sign.NewRound(partyIDs, secret, shares, message)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := state.NewBaseState(ctx, Wrap(round, mutations...), timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, output, nil
+}
+
+//
+// Keygen
+//
+
+// BadProof invalidates the proof of knowledge of the secret in KeyGen1.
+func BadProof() Mutation {
+	return func(msg *messages.Message) {
+		if body, ok := msg.Payload.(*messages.KeyGen1); ok {
+			body.Proof.R.Add(&body.Proof.R, scalar.NewScalarUInt32(1))
+		}
+	}
+}
+
+// IdentityCommitment replaces the polynomial committed to in KeyGen1 with one whose constant term is 0,
+// together with a valid proof of knowledge of 0.
+func IdentityCommitment() Mutation {
+	return func(msg *messages.Message) {
+		body, ok := msg.Payload.(*messages.KeyGen1)
+		if !ok {
+			return
+		}
+		zero := ristretto.NewScalar()
+		body.Commitments = polynomial.NewPolynomialExponent(polynomial.NewPolynomial(body.Commitments.Degree(), zero, nil))
+		body.Proof = zk.NewSchnorrProof(msg.From, body.Commitments.Constant(), make([]byte, 32), zero, nil)
+	}
+}
+
+// WrongDegree replaces the polynomial committed to in KeyGen1 with one of a higher degree,
+// together with a valid proof of knowledge of its constant term.
+func WrongDegree() Mutation {
+	return func(msg *messages.Message) {
+		body, ok := msg.Payload.(*messages.KeyGen1)
+		if !ok {
+			return
+		}
+		secret := scalar.NewScalarRandom()
+		body.Commitments = polynomial.NewPolynomialExponent(polynomial.NewPolynomial(body.Commitments.Degree()+1, secret, nil))
+		body.Proof = zk.NewSchnorrProof(msg.From, body.Commitments.Constant(), make([]byte, 32), secret, nil)
+	}
+}
+
+// InconsistentShare modifies the KeyGen2 shares sent to victims, or to all parties if none are given,
+// so that they do not match the commitments.
+//
+// Only the victims can detect it, and the other parties complete the protocol.
+func InconsistentShare(victims ...party.ID) Mutation {
+	targets := party.NewIDSlice(victims)
+	return func(msg *messages.Message) {
+		body, ok := msg.Payload.(*messages.KeyGen2)
+		if !ok || (len(targets) > 0 && !targets.Contains(msg.To)) {
+			return
+		}
+		body.Share.Add(&body.Share, scalar.NewScalarUInt32(1))
+	}
+}
+
+//
+// Sign
+//
+
+// IdentityNonce replaces the nonce commitment Di in Sign1 with the identity.
+func IdentityNonce() Mutation {
+	return func(msg *messages.Message) {
+		if body, ok := msg.Payload.(*messages.Sign1); ok {
+			body.Di.Set(ristretto.NewIdentityElement())
+		}
+	}
+}
+
+// WrongSignatureShare modifies the signature share Zi in Sign2.
+func WrongSignatureShare() Mutation {
+	return func(msg *messages.Message) {
+		if body, ok := msg.Payload.(*messages.Sign2); ok {
+			body.Zi.Add(&body.Zi, scalar.NewScalarUInt32(1))
+		}
+	}
+}
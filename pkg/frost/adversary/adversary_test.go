@@ -0,0 +1,127 @@
+package adversary
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+	"github.com/taurusgroup/frost-ed25519/pkg/transport/simnet"
+)
+
+// requireBlamed checks that the honest party id aborted with an error naming culprit, whose message contains reason.
+func requireBlamed(t *testing.T, id, culprit party.ID, s *state.State, reason string) {
+	err := s.Err()
+	require.Error(t, err, "honest party %d did not abort", id)
+	var stateErr *state.Error
+	require.True(t, errors.As(err, &stateErr), "party %d: %v", id, err)
+	assert.Equal(t, culprit, stateErr.PartyID, "party %d blamed the wrong party: %v", id, err)
+	assert.Contains(t, err.Error(), reason, "party %d", id)
+}
+
+func TestKeygen(t *testing.T) {
+	partyIDs := helpers.GenerateSet(5)
+	culprit := partyIDs[2]
+	victim := partyIDs[3]
+
+	tests := []struct {
+		name     string
+		mutation Mutation
+		reason   string
+		// victims are the honest parties expected to abort, all of them if nil
+		victims party.IDSlice
+	}{
+		{"bad proof", BadProof(), "ZK Schnorr failed", nil},
+		{"identity commitment", IdentityCommitment(), "commitment to the secret was the identity", nil},
+		{"wrong degree", WrongDegree(), "wrong degree", nil},
+		{"inconsistent shares", InconsistentShare(), "VSS failed to validate", nil},
+		{"inconsistent share for one party", InconsistentShare(victim), "VSS failed to validate", party.IDSlice{victim}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states := map[party.ID]*state.State{}
+			for _, id := range partyIDs {
+				var err error
+				if id == culprit {
+					states[id], _, err = NewKeygenState(context.Background(), id, partyIDs, 2, 0, tt.mutation)
+				} else {
+					states[id], _, err = frost.NewKeygenState(context.Background(), id, partyIDs, 2, 0)
+				}
+				require.NoError(t, err)
+			}
+			simnet.New(1, simnet.Faults{}).Run(states)
+
+			for _, id := range partyIDs {
+				if id == culprit {
+					continue
+				}
+				if tt.victims == nil || tt.victims.Contains(id) {
+					requireBlamed(t, id, culprit, states[id], tt.reason)
+				} else {
+					assert.NoError(t, states[id].Err(), "party %d", id)
+				}
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	partyIDs := helpers.GenerateSet(5)
+	_, secrets := helpers.GenerateSecrets(partyIDs, 2)
+	public := helpers.GeneratePublic(2, secrets)
+	signIDs := partyIDs[1:4]
+	culprit := signIDs[1]
+	message := []byte("hello")
+
+	tests := []struct {
+		name     string
+		mutation Mutation
+		reason   string
+	}{
+		{"identity nonce", IdentityNonce(), "commitment Ei or Di was the identity"},
+		{"wrong signature share", WrongSignatureShare(), sign.ErrValidateSigShare.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states := map[party.ID]*state.State{}
+			for _, id := range signIDs {
+				var err error
+				if id == culprit {
+					states[id], _, err = NewSignState(context.Background(), signIDs, secrets[id], public, message, 0, tt.mutation)
+				} else {
+					states[id], _, err = frost.NewSignState(context.Background(), signIDs, secrets[id], public, message, 0)
+				}
+				require.NoError(t, err)
+			}
+			simnet.New(1, simnet.Faults{}).Run(states)
+
+			for _, id := range signIDs {
+				if id != culprit {
+					requireBlamed(t, id, culprit, states[id], tt.reason)
+				}
+			}
+		})
+	}
+}
+
+func TestWrap_Honest(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	states := map[party.ID]*state.State{}
+	for _, id := range partyIDs {
+		var err error
+		states[id], _, err = NewKeygenState(context.Background(), id, partyIDs, 1, 0)
+		require.NoError(t, err)
+	}
+	simnet.New(1, simnet.Faults{}).Run(states)
+	for _, s := range states {
+		assert.NoError(t, s.Err())
+	}
+}
@@ -0,0 +1,122 @@
+package batchkeygen
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+var proofDomainSeparation = []byte("FROST-BATCH-KEYGEN")
+
+type (
+	round0 struct {
+		*state.BaseRound
+
+		// Threshold is the degree of the polynomials used for Shamir.
+		// It is the number of tolerated party corruptions.
+		Threshold party.Size
+
+		// BatchSize is the number of independent keys generated during the session.
+		BatchSize int
+
+		// Secrets[k] is first set to the zero coefficient of the k-th polynomial we send to the other parties.
+		// Once all received shares are declared, they are summed here to produce the party's
+		// final secret key for the k-th key.
+		Secrets []ristretto.Scalar
+
+		// Polynomials[k] is used to sample shares of the k-th key
+		Polynomials []*polynomial.Polynomial
+
+		// CommitmentsSum[k] is the sum of all commitments for the k-th key, we use it to compute public key shares
+		CommitmentsSum []*polynomial.Exponent
+
+		// Commitments contains all other parties commitment polynomials, for every key
+		Commitments map[party.ID][]*polynomial.Exponent
+
+		Outputs []*keygen.Output
+	}
+	round1 struct {
+		*round0
+	}
+	round2 struct {
+		*round1
+	}
+)
+
+// NewRound returns the first round of a key generation protocol which generates batchSize independent keys,
+// in the same number of rounds as a single keygen.
+// The k-th Output is filled with the k-th key once the protocol has finished.
+func NewRound(selfID party.ID, partyIDs party.IDSlice, threshold party.Size, batchSize int) (state.Round, []*keygen.Output, error) {
+	N := partyIDs.N()
+
+	if threshold == 0 {
+		return nil, nil, errors.New("threshold must be at least 1, or a minimum of T+1=2 signers")
+	}
+	if threshold > N-1 {
+		return nil, nil, errors.New("threshold must be at most N-1, or a maximum of T+1=N signers")
+	}
+	if batchSize < 1 || batchSize > messages.MaxBatchSize {
+		return nil, nil, errors.New("batch size must be between 1 and messages.MaxBatchSize")
+	}
+
+	baseRound, err := state.NewBaseRound(selfID, partyIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := round0{
+		BaseRound:   baseRound,
+		Threshold:   threshold,
+		BatchSize:   batchSize,
+		Secrets:     make([]ristretto.Scalar, batchSize),
+		Commitments: make(map[party.ID][]*polynomial.Exponent, N),
+		Outputs:     make([]*keygen.Output, batchSize),
+	}
+	for k := range r.Outputs {
+		r.Outputs[k] = &keygen.Output{}
+	}
+
+	return &r, r.Outputs, nil
+}
+
+func (round *round0) Reset() {
+	zero := ristretto.NewScalar()
+	for k := range round.Secrets {
+		round.Secrets[k].Set(zero)
+	}
+	for _, p := range round.Polynomials {
+		p.Reset()
+	}
+	for _, p := range round.CommitmentsSum {
+		p.Reset()
+	}
+	for _, ps := range round.Commitments {
+		for _, p := range ps {
+			p.Reset()
+		}
+	}
+	round.Outputs = nil
+}
+
+// ---
+// Messages
+// ---
+
+func (round *round0) AcceptedMessageTypes() []messages.MessageType {
+	return []messages.MessageType{messages.MessageTypeNone, messages.MessageTypeBatchKeyGen1, messages.MessageTypeBatchKeyGen2}
+}
+
+// proofContext returns the context used for the proof of knowledge of the k-th secret.
+// It binds the proof to its position in the batch, so that it cannot be replayed for another key.
+func proofContext(k int) []byte {
+	ctx := sha256.New()
+	_, _ = ctx.Write(proofDomainSeparation)
+	_, _ = ctx.Write([]byte{byte(k >> 8), byte(k)})
+	return ctx.Sum(nil)
+}
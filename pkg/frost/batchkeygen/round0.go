@@ -0,0 +1,53 @@
+package batchkeygen
+
+import (
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/zk"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func (round *round0) ProcessMessage(*messages.Message) *state.Error {
+	return nil
+}
+
+func (round *round0) GenerateMessages() ([]*messages.Message, *state.Error) {
+	round.Polynomials = make([]*polynomial.Polynomial, round.BatchSize)
+	round.CommitmentsSum = make([]*polynomial.Exponent, round.BatchSize)
+	proofs := make([]*zk.Schnorr, round.BatchSize)
+	commitments := make([]*polynomial.Exponent, round.BatchSize)
+
+	for k := 0; k < round.BatchSize; k++ {
+		secret := &round.Secrets[k]
+
+		// Sample a_i,0 which is the constant factor of the k-th polynomial
+		scalar.SetScalarRandom(secret, round.Random())
+
+		// Sample the remaining coefficients, and obtain a polynomial
+		// of degree t.
+		round.Polynomials[k] = polynomial.NewPolynomial(round.Threshold, secret, round.Random())
+
+		// Generate all commitments [a_{i j}] B for j = 0, 1, ..., t
+		// CommitmentsSum holds the sum of all commitments, so we initialize it to our commitment
+		round.CommitmentsSum[k] = polynomial.NewPolynomialExponent(round.Polynomials[k])
+
+		// The message gets its own copy, since CommitmentsSum is modified in the next round
+		commitments[k] = round.CommitmentsSum[k].Copy()
+
+		// Generate proof of knowledge of a_i,0 = f(0)
+		public := round.CommitmentsSum[k].Constant()
+		proofs[k] = zk.NewSchnorrProof(round.SelfID(), public, proofContext(k), secret, round.Random())
+
+		// We use Secrets[k] to hold the sum of all shares received for the k-th key.
+		// Therefore, we can set it to the share we would send to our selves.
+		secret.Set(round.Polynomials[k].Evaluate(round.SelfID().Scalar()))
+	}
+
+	msg := messages.NewBatchKeyGen1(round.SelfID(), proofs, commitments)
+	return []*messages.Message{msg}, nil
+}
+
+func (round *round0) NextRound() state.Round {
+	return &round1{round}
+}
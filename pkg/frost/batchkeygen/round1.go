@@ -0,0 +1,74 @@
+package batchkeygen
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func (round *round1) ProcessMessage(msg *messages.Message) *state.Error {
+	from := msg.From
+	body, ok := msg.Payload.(*messages.BatchKeyGen1)
+	if !ok {
+		return state.NewError(from, errors.New("invalid message payload"))
+	}
+
+	if len(body.Proofs) != round.BatchSize || len(body.Commitments) != round.BatchSize {
+		return state.NewError(from, errors.New("wrong number of keys in batch"))
+	}
+
+	identity := ristretto.NewIdentityElement()
+	for k := 0; k < round.BatchSize; k++ {
+		if body.Commitments[k].Degree() != round.Threshold {
+			return state.NewError(from, fmt.Errorf("key %d: commitment polynomial has the wrong degree", k))
+		}
+		public := body.Commitments[k].Constant()
+		if public.Equal(identity) == 1 {
+			return state.NewError(from, fmt.Errorf("key %d: commitment to the secret was the identity", k))
+		}
+		if !body.Proofs[k].Verify(from, public, proofContext(k)) {
+			return state.NewError(from, fmt.Errorf("key %d: ZK Schnorr failed", k))
+		}
+	}
+
+	round.Commitments[from] = body.Commitments
+
+	// Add the commitments to our own, so that we can interpolate the final polynomials
+	for k := 0; k < round.BatchSize; k++ {
+		_ = round.CommitmentsSum[k].Add(body.Commitments[k])
+	}
+	return nil
+}
+
+func (round *round1) GenerateMessages() ([]*messages.Message, *state.Error) {
+	msgsOut := make([]*messages.Message, 0, len(round.PartyIDs())-1)
+	for _, id := range round.PartyIDs() {
+		if id == round.SelfID() {
+			continue
+		}
+		shares := make([]ristretto.Scalar, round.BatchSize)
+		for k, p := range round.Polynomials {
+			shares[k].Set(p.Evaluate(id.Scalar()))
+		}
+		msgsOut = append(msgsOut, messages.NewBatchKeyGen2(round.SelfID(), id, shares))
+	}
+
+	// Now that we have received the commitment from every one,
+	// we no longer require the original polynomials, so we reset them
+	for _, p := range round.Polynomials {
+		p.Reset()
+	}
+
+	return msgsOut, nil
+}
+
+func (round *round1) NextRound() state.Round {
+	return &round2{round}
+}
+
+func (round *round1) MessageType() messages.MessageType {
+	return messages.MessageTypeBatchKeyGen1
+}
@@ -0,0 +1,69 @@
+package batchkeygen
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func (round *round2) ProcessMessage(msg *messages.Message) *state.Error {
+	id := msg.From
+	body, ok := msg.Payload.(*messages.BatchKeyGen2)
+	if !ok {
+		return state.NewError(id, errors.New("invalid message payload"))
+	}
+	shares := body.Shares
+
+	if len(shares) != round.BatchSize {
+		return state.NewError(id, errors.New("wrong number of shares in batch"))
+	}
+
+	var computedShareExp ristretto.Element
+	for k := range shares {
+		computedShareExp.ScalarBaseMult(&shares[k])
+		shareExp := round.Commitments[id][k].Evaluate(round.SelfID().Scalar())
+		if computedShareExp.Equal(shareExp) != 1 {
+			return state.NewError(id, fmt.Errorf("key %d: VSS failed to validate", k))
+		}
+	}
+
+	zero := ristretto.NewScalar()
+	for k := range shares {
+		round.Secrets[k].Add(&round.Secrets[k], &shares[k])
+
+		// We can reset the share in the message now
+		shares[k].Set(zero)
+	}
+
+	return nil
+}
+
+func (round *round2) GenerateMessages() ([]*messages.Message, *state.Error) {
+	for k, commitmentsSum := range round.CommitmentsSum {
+		shares := make(map[party.ID]*ristretto.Element, round.PartyIDs().N())
+		for _, id := range round.PartyIDs() {
+			shares[id] = commitmentsSum.Evaluate(id.Scalar())
+		}
+		round.Outputs[k].Public = &eddsa.Public{
+			PartyIDs:  round.BaseRound.PartyIDs().Copy(),
+			Threshold: round.Threshold,
+			Shares:    shares,
+			GroupKey:  eddsa.NewPublicKeyFromPoint(commitmentsSum.Constant()),
+		}
+		round.Outputs[k].SecretKey = eddsa.NewSecretShare(round.SelfID(), &round.Secrets[k])
+	}
+	return nil, nil
+}
+
+func (round *round2) NextRound() state.Round {
+	return nil
+}
+
+func (round *round2) MessageType() messages.MessageType {
+	return messages.MessageTypeBatchKeyGen2
+}
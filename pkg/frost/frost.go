@@ -1,9 +1,12 @@
 package frost
 
 import (
+	"context"
+	"errors"
 	"time"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/batchkeygen"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
@@ -11,31 +14,105 @@ import (
 )
 
 // NewKeygenState returns a state.State which coordinates the multiple rounds.
+// The protocol is aborted when ctx is done, or when no message was received during timeout (if it is not 0).
+// See state.NewBaseState for details.
 // The second parameter is the output of the protocol and will be filled with the output once the protocol has finished executing.
 // It is safe to use the output when State.WaitForError() returns nil.
-func NewKeygenState(selfID party.ID, partyIDs party.IDSlice, threshold party.Size, timeout time.Duration) (*state.State, *keygen.Output, error) {
+func NewKeygenState(ctx context.Context, selfID party.ID, partyIDs party.IDSlice, threshold party.Size, timeout time.Duration, opts ...state.Option) (*state.State, *keygen.Output, error) {
 	round, output, err := keygen.NewRound(selfID, partyIDs, threshold)
 	if err != nil {
 		return nil, nil, err
 	}
-	s, err := state.NewBaseState(round, timeout)
+	s, err := state.NewBaseState(ctx, round, timeout, opts...)
 	if err != nil {
 		return nil, nil, err
 	}
-
-	go func() {}()
 	return s, output, nil
 }
 
+// NewBatchKeygenState returns a state.State which coordinates the multiple rounds of a key generation
+// producing batchSize independent keys for the same set of parties.
+// The number of rounds and messages exchanged does not depend on batchSize.
+// The second parameter contains the outputs of the protocol, one for each key,
+// and will be filled once the protocol has finished executing.
+// It is safe to use the outputs when State.WaitForError() returns nil.
+func NewBatchKeygenState(ctx context.Context, selfID party.ID, partyIDs party.IDSlice, threshold party.Size, batchSize int, timeout time.Duration, opts ...state.Option) (*state.State, []*keygen.Output, error) {
+	round, outputs, err := batchkeygen.NewRound(selfID, partyIDs, threshold, batchSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := state.NewBaseState(ctx, round, timeout, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, outputs, nil
+}
+
 // NewSignState returns a state.State which coordinates the multiple rounds.
+// The protocol is aborted when ctx is done, or when no message was received during timeout (if it is not 0).
+// See state.NewBaseState for details.
 // The second parameter is the output of the protocol and will be filled with the output once the protocol has finished executing.
 // It is safe to use the output when State.WaitForError() returns nil.
-func NewSignState(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, message []byte, timeout time.Duration) (*state.State, *sign.Output, error) {
-	round, output, err := sign.NewRound(partyIDs, secret, shares, message)
+func NewSignState(ctx context.Context, partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, message []byte, timeout time.Duration, opts ...state.Option) (*state.State, *sign.Output, error) {
+	return NewSignStateWithDomain(ctx, partyIDs, secret, shares, message, eddsa.Domain{}, timeout, opts...)
+}
+
+// NewSignStateWithDomain is like NewSignState, but produces a signature of the Ed25519 variant selected by domain,
+// such as Ed25519ph or Ed25519ctx. For Ed25519ph, message must be the SHA-512 digest of the data.
+// All parties must use the same domain.
+func NewSignStateWithDomain(ctx context.Context, partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, message []byte, domain eddsa.Domain, timeout time.Duration, opts ...state.Option) (*state.State, *sign.Output, error) {
+	round, output, err := sign.NewRoundWithDomain(partyIDs, secret, shares, message, domain)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := state.NewBaseState(ctx, round, timeout, opts...)
 	if err != nil {
 		return nil, nil, err
 	}
-	s, _ := state.NewBaseState(round, timeout)
+	return s, output, nil
+}
+
+// ResumeKeygenState continues a keygen protocol from a snapshot obtained with State.Suspend.
+// key must be the same 32 byte key used to create the snapshot.
+// If guard is not nil, it is used to ensure the snapshot is only resumed once.
+func ResumeKeygenState(ctx context.Context, snapshot, key []byte, guard state.ResumeGuard, timeout time.Duration, opts ...state.Option) (*state.State, *keygen.Output, error) {
+	var output *keygen.Output
+	restore := func(roundNumber int, data []byte) (state.Round, error) {
+		var (
+			round state.Round
+			err   error
+		)
+		round, output, err = keygen.ResumeRound(roundNumber, data)
+		return round, err
+	}
+	s, err := state.Resume(ctx, snapshot, key, restore, guard, timeout, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, output, nil
+}
 
+// ResumeSignState continues a sign protocol from a snapshot obtained with State.Suspend.
+// key must be the same 32 byte key used to create the snapshot.
+//
+// The snapshot contains secret nonces, and resuming it twice would leak the secret key share.
+// The guard is therefore mandatory, and must persist across restarts, for example a state.NewFileGuard.
+func ResumeSignState(ctx context.Context, snapshot, key []byte, guard state.ResumeGuard, timeout time.Duration, opts ...state.Option) (*state.State, *sign.Output, error) {
+	if guard == nil {
+		return nil, nil, errors.New("frost.ResumeSignState: a ResumeGuard is required")
+	}
+	var output *sign.Output
+	restore := func(roundNumber int, data []byte) (state.Round, error) {
+		var (
+			round state.Round
+			err   error
+		)
+		round, output, err = sign.ResumeRound(roundNumber, data)
+		return round, err
+	}
+	s, err := state.Resume(ctx, snapshot, key, restore, guard, timeout, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
 	return s, output, nil
 }
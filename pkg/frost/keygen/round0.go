@@ -14,11 +14,11 @@ func (round *round0) ProcessMessage(*messages.Message) *state.Error {
 
 func (round *round0) GenerateMessages() ([]*messages.Message, *state.Error) {
 	// Sample a_i,0 which is the constant factor of the polynomial
-	scalar.SetScalarRandom(&round.Secret)
+	scalar.SetScalarRandom(&round.Secret, round.Random())
 
 	// Sample the remaining coefficients, and obtain a polynomial
 	// of degree t.
-	round.Polynomial = polynomial.NewPolynomial(round.Threshold, &round.Secret)
+	round.Polynomial = polynomial.NewPolynomial(round.Threshold, &round.Secret, round.Random())
 
 	// Generate all commitments [a_{i j}] B for j = 0, 1, ..., t
 	// CommitmentsSum holds the sum of all commitments, so we initialize it to our commitment
@@ -28,7 +28,7 @@ func (round *round0) GenerateMessages() ([]*messages.Message, *state.Error) {
 	ctx := make([]byte, 32)
 	public := round.CommitmentsSum.Constant()
 	// Generate proof of knowledge of a_i,0 = f(0)
-	proof := zk.NewSchnorrProof(round.SelfID(), public, ctx, &round.Secret)
+	proof := zk.NewSchnorrProof(round.SelfID(), public, ctx, &round.Secret, round.Random())
 
 	// We use the variable Secret to hold the sum of all shares received.
 	// Therefore, we can set it to the share we would send to our selves.
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
 	"github.com/taurusgroup/frost-ed25519/pkg/state"
 )
 
@@ -11,16 +12,28 @@ func (round *round1) ProcessMessage(msg *messages.Message) *state.Error {
 	// TODO we can use custom contexts to prevent replay attacks
 	ctx := make([]byte, 32)
 	from := msg.From
+	body, ok := msg.Payload.(*messages.KeyGen1)
+	if !ok {
+		return state.NewError(from, errors.New("invalid message payload"))
+	}
+
+	if body.Commitments.Degree() != round.Threshold {
+		return state.NewError(from, errors.New("commitment polynomial has the wrong degree"))
+	}
 
-	public := msg.KeyGen1.Commitments.Constant()
-	if !msg.KeyGen1.Proof.Verify(from, public, ctx) {
+	// A commitment to 0 would not contribute to the group key
+	public := body.Commitments.Constant()
+	if public.Equal(ristretto.NewIdentityElement()) == 1 {
+		return state.NewError(from, errors.New("commitment to the secret was the identity"))
+	}
+	if !body.Proof.Verify(from, public, ctx) {
 		return state.NewError(from, errors.New("ZK Schnorr failed"))
 	}
 
-	round.Commitments[from] = msg.KeyGen1.Commitments
+	round.Commitments[from] = body.Commitments
 
 	// Add the commitments to our own, so that we can interpolate the final polynomial
-	_ = round.CommitmentsSum.Add(msg.KeyGen1.Commitments)
+	_ = round.CommitmentsSum.Add(body.Commitments)
 	return nil
 }
 
@@ -11,19 +11,24 @@ import (
 )
 
 func (round *round2) ProcessMessage(msg *messages.Message) *state.Error {
+	id := msg.From
+	body, ok := msg.Payload.(*messages.KeyGen2)
+	if !ok {
+		return state.NewError(id, errors.New("invalid message payload"))
+	}
+
 	var computedShareExp ristretto.Element
-	computedShareExp.ScalarBaseMult(&msg.KeyGen2.Share)
+	computedShareExp.ScalarBaseMult(&body.Share)
 
-	id := msg.From
 	shareExp := round.Commitments[id].Evaluate(round.SelfID().Scalar())
 
 	if computedShareExp.Equal(shareExp) != 1 {
 		return state.NewError(id, errors.New("VSS failed to validate"))
 	}
-	round.Secret.Add(&round.Secret, &msg.KeyGen2.Share)
+	round.Secret.Add(&round.Secret, &body.Share)
 
 	// We can reset the share in the message now
-	msg.KeyGen2.Share.Set(ristretto.NewScalar())
+	body.Share.Set(ristretto.NewScalar())
 
 	return nil
 }
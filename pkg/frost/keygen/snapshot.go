@@ -0,0 +1,106 @@
+package keygen
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/wire"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// MarshalState implements state.Resumable.
+func (round *round0) MarshalState() ([]byte, error) {
+	w := wire.NewWriter(nil)
+	w.ID(round.SelfID())
+	w.IDs(round.PartyIDs())
+	w.ID(round.Threshold)
+	w.Scalar(&round.Secret)
+
+	w.Bool(round.Polynomial != nil)
+	if round.Polynomial != nil {
+		data, err := round.Polynomial.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		w.VarBytes(data)
+	}
+
+	w.Bool(round.CommitmentsSum != nil)
+	if round.CommitmentsSum != nil {
+		data, err := round.CommitmentsSum.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		w.VarBytes(data)
+	}
+
+	w.Uint16(uint16(len(round.Commitments)))
+	for _, id := range round.PartyIDs() {
+		commitments, ok := round.Commitments[id]
+		if !ok {
+			continue
+		}
+		data, err := commitments.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		w.ID(id)
+		w.VarBytes(data)
+	}
+	return w.Bytes(), nil
+}
+
+// ResumeRound recreates the keygen round with the given number from the output of MarshalState.
+// It is used as a state.RestoreFunc, and the returned Output will be filled when the resumed protocol finishes.
+func ResumeRound(roundNumber int, data []byte) (state.Round, *Output, error) {
+	r := wire.NewReader(data)
+	selfID := r.ID()
+	partyIDs := r.IDs()
+	threshold := r.ID()
+	if r.Err() != nil {
+		return nil, nil, fmt.Errorf("keygen.ResumeRound: %w", r.Err())
+	}
+
+	round, output, err := NewRound(selfID, partyIDs, threshold)
+	if err != nil {
+		return nil, nil, fmt.Errorf("keygen.ResumeRound: %w", err)
+	}
+	round0 := round.(*round0)
+
+	r.Scalar(&round0.Secret)
+	if r.Bool() {
+		round0.Polynomial = &polynomial.Polynomial{}
+		r.Fail(round0.Polynomial.UnmarshalBinary(r.VarBytes()))
+	}
+	if r.Bool() {
+		round0.CommitmentsSum = &polynomial.Exponent{}
+		r.Fail(round0.CommitmentsSum.UnmarshalBinary(r.VarBytes()))
+	}
+	n := int(r.Uint16())
+	for i := 0; i < n && r.Err() == nil; i++ {
+		id := r.ID()
+		if !partyIDs.Contains(id) {
+			r.Fail(errors.New("commitment from unknown party"))
+		}
+		commitments := &polynomial.Exponent{}
+		r.Fail(commitments.UnmarshalBinary(r.VarBytes()))
+		round0.Commitments[id] = commitments
+	}
+	if err = r.Finish(); err != nil {
+		round0.Reset()
+		return nil, nil, fmt.Errorf("keygen.ResumeRound: %w", err)
+	}
+
+	switch roundNumber {
+	case 0:
+		return round0, output, nil
+	case 1:
+		return &round1{round0}, output, nil
+	case 2:
+		return &round2{&round1{round0}}, output, nil
+	default:
+		round0.Reset()
+		return nil, nil, fmt.Errorf("keygen.ResumeRound: invalid round number %d", roundNumber)
+	}
+}
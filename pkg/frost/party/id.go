@@ -111,10 +111,22 @@ func (id *ID) UnmarshalText(text []byte) error {
 //
 // returns an error if id is not included in partyIDs
 func (id ID) Lagrange(partyIDs IDSlice) (*ristretto.Scalar, error) {
+	return id.LagrangeAt(0, partyIDs)
+}
+
+// LagrangeAt gives the Lagrange coefficient lⱼ(x) for x = at.
+// It is used to interpolate a polynomial at the point of another party.
+//
+//			(x₀ - x) ... (xₖ - x)
+// lⱼ(x) =	---------------------------
+//			(x₀ - xⱼ) ... (xₖ - xⱼ)
+//
+// returns an error if id is not included in partyIDs
+func (id ID) LagrangeAt(at ID, partyIDs IDSlice) (*ristretto.Scalar, error) {
 	if id == 0 {
 		return nil, errors.New("party.ID: Lagrange: id was 0 (invalid)")
 	}
-	var one, num, denum, xM, xJ ristretto.Scalar
+	var one, num, denum, x, xM, xJ ristretto.Scalar
 
 	// we can't use scalar.NewScalarUInt32() since that would cause an import cycle
 	_, _ = one.SetCanonicalBytes([]byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
@@ -122,6 +134,7 @@ func (id ID) Lagrange(partyIDs IDSlice) (*ristretto.Scalar, error) {
 	num.Set(&one)
 	denum.Set(&one)
 
+	x = *at.Scalar()
 	xJ = *id.Scalar()
 
 	foundSelfInIDs := false
@@ -131,12 +144,13 @@ func (id ID) Lagrange(partyIDs IDSlice) (*ristretto.Scalar, error) {
 			continue
 		}
 
+		// num = (x₀ - x) ... (xₖ - x)
 		xM = *partyID.Scalar()
-
-		// num = x₀ * ... * xₖ
-		num.Multiply(&num, &xM) // num * xM
+		xM.Subtract(&xM, &x)
+		num.Multiply(&num, &xM)
 
 		// denum = (x₀ - xⱼ) ... (xₖ - xⱼ)
+		xM = *partyID.Scalar()
 		xM.Subtract(&xM, &xJ)       // = xM - xJ
 		denum.Multiply(&denum, &xM) // denum * (xm - xj)
 	}
@@ -213,3 +213,38 @@ func TestID_Lagrange(t *testing.T) {
 		})
 	}
 }
+
+func TestID_LagrangeAt(t *testing.T) {
+	partyIDs := IDSlice{1, 3, 4}
+
+	// f(x) = 2 + 5x + 7x² evaluated at 2 is 40
+	f := func(x uint32) *ristretto.Scalar {
+		return scalar.NewScalarUInt32(2 + 5*x + 7*x*x)
+	}
+	sum := ristretto.NewScalar()
+	for _, id := range partyIDs {
+		l, err := id.LagrangeAt(2, partyIDs)
+		if err != nil {
+			t.Fatalf("LagrangeAt(): unexpected error: %v", err)
+		}
+		sum.MultiplyAdd(l, f(uint32(id)), sum)
+	}
+	if f(2).Equal(sum) != 1 {
+		t.Errorf("LagrangeAt(): interpolation at 2 is not f(2)")
+	}
+
+	// at the point of a party, the coefficients select its value
+	for _, id := range partyIDs {
+		l, err := id.LagrangeAt(3, partyIDs)
+		if err != nil {
+			t.Fatalf("LagrangeAt(): unexpected error: %v", err)
+		}
+		want := uint32(0)
+		if id == 3 {
+			want = 1
+		}
+		if scalar.NewScalarUInt32(want).Equal(l) != 1 {
+			t.Errorf("LagrangeAt(): l_%d(3) should be %d", id, want)
+		}
+	}
+}
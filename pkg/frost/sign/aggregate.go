@@ -0,0 +1,83 @@
+package sign
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+)
+
+// Aggregate computes the signature of message from the Sign1 and Sign2 messages of all signers in partyIDs,
+// as every signer does in the last round of the protocol.
+// It allows a party which does not hold a share, such as a coordinator, to combine the signature shares.
+//
+// Every signature share is verified, and if one is invalid, the returned error is a *state.Error
+// naming the party which sent it.
+func Aggregate(partyIDs party.IDSlice, shares *eddsa.Public, message []byte, commitments, signatureShares []*messages.Message) (*eddsa.Signature, error) {
+	if partyIDs.N() == 0 {
+		return nil, errors.New("sign.Aggregate: no signers")
+	}
+	commitments, err := messagesFrom(partyIDs, messages.MessageTypeSign1, commitments)
+	if err != nil {
+		return nil, fmt.Errorf("sign.Aggregate: %w", err)
+	}
+	signatureShares, err = messagesFrom(partyIDs, messages.MessageTypeSign2, signatureShares)
+	if err != nil {
+		return nil, fmt.Errorf("sign.Aggregate: %w", err)
+	}
+
+	round, err := newRound(partyIDs[0], partyIDs, shares, message)
+	if err != nil {
+		return nil, fmt.Errorf("sign.Aggregate: %w", err)
+	}
+	defer round.Reset()
+
+	r1 := &round1{round}
+	for _, msg := range commitments {
+		if err := r1.ProcessMessage(msg); err != nil {
+			return nil, err
+		}
+	}
+	r1.computeNonce()
+
+	r2 := &round2{r1}
+	for _, msg := range signatureShares {
+		if err := r2.ProcessMessage(msg); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := r2.GenerateMessages(); err != nil {
+		return nil, err
+	}
+	return round.Output.Signature, nil
+}
+
+// messagesFrom returns the messages in msgs in the order of partyIDs,
+// after checking that there is exactly one of type msgType from each party.
+func messagesFrom(partyIDs party.IDSlice, msgType messages.MessageType, msgs []*messages.Message) ([]*messages.Message, error) {
+	byParty := make(map[party.ID]*messages.Message, len(msgs))
+	for _, msg := range msgs {
+		if msg.Type != msgType {
+			return nil, fmt.Errorf("message from party %d has type %s instead of %s", msg.From, msg.Type, msgType)
+		}
+		if !partyIDs.Contains(msg.From) {
+			return nil, fmt.Errorf("message from party %d, who is not a signer", msg.From)
+		}
+		if _, ok := byParty[msg.From]; ok {
+			return nil, fmt.Errorf("two %s messages from party %d", msgType, msg.From)
+		}
+		byParty[msg.From] = msg
+	}
+
+	ordered := make([]*messages.Message, 0, len(partyIDs))
+	for _, id := range partyIDs {
+		msg, ok := byParty[id]
+		if !ok {
+			return nil, fmt.Errorf("missing %s message from party %d", msgType, id)
+		}
+		ordered = append(ordered, msg)
+	}
+	return ordered, nil
+}
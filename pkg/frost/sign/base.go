@@ -18,6 +18,9 @@ type (
 		// Message is the message to be signed
 		Message []byte
 
+		// Domain is the Ed25519 variant of the signature
+		Domain eddsa.Domain
+
 		// Parties maps IDs to a struct containing all intermediary data for each signer.
 		Parties map[party.ID]*signer
 
@@ -28,7 +31,7 @@ type (
 		// e and d are the scalars committed to in the first round
 		e, d ristretto.Scalar
 
-		// C = H(R, GroupKey, Message)
+		// C = H(dom2(Domain), R, GroupKey, Message)
 		C ristretto.Scalar
 		// R = ∑ Ri
 		R ristretto.Element
@@ -44,17 +47,45 @@ type (
 )
 
 func NewRound(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, message []byte) (state.Round, *Output, error) {
+	return NewRoundWithDomain(partyIDs, secret, shares, message, eddsa.Domain{})
+}
+
+// NewRoundWithDomain is like NewRound, but produces a signature of the Ed25519 variant selected by domain.
+// For Ed25519ph, message must be the SHA-512 digest of the data.
+func NewRoundWithDomain(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, message []byte, domain eddsa.Domain) (state.Round, *Output, error) {
+	if err := domain.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("base.NewRound: %w", err)
+	}
 	if !partyIDs.Contains(secret.ID) {
 		return nil, nil, errors.New("base.NewRound: owner of SecretShare is not contained in partyIDs")
 	}
-	if !partyIDs.IsSubsetOf(shares.PartyIDs) {
-		return nil, nil, errors.New("base.NewRound: not all parties of partyIDs are contained in shares")
+
+	round, err := newRound(secret.ID, partyIDs, shares, message)
+	if err != nil {
+		return nil, nil, fmt.Errorf("base.NewRound: %w", err)
 	}
 
-	baseRound, err := state.NewBaseRound(secret.ID, partyIDs)
+	// Normalize secret share so that we can assume we are dealing with an additive sharing
+	lagrange, err := round.SelfID().Lagrange(partyIDs)
 	if err != nil {
 		return nil, nil, fmt.Errorf("base.NewRound: %w", err)
 	}
+	round.SecretKeyShare.Multiply(lagrange, &secret.Secret)
+	round.Domain = domain
+
+	return round, round.Output, nil
+}
+
+// newRound returns a round0 for the signers in partyIDs, without a secret key share.
+func newRound(selfID party.ID, partyIDs party.IDSlice, shares *eddsa.Public, message []byte) (*round0, error) {
+	if !partyIDs.IsSubsetOf(shares.PartyIDs) {
+		return nil, errors.New("not all parties of partyIDs are contained in shares")
+	}
+
+	baseRound, err := state.NewBaseRound(selfID, partyIDs)
+	if err != nil {
+		return nil, err
+	}
 
 	round := &round0{
 		BaseRound: baseRound,
@@ -63,30 +94,26 @@ func NewRound(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.P
 		GroupKey:  *shares.GroupKey,
 		Output:    &Output{},
 	}
+	round.R.Set(ristretto.NewIdentityElement())
 
 	// Setup parties
 	for _, id := range partyIDs {
 		var s signer
+		// Set all points to the identity, so that the round can be serialized before they are received
+		s.Reset()
 		if id == 0 {
-			return nil, nil, errors.New("base.NewRound: id 0 is not valid")
+			return nil, errors.New("id 0 is not valid")
 		}
 		originalShare := shares.Shares[id]
 		lagrange, err := id.Lagrange(partyIDs)
 		if err != nil {
-			return nil, nil, fmt.Errorf("base.NewRound: %w", err)
+			return nil, err
 		}
 		s.Public.ScalarMult(lagrange, originalShare)
 		round.Parties[id] = &s
 	}
 
-	// Normalize secret share so that we can assume we are dealing with an additive sharing
-	lagrange, err := round.SelfID().Lagrange(partyIDs)
-	if err != nil {
-		return nil, nil, fmt.Errorf("base.NewRound: %w", err)
-	}
-	round.SecretKeyShare.Multiply(lagrange, &secret.Secret)
-
-	return round, round.Output, nil
+	return round, nil
 }
 
 func (round *round0) Reset() {
@@ -94,6 +121,7 @@ func (round *round0) Reset() {
 	one := ristretto.NewIdentityElement()
 
 	round.Message = nil
+	round.Domain = eddsa.Domain{}
 	round.SecretKeyShare.Set(zero)
 
 	round.e.Set(zero)
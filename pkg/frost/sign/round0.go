@@ -14,11 +14,11 @@ func (round *round0) GenerateMessages() ([]*messages.Message, *state.Error) {
 	selfParty := round.Parties[round.SelfID()]
 
 	// Sample dᵢ, Dᵢ = [dᵢ] B
-	scalar.SetScalarRandom(&round.d)
+	scalar.SetScalarRandom(&round.d, round.Random())
 	selfParty.Di.ScalarBaseMult(&round.d)
 
 	// Sample eᵢ, Dᵢ = [eᵢ] B
-	scalar.SetScalarRandom(&round.e)
+	scalar.SetScalarRandom(&round.e, round.Random())
 	selfParty.Ei.ScalarBaseMult(&round.e)
 
 	msg := messages.NewSign1(round.SelfID(), &selfParty.Di, &selfParty.Ei)
@@ -16,12 +16,16 @@ var hashDomainSeparation = []byte("FROST-SHA512")
 func (round *round1) ProcessMessage(msg *messages.Message) *state.Error {
 	id := msg.From
 	otherParty := round.Parties[id]
+	body, ok := msg.Payload.(*messages.Sign1)
+	if !ok {
+		return state.NewError(id, errors.New("invalid message payload"))
+	}
 	identity := ristretto.NewIdentityElement()
-	if msg.Sign1.Di.Equal(identity) == 1 || msg.Sign1.Ei.Equal(identity) == 1 {
+	if body.Di.Equal(identity) == 1 || body.Ei.Equal(identity) == 1 {
 		return state.NewError(id, errors.New("commitment Ei or Di was the identity"))
 	}
-	otherParty.Di.Set(&msg.Sign1.Di)
-	otherParty.Ei.Set(&msg.Sign1.Ei)
+	otherParty.Di.Set(&body.Di)
+	otherParty.Ei.Set(&body.Ei)
 	return nil
 }
 
@@ -74,7 +78,9 @@ func (round *round1) computeRhos() {
 	}
 }
 
-func (round *round1) GenerateMessages() ([]*messages.Message, *state.Error) {
+// computeNonce computes the binding factors, the commitment R and the challenge c,
+// once the commitments of all parties were received.
+func (round *round1) computeNonce() {
 	round.computeRhos()
 
 	round.R.Set(ristretto.NewIdentityElement())
@@ -88,8 +94,12 @@ func (round *round1) GenerateMessages() ([]*messages.Message, *state.Error) {
 		round.R.Add(&round.R, &p.Ri)
 	}
 
-	// c = H(R, GroupKey, M)
-	round.C.Set(eddsa.ComputeChallenge(&round.R, &round.GroupKey, round.Message))
+	// c = H(dom2, R, GroupKey, M)
+	round.C.Set(eddsa.ComputeChallengeWithDomain(&round.R, &round.GroupKey, round.Message, round.Domain))
+}
+
+func (round *round1) GenerateMessages() ([]*messages.Message, *state.Error) {
+	round.computeNonce()
 
 	selfParty := round.Parties[round.SelfID()]
 
@@ -17,16 +17,20 @@ var (
 func (round *round2) ProcessMessage(msg *messages.Message) *state.Error {
 	id := msg.From
 	otherParty := round.Parties[id]
+	body, ok := msg.Payload.(*messages.Sign2)
+	if !ok {
+		return state.NewError(id, errors.New("invalid message payload"))
+	}
 
 	var publicNeg, RPrime ristretto.Element
 	publicNeg.Negate(&otherParty.Public)
 
 	// RPrime = [c](-A) + [s]B
-	RPrime.VarTimeDoubleScalarBaseMult(&round.C, &publicNeg, &msg.Sign2.Zi)
+	RPrime.VarTimeDoubleScalarBaseMult(&round.C, &publicNeg, &body.Zi)
 	if RPrime.Equal(&otherParty.Ri) != 1 {
 		return state.NewError(id, ErrValidateSigShare)
 	}
-	otherParty.Zi.Set(&msg.Sign2.Zi)
+	otherParty.Zi.Set(&body.Zi)
 	return nil
 }
 
@@ -43,7 +47,7 @@ func (round *round2) GenerateMessages() ([]*messages.Message, *state.Error) {
 		S: *S,
 	}
 
-	if !round.GroupKey.Verify(round.Message, sig) {
+	if !round.GroupKey.VerifyWithDomain(round.Message, sig, round.Domain) {
 		return nil, state.NewError(0, ErrValidateSignature)
 	}
 
@@ -0,0 +1,111 @@
+package sign
+
+import (
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/wire"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// MarshalState implements state.Resumable.
+//
+// The output contains the secret nonces e and d, and must therefore never be resumed more than once.
+func (round *round0) MarshalState() ([]byte, error) {
+	w := wire.NewWriter(nil)
+	w.ID(round.SelfID())
+	w.IDs(round.PartyIDs())
+	w.VarBytes(round.Message)
+	w.Bool(round.Domain.Prehashed)
+	w.VarBytes([]byte(round.Domain.Context))
+
+	groupKey, err := round.GroupKey.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	w.Raw(groupKey)
+
+	w.Scalar(&round.SecretKeyShare)
+	w.Scalar(&round.e)
+	w.Scalar(&round.d)
+	w.Scalar(&round.C)
+	w.Element(&round.R)
+
+	for _, id := range round.PartyIDs() {
+		p := round.Parties[id]
+		w.Element(&p.Public)
+		w.Element(&p.Di)
+		w.Element(&p.Ei)
+		w.Element(&p.Ri)
+		w.Scalar(&p.Pi)
+		w.Scalar(&p.Zi)
+	}
+	return w.Bytes(), nil
+}
+
+// ResumeRound recreates the sign round with the given number from the output of MarshalState.
+// It is used as a state.RestoreFunc, and the returned Output will be filled when the resumed protocol finishes.
+func ResumeRound(roundNumber int, data []byte) (state.Round, *Output, error) {
+	r := wire.NewReader(data)
+	selfID := r.ID()
+	partyIDs := r.IDs()
+	message := r.VarBytes()
+	domain := eddsa.Domain{
+		Prehashed: r.Bool(),
+		Context:   string(r.VarBytes()),
+	}
+	groupKey := r.Raw(32)
+	r.Fail(domain.Validate())
+	if r.Err() != nil {
+		return nil, nil, fmt.Errorf("sign.ResumeRound: %w", r.Err())
+	}
+
+	baseRound, err := state.NewBaseRound(selfID, partyIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sign.ResumeRound: %w", err)
+	}
+
+	round := &round0{
+		BaseRound: baseRound,
+		Message:   append([]byte{}, message...),
+		Domain:    domain,
+		Parties:   make(map[party.ID]*signer, partyIDs.N()),
+		Output:    &Output{},
+	}
+	r.Fail(round.GroupKey.UnmarshalBinary(groupKey))
+
+	r.Scalar(&round.SecretKeyShare)
+	r.Scalar(&round.e)
+	r.Scalar(&round.d)
+	r.Scalar(&round.C)
+	r.Element(&round.R)
+
+	for _, id := range partyIDs {
+		var p signer
+		r.Element(&p.Public)
+		r.Element(&p.Di)
+		r.Element(&p.Ei)
+		r.Element(&p.Ri)
+		r.Scalar(&p.Pi)
+		r.Scalar(&p.Zi)
+		round.Parties[id] = &p
+	}
+	if err = r.Finish(); err != nil {
+		round.Reset()
+		return nil, nil, fmt.Errorf("sign.ResumeRound: %w", err)
+	}
+
+	output := round.Output
+	switch roundNumber {
+	case 0:
+		return round, output, nil
+	case 1:
+		return &round1{round}, output, nil
+	case 2:
+		return &round2{&round1{round}}, output, nil
+	default:
+		round.Reset()
+		return nil, nil, fmt.Errorf("sign.ResumeRound: invalid round number %d", roundNumber)
+	}
+}
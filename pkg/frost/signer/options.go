@@ -0,0 +1,17 @@
+//go:build go1.20
+// +build go1.20
+
+package signer
+
+import (
+	"crypto"
+	"crypto/ed25519"
+)
+
+// optionsContext returns the context string of opts, if it is an *ed25519.Options.
+func optionsContext(opts crypto.SignerOpts) string {
+	if o, ok := opts.(*ed25519.Options); ok {
+		return o.Context
+	}
+	return ""
+}
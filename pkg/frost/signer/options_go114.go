@@ -0,0 +1,11 @@
+//go:build !go1.20
+// +build !go1.20
+
+package signer
+
+import "crypto"
+
+// optionsContext returns an empty context, since ed25519.Options requires Go 1.20.
+func optionsContext(crypto.SignerOpts) string {
+	return ""
+}
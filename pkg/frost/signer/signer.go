@@ -0,0 +1,224 @@
+// Package signer implements a crypto.Signer for a FROST group key.
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+	"github.com/taurusgroup/frost-ed25519/pkg/transport"
+)
+
+// sessionDomain separates the session IDs derived by a Signer from other hashes.
+var sessionDomain = []byte("FROST-Ed25519 signer session v1")
+
+// Signer is a crypto.Signer for the group key, whose signatures are computed by a sign protocol
+// between the parties in partyIDs. It can be used wherever Go expects a crypto.Signer,
+// for example to issue certificates with x509.CreateCertificate.
+//
+// The other parties must run the same protocol at the same time, with their own Signer,
+// or with frost.NewSignStateWithDomain and the session ID returned by SessionID.
+type Signer struct {
+	partyIDs party.IDSlice
+	secret   *eddsa.SecretShare
+	shares   *eddsa.Public
+	comm     transport.Communicator
+	opts     []state.Option
+	session  messages.SessionID
+
+	// mu ensures that only one protocol uses comm at a time, and protects the fields below.
+	mu sync.Mutex
+	// counter is the number of signatures started so far
+	counter uint64
+	// pending holds the messages received for a session which was not started yet
+	pending []*messages.Message
+}
+
+// New returns a Signer for the party holding secret, which signs with the parties in partyIDs.
+//
+// The messages of every signature are exchanged through comm, which is not released by the Signer,
+// and must not be used by anything else while the Signer is in use.
+// Every signature has its own session ID, derived with SessionID from the ID given with state.WithSessionID,
+// which should be shared by all parties and unique to this group of Signers.
+// Messages for the next signature which arrive early are kept until it starts.
+// opts are given to every State.
+func New(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, comm transport.Communicator, opts ...state.Option) (*Signer, error) {
+	if !partyIDs.Contains(secret.ID) {
+		return nil, errors.New("signer.New: owner of SecretShare is not contained in partyIDs")
+	}
+	if !partyIDs.IsSubsetOf(shares.PartyIDs) {
+		return nil, errors.New("signer.New: not all parties of partyIDs are contained in shares")
+	}
+	if partyIDs.N() <= shares.Threshold {
+		return nil, fmt.Errorf("signer.New: at least %d parties are required to sign", shares.Threshold+1)
+	}
+	return &Signer{
+		partyIDs: partyIDs,
+		secret:   secret,
+		shares:   shares,
+		comm:     comm,
+		opts:     opts,
+		session:  state.SessionIDOf(opts...),
+	}, nil
+}
+
+// Public returns the group key as an ed25519.PublicKey.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.shares.GroupKey.ToEd25519()
+}
+
+// Sign signs message with the other parties, and returns the signature in the format of ed25519.Sign.
+// It is equivalent to SignContext with context.Background().
+//
+// The nonces are drawn from crypto/rand, or from the source given with state.WithRandom, and not from random,
+// since a nonce reused by a single party leaks its secret share.
+func (s *Signer) Sign(random io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.SignContext(context.Background(), message, opts)
+}
+
+// SignContext is like Sign, but aborts the protocol when ctx is done.
+//
+// The other Signers must call Sign the same number of times, in the same order and with the same messages,
+// since the session ID of a signature is derived from the number of signatures before it, and from the message.
+// A call which fails still counts, so that the Signers remain in step.
+//
+// As for ed25519.PrivateKey.Sign, opts.HashFunc() must be crypto.Hash(0) for Ed25519,
+// or crypto.SHA512 for Ed25519ph, in which case message must be the SHA-512 digest of the data.
+// A context string can be given with *ed25519.Options, which selects Ed25519ctx when the hash is crypto.Hash(0).
+func (s *Signer) SignContext(ctx context.Context, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	domain, err := domainFromOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if domain.Prehashed && len(message) != sha512.Size {
+		return nil, errors.New("signer: bad Ed25519ph message hash length")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessionID := SessionID(s.session, s.counter, message, domain)
+	s.counter++
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stateOpts := append(append([]state.Option{}, s.opts...), state.WithSessionID(sessionID))
+	st, output, err := frost.NewSignStateWithDomain(ctx, s.partyIDs, s.secret, s.shares, message, domain, s.comm.Timeout(), stateOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.run(st, sessionID); err != nil {
+		return nil, err
+	}
+	return output.Signature.ToEd25519(), nil
+}
+
+// run is transport.Run, except that messages of other sessions are kept in s.pending
+// instead of being rejected, and that the pending messages of this session are handled first.
+// s.mu must be held.
+func (s *Signer) run(st *state.State, sessionID messages.SessionID) error {
+	if err := transport.ProcessAll(st, s.comm); err != nil {
+		return err
+	}
+	pending := s.pending[:0]
+	for _, msg := range s.pending {
+		if msg.SessionID == sessionID {
+			_ = st.HandleMessage(msg)
+		} else {
+			pending = append(pending, msg)
+		}
+	}
+	s.pending = pending
+	if err := transport.ProcessAll(st, s.comm); err != nil {
+		return err
+	}
+
+	incoming := s.comm.Incoming()
+	for {
+		select {
+		case msg, ok := <-incoming:
+			if !ok {
+				incoming = nil
+				continue
+			}
+			if msg == nil || msg.From == s.secret.ID {
+				continue
+			}
+			if msg.SessionID != sessionID {
+				s.hold(msg)
+				continue
+			}
+			_ = st.HandleMessage(msg)
+			if err := transport.ProcessAll(st, s.comm); err != nil {
+				return err
+			}
+		case <-st.Done():
+			return st.Err()
+		}
+	}
+}
+
+// hold keeps msg for a later session.
+// The other Signers can be at most one signature ahead, so that a few messages per party suffice.
+// Beyond that, the oldest messages are dropped, since they belong to sessions which were aborted.
+func (s *Signer) hold(msg *messages.Message) {
+	maxPending := 2 * int(s.partyIDs.N())
+	if len(s.pending) >= maxPending {
+		copy(s.pending, s.pending[1:])
+		s.pending = s.pending[:maxPending-1]
+	}
+	s.pending = append(s.pending, msg)
+}
+
+// SessionID returns the session ID of the signature of message with the given domain,
+// when counter signatures were already started by a Signer whose session ID is base.
+func SessionID(base messages.SessionID, counter uint64, message []byte, domain eddsa.Domain) messages.SessionID {
+	h := sha512.New()
+	_, _ = h.Write(sessionDomain)
+	_, _ = h.Write(base[:])
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], counter)
+	_, _ = h.Write(buf[:])
+	if domain.Prehashed {
+		_, _ = h.Write([]byte{1})
+	} else {
+		_, _ = h.Write([]byte{0})
+	}
+	binary.BigEndian.PutUint64(buf[:], uint64(len(domain.Context)))
+	_, _ = h.Write(buf[:])
+	_, _ = h.Write([]byte(domain.Context))
+	_, _ = h.Write(message)
+	var id messages.SessionID
+	copy(id[:], h.Sum(nil))
+	return id
+}
+
+// domainFromOptions returns the Ed25519 variant selected by opts.
+func domainFromOptions(opts crypto.SignerOpts) (eddsa.Domain, error) {
+	var domain eddsa.Domain
+	if opts == nil {
+		return domain, nil
+	}
+	switch opts.HashFunc() {
+	case crypto.Hash(0):
+	case crypto.SHA512:
+		domain.Prehashed = true
+	default:
+		return domain, errors.New("signer: expected opts.HashFunc() zero (unhashed message, for standard Ed25519) or SHA-512 (for Ed25519ph)")
+	}
+	domain.Context = optionsContext(opts)
+	if err := domain.Validate(); err != nil {
+		return domain, fmt.Errorf("signer: %w", err)
+	}
+	return domain, nil
+}
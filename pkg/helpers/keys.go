@@ -13,7 +13,7 @@ func GenerateSecrets(set party.IDSlice, threshold party.Size) (*ristretto.Scalar
 		panic("threshold must be at most the size of set minus 1")
 	}
 	secret := scalar.NewScalarRandom()
-	poly := polynomial.NewPolynomial(threshold, secret)
+	poly := polynomial.NewPolynomial(threshold, secret, nil)
 	shares := make(map[party.ID]*eddsa.SecretShare, set.N())
 	for _, id := range set {
 		shares[id] = eddsa.NewSecretShare(id, poly.Evaluate(id.Scalar()))
@@ -21,17 +21,25 @@ func GenerateSet(n party.Size) party.IDSlice {
 	return party.NewIDSlice(NewPartySlice(n))
 }
 
-func PartyRoutine(in [][]byte, s *state.State) ([][]byte, error) {
+// HandleMessages unmarshals all messages in and gives them to s.
+func HandleMessages(in [][]byte, s *state.State) error {
 	for _, m := range in {
 		var msgTmp messages.Message
 
 		if err := msgTmp.UnmarshalBinary(m); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal message: %w", err)
+			return fmt.Errorf("failed to unmarshal message: %w", err)
 		}
 		if err := s.HandleMessage(&msgTmp); err != nil {
-			return nil, fmt.Errorf("failed to handle message: %w", err)
+			return fmt.Errorf("failed to handle message: %w", err)
 		}
 	}
+	return nil
+}
+
+func PartyRoutine(in [][]byte, s *state.State) ([][]byte, error) {
+	if err := HandleMessages(in, s); err != nil {
+		return nil, err
+	}
 	msgsOut := s.ProcessAll()
 	out := make([][]byte, 0, len(msgsOut))
 	for _, msgOut := range msgsOut {
@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"io"
+)
+
+// deterministicReader is an io.Reader whose output is SHA-512(seed || counter) for counter = 0, 1, ...
+type deterministicReader struct {
+	seed    []byte
+	counter uint64
+	buffer  []byte
+}
+
+// NewDeterministicReader returns an io.Reader whose output only depends on seed.
+// It can be given to state.WithRandom to reproduce an execution of a protocol in tests,
+// and must never be used otherwise.
+func NewDeterministicReader(seed ...[]byte) io.Reader {
+	h := sha512.New()
+	for _, s := range seed {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(s)))
+		_, _ = h.Write(length[:])
+		_, _ = h.Write(s)
+	}
+	return &deterministicReader{seed: h.Sum(nil)}
+}
+
+func (r *deterministicReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buffer) == 0 {
+			var counter [8]byte
+			binary.BigEndian.PutUint64(counter[:], r.counter)
+			r.counter++
+			h := sha512.New()
+			_, _ = h.Write(r.seed)
+			_, _ = h.Write(counter[:])
+			r.buffer = h.Sum(nil)
+		}
+		copied := copy(p[n:], r.buffer)
+		r.buffer = r.buffer[copied:]
+		n += copied
+	}
+	return n, nil
+}
@@ -0,0 +1,283 @@
+// Package cbor implements the small subset of CBOR (RFC 8949) needed to encode protocol messages.
+//
+// Only unsigned integers, byte strings, text strings and arrays of definite length are supported.
+// The Writer always produces the deterministic encoding of Section 4.2 of the RFC,
+// and the Reader rejects anything else: integers and lengths must use the shortest form,
+// and indefinite lengths, maps, tags, negative integers and floats are refused.
+// Structures are encoded as arrays of their fields, in a fixed order.
+package cbor
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	majorUint  = 0
+	majorBytes = 2
+	majorText  = 3
+	majorArray = 4
+)
+
+var (
+	// ErrTruncated is returned by Reader when there is not enough data left.
+	ErrTruncated = errors.New("cbor: data is truncated")
+
+	// ErrTrailingData is returned by Reader.Finish when some data was not consumed.
+	ErrTrailingData = errors.New("cbor: unexpected trailing data")
+
+	// ErrNotCanonical is returned when an item is not encoded in its deterministic form.
+	ErrNotCanonical = errors.New("cbor: non canonical encoding")
+
+	// ErrUnexpectedType is returned when an item does not have the expected type.
+	ErrUnexpectedType = errors.New("cbor: unexpected type")
+)
+
+// Writer appends CBOR items to a byte slice.
+type Writer struct {
+	buf []byte
+}
+
+// NewWriter returns a Writer which appends to existing.
+func NewWriter(existing []byte) *Writer {
+	return &Writer{buf: existing}
+}
+
+// Bytes returns the encoded data.
+func (w *Writer) Bytes() []byte {
+	return w.buf
+}
+
+func (w *Writer) head(major byte, x uint64) {
+	major <<= 5
+	switch {
+	case x < 24:
+		w.buf = append(w.buf, major|byte(x))
+	case x <= 0xff:
+		w.buf = append(w.buf, major|24, byte(x))
+	case x <= 0xffff:
+		w.buf = append(w.buf, major|25, 0, 0)
+		binary.BigEndian.PutUint16(w.buf[len(w.buf)-2:], uint16(x))
+	case x <= 0xffffffff:
+		w.buf = append(w.buf, major|26, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(w.buf[len(w.buf)-4:], uint32(x))
+	default:
+		w.buf = append(w.buf, major|27, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(w.buf[len(w.buf)-8:], x)
+	}
+}
+
+// Uint appends the unsigned integer x.
+func (w *Writer) Uint(x uint64) {
+	w.head(majorUint, x)
+}
+
+// ByteString appends b as a byte string.
+func (w *Writer) ByteString(b []byte) {
+	w.head(majorBytes, uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+// TextString appends s as a text string.
+func (w *Writer) TextString(s string) {
+	w.head(majorText, uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+// Array appends the header of an array of n items, which must be appended next.
+func (w *Writer) Array(n int) {
+	w.head(majorArray, uint64(n))
+}
+
+// Raw appends an item which is already encoded.
+func (w *Writer) Raw(item []byte) {
+	w.buf = append(w.buf, item...)
+}
+
+// Reader parses CBOR items from a byte slice.
+// The first error encountered is recorded, after which all methods return zero values.
+type Reader struct {
+	data []byte
+	err  error
+}
+
+// NewReader returns a Reader which parses data.
+func NewReader(data []byte) *Reader {
+	return &Reader{data: data}
+}
+
+// Err returns the first error encountered.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Fail records err if no other error was encountered before.
+func (r *Reader) Fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// Finish returns the first error encountered, or ErrTrailingData if some data was not consumed.
+func (r *Reader) Finish() error {
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.data) != 0 {
+		return ErrTrailingData
+	}
+	return nil
+}
+
+func (r *Reader) head() (major byte, x uint64) {
+	if r.err != nil {
+		return 0, 0
+	}
+	if len(r.data) == 0 {
+		r.err = ErrTruncated
+		return 0, 0
+	}
+	major, info := r.data[0]>>5, r.data[0]&0x1f
+	r.data = r.data[1:]
+
+	var size int
+	switch {
+	case info < 24:
+		return major, uint64(info)
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		// reserved values and indefinite lengths
+		r.err = ErrNotCanonical
+		return 0, 0
+	}
+	if len(r.data) < size {
+		r.err = ErrTruncated
+		return 0, 0
+	}
+	switch size {
+	case 1:
+		x = uint64(r.data[0])
+	case 2:
+		x = uint64(binary.BigEndian.Uint16(r.data))
+	case 4:
+		x = uint64(binary.BigEndian.Uint32(r.data))
+	case 8:
+		x = binary.BigEndian.Uint64(r.data)
+	}
+	r.data = r.data[size:]
+
+	// The shortest form must be used
+	if (size == 1 && x < 24) || (size > 1 && x < 1<<(4*size)) {
+		r.err = ErrNotCanonical
+		return 0, 0
+	}
+	return major, x
+}
+
+func (r *Reader) expect(major byte) uint64 {
+	m, x := r.head()
+	if r.err == nil && m != major {
+		r.err = fmt.Errorf("%w: got major type %d, expected %d", ErrUnexpectedType, m, major)
+		return 0
+	}
+	return x
+}
+
+func (r *Reader) content(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if uint64(len(r.data)) < n {
+		r.err = ErrTruncated
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+// Uint parses an unsigned integer.
+func (r *Reader) Uint() uint64 {
+	return r.expect(majorUint)
+}
+
+// ByteString parses a byte string. The returned slice aliases the input.
+func (r *Reader) ByteString() []byte {
+	return r.content(r.expect(majorBytes))
+}
+
+// TextString parses a text string, which must be valid UTF-8.
+func (r *Reader) TextString() string {
+	s := r.content(r.expect(majorText))
+	if r.err == nil && !utf8.Valid(s) {
+		r.err = fmt.Errorf("%w: invalid UTF-8 in text string", ErrUnexpectedType)
+		return ""
+	}
+	return string(s)
+}
+
+// Array parses the header of an array and returns the number of items, which must be parsed next.
+// The number of items is bounded by the remaining data, so it can safely be used to allocate a slice.
+func (r *Reader) Array() int {
+	n := r.expect(majorArray)
+	if r.err == nil && n > uint64(len(r.data)) {
+		r.err = ErrTruncated
+		return 0
+	}
+	return int(n)
+}
+
+// ArrayOf parses the header of an array which must contain exactly n items.
+func (r *Reader) ArrayOf(n int) {
+	if got := r.Array(); r.err == nil && got != n {
+		r.err = fmt.Errorf("%w: got array of %d items, expected %d", ErrUnexpectedType, got, n)
+	}
+}
+
+// Raw returns the encoding of the next item, including all nested items in the case of an array.
+func (r *Reader) Raw() []byte {
+	start := r.data
+	r.skip(0)
+	if r.err != nil {
+		return nil
+	}
+	return start[:len(start)-len(r.data)]
+}
+
+// maxDepth bounds the nesting of arrays, so that skipping items cannot exhaust the stack.
+const maxDepth = 16
+
+func (r *Reader) skip(depth int) {
+	if depth > maxDepth {
+		r.Fail(fmt.Errorf("%w: arrays nested too deeply", ErrUnexpectedType))
+		return
+	}
+	major, x := r.head()
+	if r.err != nil {
+		return
+	}
+	switch major {
+	case majorUint:
+	case majorBytes, majorText:
+		r.content(x)
+	case majorArray:
+		if x > uint64(len(r.data)) {
+			r.err = ErrTruncated
+			return
+		}
+		for i := uint64(0); i < x && r.err == nil; i++ {
+			r.skip(depth + 1)
+		}
+	default:
+		r.err = fmt.Errorf("%w: unsupported major type %d", ErrUnexpectedType, major)
+	}
+}
@@ -0,0 +1,94 @@
+package cbor
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_Vectors(t *testing.T) {
+	// Examples from Appendix A of RFC 8949
+	tests := []struct {
+		write func(w *Writer)
+		hex   string
+	}{
+		{func(w *Writer) { w.Uint(0) }, "00"},
+		{func(w *Writer) { w.Uint(23) }, "17"},
+		{func(w *Writer) { w.Uint(24) }, "1818"},
+		{func(w *Writer) { w.Uint(1000) }, "1903e8"},
+		{func(w *Writer) { w.Uint(1000000) }, "1a000f4240"},
+		{func(w *Writer) { w.Uint(1000000000000) }, "1b000000e8d4a51000"},
+		{func(w *Writer) { w.ByteString([]byte{1, 2, 3, 4}) }, "4401020304"},
+		{func(w *Writer) { w.TextString("IETF") }, "6449455446"},
+		{func(w *Writer) { w.Array(0) }, "80"},
+		{func(w *Writer) { w.Array(3); w.Uint(1); w.Uint(2); w.Uint(3) }, "83010203"},
+	}
+	for _, tt := range tests {
+		w := NewWriter(nil)
+		tt.write(w)
+		assert.Equal(t, tt.hex, hex.EncodeToString(w.Bytes()))
+	}
+}
+
+func TestReader_RoundTrip(t *testing.T) {
+	w := NewWriter(nil)
+	w.Array(4)
+	w.Uint(70000)
+	w.ByteString(make([]byte, 300))
+	w.TextString("frost")
+	w.Array(1)
+	w.Uint(1)
+
+	r := NewReader(w.Bytes())
+	r.ArrayOf(4)
+	assert.Equal(t, uint64(70000), r.Uint())
+	assert.Len(t, r.ByteString(), 300)
+	assert.Equal(t, "frost", r.TextString())
+	assert.Equal(t, []byte{0x81, 0x01}, r.Raw())
+	require.NoError(t, r.Finish())
+}
+
+func TestReader_Strict(t *testing.T) {
+	tests := []struct {
+		name string
+		hex  string
+		read func(r *Reader)
+		err  error
+	}{
+		{"non minimal uint", "1817", func(r *Reader) { r.Uint() }, ErrNotCanonical},
+		{"non minimal uint16", "1900ff", func(r *Reader) { r.Uint() }, ErrNotCanonical},
+		{"non minimal length", "580101", func(r *Reader) { r.ByteString() }, ErrNotCanonical},
+		{"indefinite length", "5f4101ff", func(r *Reader) { r.ByteString() }, ErrNotCanonical},
+		{"negative int", "20", func(r *Reader) { r.Uint() }, ErrUnexpectedType},
+		{"map", "a0", func(r *Reader) { r.Raw() }, ErrUnexpectedType},
+		{"wrong type", "4101", func(r *Reader) { r.Uint() }, ErrUnexpectedType},
+		{"wrong array size", "820102", func(r *Reader) { r.ArrayOf(3) }, ErrUnexpectedType},
+		{"invalid utf8", "61ff", func(r *Reader) { r.TextString() }, ErrUnexpectedType},
+		{"truncated", "4401", func(r *Reader) { r.ByteString() }, ErrTruncated},
+		{"huge array", "9bffffffffffffffff", func(r *Reader) { r.Array() }, ErrTruncated},
+		{"trailing", "0000", func(r *Reader) { r.Uint() }, ErrTrailingData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.hex)
+			require.NoError(t, err)
+			r := NewReader(data)
+			tt.read(r)
+			err = r.Finish()
+			assert.True(t, errors.Is(err, tt.err), "got %v", err)
+		})
+	}
+}
+
+func TestReader_Depth(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = 0x81
+	}
+	r := NewReader(data)
+	r.Raw()
+	assert.Error(t, r.Err())
+}
@@ -1,9 +1,13 @@
 package polynomial
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/cbor"
 	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
 	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
 )
@@ -239,3 +243,80 @@ func (p *Exponent) Equal(other interface{}) bool {
 	}
 	return true
 }
+
+// MarshalJSON implements the json.Marshaler interface.
+// The polynomial is encoded as the array of its coefficients, starting with the constant.
+func (p *Exponent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.coefficients)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (p *Exponent) UnmarshalJSON(data []byte) error {
+	var encoded []string
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	if err := checkCoefficientCount(len(encoded)); err != nil {
+		return err
+	}
+
+	coefficients := make([]ristretto.Element, len(encoded))
+	pointers := make([]*ristretto.Element, len(encoded))
+	for i := range encoded {
+		if err := coefficients[i].UnmarshalText([]byte(encoded[i])); err != nil {
+			return fmt.Errorf("polynomial.Exponent: coefficient %d: %w", i, err)
+		}
+		pointers[i] = &coefficients[i]
+	}
+	p.coefficients = pointers
+	return nil
+}
+
+// MarshalCBOR returns the polynomial encoded as a CBOR array of byte strings,
+// containing the coefficients starting with the constant.
+func (p *Exponent) MarshalCBOR() ([]byte, error) {
+	w := cbor.NewWriter(make([]byte, 0, 3+34*len(p.coefficients)))
+	w.Array(len(p.coefficients))
+	for _, c := range p.coefficients {
+		w.ByteString(c.Bytes())
+	}
+	return w.Bytes(), nil
+}
+
+// UnmarshalCBOR decodes a polynomial encoded by MarshalCBOR.
+func (p *Exponent) UnmarshalCBOR(data []byte) error {
+	r := cbor.NewReader(data)
+	n := r.Array()
+	if r.Err() == nil {
+		r.Fail(checkCoefficientCount(n))
+	}
+	encoded := make([][]byte, 0, n)
+	for i := 0; i < n && r.Err() == nil; i++ {
+		encoded = append(encoded, r.ByteString())
+	}
+	if err := r.Finish(); err != nil {
+		return err
+	}
+
+	coefficients := make([]ristretto.Element, n)
+	pointers := make([]*ristretto.Element, n)
+	for i := range encoded {
+		if _, err := coefficients[i].SetCanonicalBytes(encoded[i]); err != nil {
+			return fmt.Errorf("polynomial.Exponent: coefficient %d: %w", i, err)
+		}
+		pointers[i] = &coefficients[i]
+	}
+	p.coefficients = pointers
+	return nil
+}
+
+// checkCoefficientCount verifies that a polynomial with n coefficients has a degree which fits in a party.Size.
+func checkCoefficientCount(n int) error {
+	if n == 0 {
+		return errors.New("polynomial.Exponent: no coefficients")
+	}
+	if n-1 > math.MaxUint16 {
+		return errors.New("polynomial.Exponent: degree is too large")
+	}
+	return nil
+}
@@ -16,7 +16,7 @@ func TestExponent_Evaluate(t *testing.T) {
 	for x := 0; x < 5; x++ {
 		N := party.Size(1000)
 		secret := scalar.NewScalarRandom()
-		poly := NewPolynomial(N, secret)
+		poly := NewPolynomial(N, secret, nil)
 		polyExp := NewPolynomialExponent(poly)
 
 		randomIndex := party.RandID().Scalar()
@@ -35,7 +35,7 @@ func TestExponent_Evaluate(t *testing.T) {
 func Benchmark_Evaluate(b *testing.B) {
 	N := party.Size(100)
 	secret := scalar.NewScalarRandom()
-	poly := NewPolynomial(N, secret)
+	poly := NewPolynomial(N, secret, nil)
 	polyExp := NewPolynomialExponent(poly)
 
 	b.Run("normal", func(b *testing.B) {
@@ -77,7 +77,7 @@ func TestSum(t *testing.T) {
 	polysExp := make([]*Exponent, N)
 	for i := range polys {
 		sec := scalar.NewScalarRandom()
-		polys[i] = NewPolynomial(Deg, sec)
+		polys[i] = NewPolynomial(Deg, sec, nil)
 		polysExp[i] = NewPolynomialExponent(polys[i])
 
 		evaluationScalar.Add(evaluationScalar, polys[i].Evaluate(randomIndex))
@@ -92,3 +92,25 @@ func TestSum(t *testing.T) {
 	assert.Equal(t, 1, evaluationSum.Equal(evaluationFromScalar))
 	assert.Equal(t, 1, evaluationSum.Equal(evaluationPartial))
 }
+
+func TestExponent_JSONCBOR(t *testing.T) {
+	poly := NewPolynomial(5, scalar.NewScalarRandom(), nil)
+	polyExp := NewPolynomialExponent(poly)
+
+	data, err := polyExp.MarshalJSON()
+	assert.NoError(t, err)
+	var decoded Exponent
+	assert.NoError(t, decoded.UnmarshalJSON(data))
+	assert.True(t, polyExp.Equal(&decoded))
+
+	data, err = polyExp.MarshalCBOR()
+	assert.NoError(t, err)
+	var decoded2 Exponent
+	assert.NoError(t, decoded2.UnmarshalCBOR(data))
+	assert.True(t, polyExp.Equal(&decoded2))
+
+	assert.Error(t, decoded2.UnmarshalJSON([]byte(`[]`)), "no coefficients")
+	assert.Error(t, decoded2.UnmarshalJSON([]byte(`[null]`)), "null coefficient")
+	assert.Error(t, decoded2.UnmarshalCBOR([]byte{0x80}), "no coefficients")
+	assert.Error(t, decoded2.UnmarshalCBOR(append(data, 0)), "trailing data")
+}
@@ -1,10 +1,11 @@
 package polynomial
 
 import (
-	"crypto/rand"
-	"fmt"
+	"errors"
+	"io"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
 	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
 )
 
@@ -14,21 +15,16 @@ type Polynomial struct {
 
 // NewPolynomial generates a Polynomial f(X) = secret + a1*X + ... + at*X^t,
 // with coefficients in Z_q, and degree t.
-func NewPolynomial(degree party.Size, constant *ristretto.Scalar) *Polynomial {
+// The coefficients a1, ..., at are read from random, or from crypto/rand if it is nil.
+func NewPolynomial(degree party.Size, constant *ristretto.Scalar, random io.Reader) *Polynomial {
 	var polynomial Polynomial
 	polynomial.coefficients = make([]ristretto.Scalar, degree+1)
 
 	// SetWithoutSelf the constant term to the secret
 	polynomial.coefficients[0].Set(constant)
 
-	var err error
-	randomBytes := make([]byte, 64)
 	for i := party.Size(1); i <= degree; i++ {
-		_, err = rand.Read(randomBytes)
-		if err != nil {
-			panic(fmt.Errorf("edwards25519: failed to generate random Scalar: %w", err))
-		}
-		_, _ = polynomial.coefficients[i].SetUniformBytes(randomBytes)
+		scalar.SetScalarRandom(&polynomial.coefficients[i], random)
 	}
 
 	return &polynomial
@@ -74,3 +70,34 @@ func (p *Polynomial) Reset() {
 		p.coefficients[i].Set(zero)
 	}
 }
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The output contains all the secret coefficients of the polynomial.
+func (p *Polynomial) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, party.IDByteSize+32*len(p.coefficients))
+	data = append(data, p.Degree().Bytes()...)
+	for i := range p.coefficients {
+		data = append(data, p.coefficients[i].Bytes()...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (p *Polynomial) UnmarshalBinary(data []byte) error {
+	degree, err := party.FromBytes(data)
+	if err != nil {
+		return err
+	}
+	data = data[party.IDByteSize:]
+	if len(data) != 32*(int(degree)+1) {
+		return errors.New("wrong number of coefficients embedded")
+	}
+	p.coefficients = make([]ristretto.Scalar, degree+1)
+	for i := range p.coefficients {
+		if _, err = p.coefficients[i].SetCanonicalBytes(data[:32]); err != nil {
+			return err
+		}
+		data = data[32:]
+	}
+	return nil
+}
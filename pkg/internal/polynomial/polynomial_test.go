@@ -27,3 +27,17 @@ func TestPolynomial_Evaluate(t *testing.T) {
 		}
 	}
 }
+
+func TestPolynomial_MarshalBinary(t *testing.T) {
+	secret := scalar.NewScalarRandom()
+	poly := NewPolynomial(10, secret, nil)
+	data, err := poly.MarshalBinary()
+	assert.NoError(t, err)
+
+	var poly2 Polynomial
+	assert.NoError(t, poly2.UnmarshalBinary(data))
+	x := party.RandID().Scalar()
+	assert.Equal(t, 1, poly.Evaluate(x).Equal(poly2.Evaluate(x)))
+
+	assert.Error(t, poly2.UnmarshalBinary(data[:len(data)-1]))
+}
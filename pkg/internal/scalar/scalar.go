@@ -3,15 +3,17 @@ package scalar
 import (
 	"crypto/rand"
 	"fmt"
+	"io"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
 )
 
-// SetScalarRandom sets s to a random ristretto.Scalar using the default randomness source from crypto/rand
-func SetScalarRandom(s *ristretto.Scalar) *ristretto.Scalar {
+// SetScalarRandom sets s to a random ristretto.Scalar read from random,
+// or from the default randomness source from crypto/rand if it is nil.
+func SetScalarRandom(s *ristretto.Scalar, random io.Reader) *ristretto.Scalar {
 	bytes := make([]byte, 64)
 
-	_, err := rand.Reader.Read(bytes)
+	_, err := io.ReadFull(Reader(random), bytes)
 	if err != nil {
 		panic(fmt.Errorf("edwards25519: failed to generate random Scalar: %w", err))
 	}
@@ -23,7 +25,15 @@ func SetScalarRandom(s *ristretto.Scalar) *ristretto.Scalar {
 // NewScalarRandom generates a new ristretto.Scalar using the default randomness source from crypto/rand
 func NewScalarRandom() *ristretto.Scalar {
 	var s ristretto.Scalar
-	return SetScalarRandom(&s)
+	return SetScalarRandom(&s, nil)
+}
+
+// Reader returns random if it is not nil, and crypto/rand.Reader otherwise.
+func Reader(random io.Reader) io.Reader {
+	if random == nil {
+		return rand.Reader
+	}
+	return random
 }
 
 // SetScalarUInt32 set s's value to that of a uint32 x. It creates a 32 byte big-endian representation of x,
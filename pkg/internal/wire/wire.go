@@ -0,0 +1,247 @@
+// Package wire contains helpers for building and parsing the simple binary encodings
+// used to persist protocol state.
+//
+// All integers are big-endian, and variable length data is prefixed by its length.
+package wire
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+// ErrTruncated is returned by Reader when there is not enough data left.
+var ErrTruncated = errors.New("wire: data is truncated")
+
+// ErrTrailingData is returned by Reader.Finish when some data was not consumed.
+var ErrTrailingData = errors.New("wire: unexpected trailing data")
+
+// Writer appends values to a byte slice.
+type Writer struct {
+	buf []byte
+}
+
+// NewWriter returns a Writer which appends to existing.
+func NewWriter(existing []byte) *Writer {
+	return &Writer{buf: existing}
+}
+
+// Bytes returns the encoded data.
+func (w *Writer) Bytes() []byte {
+	return w.buf
+}
+
+// Uint8 appends x.
+func (w *Writer) Uint8(x uint8) {
+	w.buf = append(w.buf, x)
+}
+
+// Uint16 appends x.
+func (w *Writer) Uint16(x uint16) {
+	w.buf = append(w.buf, 0, 0)
+	binary.BigEndian.PutUint16(w.buf[len(w.buf)-2:], x)
+}
+
+// Uint32 appends x.
+func (w *Writer) Uint32(x uint32) {
+	w.buf = append(w.buf, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(w.buf[len(w.buf)-4:], x)
+}
+
+// Raw appends b without a length prefix.
+func (w *Writer) Raw(b []byte) {
+	w.buf = append(w.buf, b...)
+}
+
+// VarBytes appends b prefixed by its length.
+func (w *Writer) VarBytes(b []byte) {
+	w.Uint32(uint32(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+// Bool appends a single byte set to 1 if b is true.
+func (w *Writer) Bool(b bool) {
+	if b {
+		w.Uint8(1)
+	} else {
+		w.Uint8(0)
+	}
+}
+
+// ID appends a party.ID.
+func (w *Writer) ID(id party.ID) {
+	w.buf = append(w.buf, id.Bytes()...)
+}
+
+// IDs appends a party.IDSlice prefixed by its length.
+func (w *Writer) IDs(ids party.IDSlice) {
+	w.Uint16(uint16(len(ids)))
+	for _, id := range ids {
+		w.ID(id)
+	}
+}
+
+// Scalar appends the canonical encoding of s.
+func (w *Writer) Scalar(s *ristretto.Scalar) {
+	w.buf = append(w.buf, s.Bytes()...)
+}
+
+// Element appends the canonical encoding of e.
+func (w *Writer) Element(e *ristretto.Element) {
+	w.buf = append(w.buf, e.Bytes()...)
+}
+
+// Reader consumes values from a byte slice.
+// The first error encountered is retained, and all subsequent reads return zero values.
+type Reader struct {
+	data []byte
+	err  error
+}
+
+// NewReader returns a Reader over data.
+func NewReader(data []byte) *Reader {
+	return &Reader{data: data}
+}
+
+// Err returns the first error that was encountered.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Finish returns the first error that was encountered,
+// or ErrTrailingData if not all data was consumed.
+func (r *Reader) Finish() error {
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.data) != 0 {
+		return ErrTrailingData
+	}
+	return nil
+}
+
+// Fail sets the error of r if none was set yet.
+func (r *Reader) Fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *Reader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.data) < n {
+		r.err = ErrTruncated
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+// Uint8 reads a single byte.
+func (r *Reader) Uint8() uint8 {
+	b := r.next(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+// Uint16 reads a 2 byte integer.
+func (r *Reader) Uint16() uint16 {
+	b := r.next(2)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b)
+}
+
+// Uint32 reads a 4 byte integer.
+func (r *Reader) Uint32() uint32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+// Raw reads exactly n bytes.
+func (r *Reader) Raw(n int) []byte {
+	return r.next(n)
+}
+
+// VarBytes reads a slice prefixed by its length.
+func (r *Reader) VarBytes() []byte {
+	n := r.Uint32()
+	if uint64(n) > uint64(len(r.data)) {
+		r.Fail(ErrTruncated)
+		return nil
+	}
+	return r.next(int(n))
+}
+
+// Bool reads a boolean encoded as a single byte.
+func (r *Reader) Bool() bool {
+	switch r.Uint8() {
+	case 0:
+		return false
+	case 1:
+		return true
+	default:
+		r.Fail(errors.New("wire: invalid boolean"))
+		return false
+	}
+}
+
+// ID reads a party.ID.
+func (r *Reader) ID() party.ID {
+	b := r.next(party.IDByteSize)
+	if b == nil {
+		return 0
+	}
+	id, _ := party.FromBytes(b)
+	return id
+}
+
+// IDs reads a party.IDSlice prefixed by its length.
+func (r *Reader) IDs() party.IDSlice {
+	n := int(r.Uint16())
+	if r.err != nil {
+		return nil
+	}
+	if n*party.IDByteSize > len(r.data) {
+		r.Fail(ErrTruncated)
+		return nil
+	}
+	ids := make(party.IDSlice, n)
+	for i := range ids {
+		ids[i] = r.ID()
+	}
+	return ids
+}
+
+// Scalar reads a canonically encoded ristretto.Scalar into s.
+func (r *Reader) Scalar(s *ristretto.Scalar) {
+	b := r.next(32)
+	if b == nil {
+		return
+	}
+	if _, err := s.SetCanonicalBytes(b); err != nil {
+		r.Fail(err)
+	}
+}
+
+// Element reads a canonically encoded ristretto.Element into e.
+func (r *Reader) Element(e *ristretto.Element) {
+	b := r.next(32)
+	if b == nil {
+		return
+	}
+	if _, err := e.SetCanonicalBytes(b); err != nil {
+		r.Fail(err)
+	}
+}
@@ -1,9 +1,13 @@
 package zk
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/cbor"
 	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
 	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
 
@@ -29,17 +33,18 @@ type Schnorr struct {
 //    public is the point [private]•B
 //    context is a 32 byte context (if it is set to [0 ... 0] then we may be susceptible to replay attacks)
 //    private is the discrete log of public
+//    random is the source of randomness for the nonce, crypto/rand is used if it is nil
 //
 // We sample a random Scalar k, and obtain M = [k]•B
 // S := H(ID,CTX,Public,M)
 // R := k + private•S
 //
 // The proof returned is the tuple (S,R)
-func NewSchnorrProof(partyID party.ID, public *ristretto.Element, context []byte, private *ristretto.Scalar) *Schnorr {
+func NewSchnorrProof(partyID party.ID, public *ristretto.Element, context []byte, private *ristretto.Scalar, random io.Reader) *Schnorr {
 	var proof Schnorr
 
 	// Compute commitment for random nonce
-	k := scalar.NewScalarRandom()
+	k := scalar.SetScalarRandom(ristretto.NewScalar(), random)
 
 	// M = [k] B
 	var M ristretto.Element
@@ -83,7 +88,7 @@ func challenge(partyID party.ID, context []byte, public, M *ristretto.Element) *
 	_, _ = h.Write(public.Bytes())
 	_, _ = h.Write(M.Bytes())
 
-	buffer := make([]byte, 64)
+	buffer := make([]byte, 0, 64)
 	// SetUniformBytes only returns an error when the length is wrong so we're okay here
 	_, _ = S.SetUniformBytes(h.Sum(buffer))
 	return &S
@@ -139,3 +144,58 @@ func (proof *Schnorr) Equal(other interface{}) bool {
 	}
 	return true
 }
+
+type jsonSchnorr struct {
+	S []byte `json:"s"`
+	R []byte `json:"r"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (proof *Schnorr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonSchnorr{
+		S: proof.S.Bytes(),
+		R: proof.R.Bytes(),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (proof *Schnorr) UnmarshalJSON(data []byte) error {
+	var out jsonSchnorr
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+	return proof.setScalars(out.S, out.R)
+}
+
+// MarshalCBOR returns the proof encoded as the CBOR array [S, R] of two byte strings.
+func (proof *Schnorr) MarshalCBOR() ([]byte, error) {
+	w := cbor.NewWriter(make([]byte, 0, 1+2*34))
+	w.Array(2)
+	w.ByteString(proof.S.Bytes())
+	w.ByteString(proof.R.Bytes())
+	return w.Bytes(), nil
+}
+
+// UnmarshalCBOR decodes a proof encoded by MarshalCBOR.
+func (proof *Schnorr) UnmarshalCBOR(data []byte) error {
+	r := cbor.NewReader(data)
+	r.ArrayOf(2)
+	S, R := r.ByteString(), r.ByteString()
+	if err := r.Finish(); err != nil {
+		return err
+	}
+	return proof.setScalars(S, R)
+}
+
+// setScalars sets the proof to the canonical encodings S and R, and leaves it unchanged if one of them is invalid.
+func (proof *Schnorr) setScalars(S, R []byte) error {
+	var decoded Schnorr
+	if _, err := decoded.S.SetCanonicalBytes(S); err != nil {
+		return fmt.Errorf("zk.Schnorr: S: %w", err)
+	}
+	if _, err := decoded.R.SetCanonicalBytes(R); err != nil {
+		return fmt.Errorf("zk.Schnorr: R: %w", err)
+	}
+	*proof = decoded
+	return nil
+}
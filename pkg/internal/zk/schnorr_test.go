@@ -14,8 +14,36 @@ func TestSchnorrProof(t *testing.T) {
 	partyID := party.ID(42)
 	private := scalar.NewScalarRandom()
 	public := new(ristretto.Element).ScalarBaseMult(private)
-	proof := NewSchnorrProof(partyID, public, ctx[:], private)
+	proof := NewSchnorrProof(partyID, public, ctx[:], private, nil)
 	publicComputed := ristretto.NewIdentityElement().ScalarBaseMult(private)
 	require.True(t, publicComputed.Equal(public) == 1)
 	require.True(t, proof.Verify(partyID, public, ctx[:]))
+
+	require.False(t, proof.Verify(partyID+1, public, ctx[:]))
+	require.False(t, proof.Verify(partyID, new(ristretto.Element).Add(public, public), ctx[:]))
+	proof.R.Add(&proof.R, scalar.NewScalarUInt32(1))
+	require.False(t, proof.Verify(partyID, public, ctx[:]))
+}
+
+func TestSchnorr_JSONCBOR(t *testing.T) {
+	var ctx [32]byte
+	private := scalar.NewScalarRandom()
+	public := new(ristretto.Element).ScalarBaseMult(private)
+	proof := NewSchnorrProof(42, public, ctx[:], private, nil)
+
+	data, err := proof.MarshalJSON()
+	require.NoError(t, err)
+	var proof2 Schnorr
+	require.NoError(t, proof2.UnmarshalJSON(data))
+	require.True(t, proof.Equal(&proof2))
+
+	data, err = proof.MarshalCBOR()
+	require.NoError(t, err)
+	var proof3 Schnorr
+	require.NoError(t, proof3.UnmarshalCBOR(data))
+	require.True(t, proof.Equal(&proof3))
+
+	require.Error(t, proof3.UnmarshalCBOR(data[:len(data)-1]))
+	require.Error(t, proof3.UnmarshalJSON([]byte(`{"s":"AAAA","r":"AAAA"}`)))
+	require.True(t, proof.Equal(&proof3), "proof must be unchanged after a failed decoding")
 }
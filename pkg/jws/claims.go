@@ -0,0 +1,73 @@
+package jws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidClaims = errors.New("jws: invalid claims")
+
+// Claims are the registered claims of a JWT (RFC 7519, Section 4.1).
+// Times are Unix times in seconds, and are ignored when they are zero.
+type Claims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	Expiry    int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+}
+
+// Audience is the "aud" claim, which is encoded as a string when it has a single element.
+type Audience []string
+
+// MarshalJSON implements the json.Marshaler interface.
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(a))
+}
+
+// Contains returns true if audience is one of the elements of a.
+func (a Audience) Contains(audience string) bool {
+	for _, s := range a {
+		if s == audience {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the time claims at now, allowing for the given clock skew,
+// and that the token was issued for audience, unless it is empty.
+func (c *Claims) Validate(now time.Time, skew time.Duration, audience string) error {
+	t := now.Unix()
+	s := int64(skew / time.Second)
+	if c.Expiry != 0 && t >= c.Expiry+s {
+		return fmt.Errorf("%w: the token expired at %v", ErrInvalidClaims, time.Unix(c.Expiry, 0).UTC())
+	}
+	if c.NotBefore != 0 && t < c.NotBefore-s {
+		return fmt.Errorf("%w: the token is not valid before %v", ErrInvalidClaims, time.Unix(c.NotBefore, 0).UTC())
+	}
+	if c.IssuedAt != 0 && t < c.IssuedAt-s {
+		return fmt.Errorf("%w: the token is issued in the future", ErrInvalidClaims)
+	}
+	if audience != "" && !c.Audience.Contains(audience) {
+		return fmt.Errorf("%w: the token is not intended for %q", ErrInvalidClaims, audience)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package jws
+
+import (
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+)
+
+// JWKS is a JSON Web Key Set (RFC 7517, Section 5), as published by an issuer
+// so that relying parties can verify its tokens.
+type JWKS struct {
+	Keys []*eddsa.JWK `json:"keys"`
+}
+
+// NewJWKS returns the set of the given keys, in order, whose "kid" are their KeyID.
+// Several keys are published while the group key is rotated.
+func NewJWKS(keys ...*eddsa.PublicKey) *JWKS {
+	set := &JWKS{Keys: make([]*eddsa.JWK, 0, len(keys))}
+	for _, pk := range keys {
+		set.Keys = append(set.Keys, pk.JWK())
+	}
+	return set
+}
+
+// Key returns the Ed25519 key of the set with the given kid.
+// Keys of other types are ignored.
+func (s *JWKS) Key(kid string) (*eddsa.PublicKey, error) {
+	for _, jwk := range s.Keys {
+		if jwk.KeyID != kid || jwk.KeyType != eddsa.JWKKeyType || jwk.Curve != eddsa.JWKCurve {
+			continue
+		}
+		pk, err := jwk.PublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jws: key %q: %w", kid, err)
+		}
+		return pk, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+}
+
+// Verify verifies a compact JWS with the key of the set given by its "kid" header, as the package function Verify.
+func (s *JWKS) Verify(token string) (*Header, []byte, error) {
+	header, _, _, _, err := Parse(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.KeyID == "" {
+		return nil, nil, fmt.Errorf("%w: no kid header", ErrUnknownKey)
+	}
+	pk, err := s.Key(header.KeyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return Verify(pk, token)
+}
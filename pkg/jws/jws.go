@@ -0,0 +1,160 @@
+// Package jws issues and verifies JSON Web Signatures (RFC 7515) and JSON Web Tokens (RFC 7519)
+// with the EdDSA algorithm of RFC 8037, signed by a FROST group key.
+//
+// Tokens are signed by a crypto.Signer, such as a signer.Signer, in which case every party of the
+// signing session must call Sign with the same payload: the protected header only depends on the group key,
+// so that all parties compute the same signing input.
+// For offline signing, SigningInput returns the message to sign, and Compact attaches the signature.
+package jws
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+)
+
+// TypeJWT is the "typ" header of JSON Web Tokens.
+const TypeJWT = "JWT"
+
+var (
+	ErrInvalidToken     = errors.New("jws: invalid token")
+	ErrInvalidSignature = errors.New("jws: invalid signature")
+	ErrUnknownKey       = errors.New("jws: unknown key")
+)
+
+var encoding = base64.RawURLEncoding.Strict()
+
+// Header is the protected header of a JWS.
+type Header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	// KeyID is the JWK thumbprint of the group key, as in eddsa.JWK.
+	KeyID string `json:"kid,omitempty"`
+	// Critical lists extensions which must be understood, none of which are supported.
+	Critical []string `json:"crit,omitempty"`
+}
+
+// NewHeader returns the header of JWTs signed by pk.
+func NewHeader(pk *eddsa.PublicKey) *Header {
+	return &Header{
+		Algorithm: eddsa.JWSAlgorithm,
+		Type:      TypeJWT,
+		KeyID:     KeyID(pk),
+	}
+}
+
+// KeyID returns the "kid" of pk, which is its base64url encoded JWK thumbprint.
+func KeyID(pk *eddsa.PublicKey) string {
+	return encoding.EncodeToString(pk.Thumbprint())
+}
+
+// SigningInput returns the message signed by the group key for a JWT with the given payload,
+// which is the concatenation of the encoded header of NewHeader and the encoded payload.
+func SigningInput(pk *eddsa.PublicKey, payload []byte) []byte {
+	return signingInput(NewHeader(pk), payload)
+}
+
+func signingInput(header *Header, payload []byte) []byte {
+	// Header has no map, so its encoding is deterministic.
+	headerJSON, _ := json.Marshal(header)
+	out := make([]byte, 0, encoding.EncodedLen(len(headerJSON))+1+encoding.EncodedLen(len(payload)))
+	out = append(out, encoding.EncodeToString(headerJSON)...)
+	out = append(out, '.')
+	out = append(out, encoding.EncodeToString(payload)...)
+	return out
+}
+
+// Compact returns the compact serialization of a JWS, given its signing input and its Ed25519 signature.
+func Compact(signingInput, sig []byte) string {
+	return string(signingInput) + "." + encoding.EncodeToString(sig)
+}
+
+// Sign returns a compact JWS of payload signed by signer, which must have an Ed25519 key.
+// When signer is a signer.Signer, the other parties must sign the same payload.
+func Sign(signer crypto.Signer, payload []byte) (string, error) {
+	key, ok := signer.Public().(ed25519.PublicKey)
+	if !ok {
+		return "", errors.New("jws: the signer does not have an Ed25519 key")
+	}
+	pk, err := eddsa.NewPublicKeyFromEd25519(key)
+	if err != nil {
+		return "", fmt.Errorf("jws: %w", err)
+	}
+	input := SigningInput(pk, payload)
+	sig, err := signer.Sign(nil, input, crypto.Hash(0))
+	if err != nil {
+		return "", err
+	}
+	return Compact(input, sig), nil
+}
+
+// SignClaims returns a JWT whose payload is the JSON encoding of claims, signed by signer as with Sign.
+// All parties must pass equal claims, since json.Marshal then returns the same payload.
+func SignClaims(signer crypto.Signer, claims interface{}) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	return Sign(signer, payload)
+}
+
+// Parse decodes a compact JWS without verifying it.
+// It returns the header, the payload, the signing input and the signature.
+func Parse(token string) (header *Header, payload, signingInput []byte, sig *eddsa.Signature, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, nil, nil, ErrInvalidToken
+	}
+	headerJSON, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	header = new(Header)
+	if err = json.Unmarshal(headerJSON, header); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	if payload, err = encoding.DecodeString(parts[1]); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("%w: payload: %v", ErrInvalidToken, err)
+	}
+	if sig, err = eddsa.ImportSignature([]byte(parts[2]), eddsa.FormatJWS); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("%w: signature: %v", ErrInvalidToken, err)
+	}
+	signingInput = []byte(token[:len(parts[0])+1+len(parts[1])])
+	return header, payload, signingInput, sig, nil
+}
+
+// Verify verifies a compact JWS signed by pk with eddsa.PublicKey.Verify, and returns its header and payload.
+// The "alg" header must be EdDSA, and the "kid" header, if present, must be the KeyID of pk.
+// Other header parameters are ignored, unless they are listed in "crit".
+func Verify(pk *eddsa.PublicKey, token string) (*Header, []byte, error) {
+	header, payload, input, sig, err := Parse(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = checkHeader(header); err != nil {
+		return nil, nil, err
+	}
+	if header.KeyID != "" && header.KeyID != KeyID(pk) {
+		return nil, nil, fmt.Errorf("%w %q", ErrUnknownKey, header.KeyID)
+	}
+	if !pk.Verify(input, sig) {
+		return nil, nil, ErrInvalidSignature
+	}
+	return header, payload, nil
+}
+
+func checkHeader(header *Header) error {
+	if header.Algorithm != eddsa.JWSAlgorithm {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, header.Algorithm)
+	}
+	if len(header.Critical) != 0 {
+		return fmt.Errorf("%w: unsupported critical extensions %q", ErrInvalidToken, header.Critical)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package jws
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+)
+
+func newKey(t *testing.T) (ed25519.PrivateKey, *eddsa.PublicKey) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	pk, err := eddsa.NewPublicKeyFromEd25519(public)
+	require.NoError(t, err)
+	return private, pk
+}
+
+func TestVerify_RFC8037(t *testing.T) {
+	// RFC 8037, Appendix A.4
+	key, err := hex.DecodeString("d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a")
+	require.NoError(t, err)
+	pk, err := eddsa.NewPublicKeyFromEd25519(key)
+	require.NoError(t, err)
+	token := "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc." +
+		"hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg"
+
+	header, payload, err := Verify(pk, token)
+	require.NoError(t, err)
+	assert.Equal(t, "EdDSA", header.Algorithm)
+	assert.Equal(t, "Example of Ed25519 signing", string(payload))
+
+	_, _, err = Verify(pk, strings.Replace(token, ".RXhh", ".RYhh", 1))
+	assert.Error(t, err)
+}
+
+func TestSign(t *testing.T) {
+	private, pk := newKey(t)
+	now := time.Now()
+	claims := Claims{
+		Issuer:    "https://id.example.com",
+		Subject:   "alice",
+		Audience:  Audience{"api"},
+		Expiry:    now.Add(time.Hour).Unix(),
+		NotBefore: now.Unix(),
+		IssuedAt:  now.Unix(),
+	}
+	token, err := SignClaims(private, claims)
+	require.NoError(t, err)
+
+	header, payload, err := Verify(pk, token)
+	require.NoError(t, err)
+	assert.Equal(t, NewHeader(pk), header)
+	assert.Equal(t, pk.JWK().KeyID, header.KeyID)
+	assert.Contains(t, string(payload), `"aud":"api"`)
+	var decoded Claims
+	require.NoError(t, json.Unmarshal(payload, &decoded))
+	assert.Equal(t, claims, decoded)
+	assert.NoError(t, decoded.Validate(now, 0, "api"))
+
+	// The same signing input is used for offline signing
+	parts := strings.Split(token, ".")
+	input := SigningInput(pk, payload)
+	assert.Equal(t, parts[0]+"."+parts[1], string(input))
+	assert.Equal(t, token, Compact(input, ed25519.Sign(private, input)))
+
+	_, otherPk := newKey(t)
+	_, _, err = Verify(otherPk, token)
+	assert.True(t, errors.Is(err, ErrUnknownKey), err)
+	_, _, err = Verify(pk, parts[0]+"."+parts[1]+"."+parts[1])
+	assert.True(t, errors.Is(err, ErrInvalidToken), err)
+	_, _, err = Verify(pk, parts[0]+"."+parts[1])
+	assert.True(t, errors.Is(err, ErrInvalidToken), err)
+
+	// A valid signature of a header with another algorithm
+	header.Algorithm = "none"
+	input = signingInput(header, payload)
+	_, _, err = Verify(pk, Compact(input, ed25519.Sign(private, input)))
+	assert.True(t, errors.Is(err, ErrInvalidToken), err)
+	header.Algorithm = eddsa.JWSAlgorithm
+	header.Critical = []string{"exp"}
+	input = signingInput(header, payload)
+	_, _, err = Verify(pk, Compact(input, ed25519.Sign(private, input)))
+	assert.True(t, errors.Is(err, ErrInvalidToken), err)
+}
+
+func TestJWKS(t *testing.T) {
+	private1, pk1 := newKey(t)
+	private2, pk2 := newKey(t)
+	private3, _ := newKey(t)
+	set := NewJWKS(pk1, pk2)
+
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	var decoded JWKS
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, set, &decoded)
+
+	for _, private := range []ed25519.PrivateKey{private1, private2} {
+		token, err := Sign(private, []byte("{}"))
+		require.NoError(t, err)
+		_, payload, err := decoded.Verify(token)
+		require.NoError(t, err)
+		assert.Equal(t, "{}", string(payload))
+	}
+	token, err := Sign(private3, []byte("{}"))
+	require.NoError(t, err)
+	_, _, err = decoded.Verify(token)
+	assert.True(t, errors.Is(err, ErrUnknownKey), err)
+
+	key, err := decoded.Key(KeyID(pk2))
+	require.NoError(t, err)
+	assert.True(t, pk2.Equal(key))
+}
+
+func TestClaims_Validate(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	claims := Claims{
+		Audience:  Audience{"a", "b"},
+		Expiry:    now.Unix() + 60,
+		NotBefore: now.Unix() - 60,
+		IssuedAt:  now.Unix() - 60,
+	}
+	assert.NoError(t, claims.Validate(now, 0, "b"))
+	assert.NoError(t, claims.Validate(now, 0, ""))
+	assert.Error(t, claims.Validate(now, 0, "c"))
+	assert.Error(t, claims.Validate(now.Add(time.Minute), 0, ""))
+	assert.NoError(t, claims.Validate(now.Add(time.Minute), time.Minute, ""))
+	assert.Error(t, claims.Validate(now.Add(-2*time.Minute), 0, ""))
+
+	data, err := json.Marshal(claims)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"aud":["a","b"]`)
+}
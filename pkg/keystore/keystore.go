@@ -0,0 +1,294 @@
+// Package keystore stores the secret share of a party, together with the public keys of its group,
+// in a file encrypted with a passphrase.
+//
+// The key is derived from the passphrase with scrypt, and the shares are encrypted with AES-256-GCM.
+// The Metadata is stored in the clear, so that keystores can be listed without the passphrase,
+// but it is authenticated together with the ciphertext and cannot be modified.
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"golang.org/x/crypto/scrypt"
+)
+
+// version is the version of the keystore format.
+const version = 1
+
+const (
+	kdfScrypt    = "scrypt"
+	cipherAESGCM = "aes-256-gcm"
+	keySize      = 32
+	saltSize     = 32
+	gcmNonceSize = 12
+
+	// maxScryptMemory bounds the memory required by the parameters of a keystore,
+	// so that opening a malicious keystore does not exhaust the memory.
+	maxScryptMemory = 4 << 30
+)
+
+var (
+	// ErrWrongPassphrase is returned by Open when the passphrase is wrong,
+	// or when the keystore was modified.
+	ErrWrongPassphrase = errors.New("keystore: wrong passphrase or corrupted keystore")
+
+	// ErrInvalid is returned when a keystore cannot be decoded.
+	ErrInvalid = errors.New("keystore: invalid keystore")
+)
+
+// ScryptParams are the cost parameters of the scrypt key derivation, where N = 2^LogN.
+type ScryptParams struct {
+	LogN uint8 `json:"logn"`
+	R    int   `json:"r"`
+	P    int   `json:"p"`
+}
+
+// DefaultParams are the scrypt parameters recommended for interactive use,
+// which require 128 MiB of memory and about half a second to derive the key.
+var DefaultParams = ScryptParams{LogN: 17, R: 8, P: 1}
+
+// Metadata describes the share held by a keystore.
+type Metadata struct {
+	// ID of the party owning the share
+	ID party.ID `json:"id"`
+
+	// Threshold of the group, such that Threshold+1 parties are required to sign
+	Threshold party.Size `json:"threshold"`
+
+	// GroupKey is the public key of the group
+	GroupKey *eddsa.PublicKey `json:"groupkey"`
+
+	// Created is the time at which the share was first stored, in UTC
+	Created time.Time `json:"created"`
+}
+
+// Keystore is the decrypted content of a keystore.
+type Keystore struct {
+	Metadata Metadata
+	Secret   *eddsa.SecretShare
+	Public   *eddsa.Public
+}
+
+// header contains all fields of a keystore except the ciphertext,
+// and its JSON encoding is authenticated as additional data.
+type header struct {
+	Version  int          `json:"version"`
+	Metadata Metadata     `json:"metadata"`
+	KDF      string       `json:"kdf"`
+	Scrypt   ScryptParams `json:"scrypt"`
+	Salt     []byte       `json:"salt"`
+	Cipher   string       `json:"cipher"`
+	Nonce    []byte       `json:"nonce"`
+}
+
+type keystoreJSON struct {
+	header
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type contentJSON struct {
+	Secret *eddsa.SecretShare `json:"secret"`
+	Public *eddsa.Public      `json:"public"`
+}
+
+// Seal encrypts secret and public with a key derived from passphrase, using DefaultParams,
+// and returns the encoded keystore.
+func Seal(secret *eddsa.SecretShare, public *eddsa.Public, passphrase []byte) ([]byte, error) {
+	return SealWithParams(secret, public, passphrase, DefaultParams)
+}
+
+// SealWithParams is like Seal, but uses the given scrypt parameters.
+func SealWithParams(secret *eddsa.SecretShare, public *eddsa.Public, passphrase []byte, params ScryptParams) ([]byte, error) {
+	share, ok := public.Shares[secret.ID]
+	if !ok {
+		return nil, fmt.Errorf("keystore.Seal: party %d has no public share", secret.ID)
+	}
+	var expected ristretto.Element
+	expected.ScalarBaseMult(&secret.Secret)
+	if expected.Equal(share) != 1 {
+		return nil, errors.New("keystore.Seal: secret share does not match the public share")
+	}
+
+	metadata := Metadata{
+		ID:        secret.ID,
+		Threshold: public.Threshold,
+		GroupKey:  public.GroupKey,
+		Created:   time.Now().UTC().Truncate(time.Second),
+	}
+	data, err := seal(metadata, secret, public, passphrase, params)
+	if err != nil {
+		return nil, fmt.Errorf("keystore.Seal: %w", err)
+	}
+	return data, nil
+}
+
+// Open decrypts a keystore with the passphrase it was sealed with.
+func Open(data, passphrase []byte) (*Keystore, error) {
+	ks, err := open(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("keystore.Open: %w", err)
+	}
+	return ks, nil
+}
+
+// ReadMetadata returns the metadata of a keystore, without decrypting it.
+// Since it is not authenticated, it should only be used for display purposes.
+func ReadMetadata(data []byte) (*Metadata, error) {
+	var ks keystoreJSON
+	if err := decode(data, &ks); err != nil {
+		return nil, fmt.Errorf("keystore.ReadMetadata: %w", err)
+	}
+	return &ks.Metadata, nil
+}
+
+// ChangePassphrase decrypts a keystore with oldPassphrase, and encrypts it again with newPassphrase.
+// The metadata, including the creation time, is kept, and the key is derived with params.
+func ChangePassphrase(data, oldPassphrase, newPassphrase []byte, params ScryptParams) ([]byte, error) {
+	ks, err := open(data, oldPassphrase)
+	if err != nil {
+		return nil, fmt.Errorf("keystore.ChangePassphrase: %w", err)
+	}
+	defer ks.Secret.Secret.Set(ristretto.NewScalar())
+
+	newData, err := seal(ks.Metadata, ks.Secret, ks.Public, newPassphrase, params)
+	if err != nil {
+		return nil, fmt.Errorf("keystore.ChangePassphrase: %w", err)
+	}
+	return newData, nil
+}
+
+func seal(metadata Metadata, secret *eddsa.SecretShare, public *eddsa.Public, passphrase []byte, params ScryptParams) ([]byte, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	h := header{
+		Version:  version,
+		Metadata: metadata,
+		KDF:      kdfScrypt,
+		Scrypt:   params,
+		Salt:     make([]byte, saltSize),
+		Cipher:   cipherAESGCM,
+		Nonce:    make([]byte, gcmNonceSize),
+	}
+	if _, err := rand.Read(h.Salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(h.Nonce); err != nil {
+		return nil, err
+	}
+
+	plaintext, err := json.Marshal(contentJSON{Secret: secret, Public: public})
+	if err != nil {
+		return nil, err
+	}
+	defer zero(plaintext)
+
+	aead, ad, err := h.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(keystoreJSON{
+		header:     h,
+		Ciphertext: aead.Seal(nil, h.Nonce, plaintext, ad),
+	}, "", " ")
+}
+
+func open(data, passphrase []byte) (*Keystore, error) {
+	var ks keystoreJSON
+	if err := decode(data, &ks); err != nil {
+		return nil, err
+	}
+	if ks.KDF != kdfScrypt || ks.Cipher != cipherAESGCM {
+		return nil, fmt.Errorf("unsupported algorithms %s and %s: %w", ks.KDF, ks.Cipher, ErrInvalid)
+	}
+	if err := ks.Scrypt.validate(); err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalid)
+	}
+	if len(ks.Salt) != saltSize || len(ks.Nonce) != gcmNonceSize {
+		return nil, fmt.Errorf("invalid salt or nonce: %w", ErrInvalid)
+	}
+
+	aead, ad, err := ks.header.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, ks.Nonce, ks.Ciphertext, ad)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	defer zero(plaintext)
+
+	var content contentJSON
+	if err = json.Unmarshal(plaintext, &content); err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalid)
+	}
+	if content.Secret == nil || content.Public == nil ||
+		content.Secret.ID != ks.Metadata.ID ||
+		content.Public.Threshold != ks.Metadata.Threshold ||
+		!content.Public.GroupKey.Equal(ks.Metadata.GroupKey) {
+		return nil, fmt.Errorf("content does not match the metadata: %w", ErrInvalid)
+	}
+	return &Keystore{
+		Metadata: ks.Metadata,
+		Secret:   content.Secret,
+		Public:   content.Public,
+	}, nil
+}
+
+func decode(data []byte, ks *keystoreJSON) error {
+	if err := json.Unmarshal(data, ks); err != nil {
+		return fmt.Errorf("%v: %w", err, ErrInvalid)
+	}
+	if ks.Version != version {
+		return fmt.Errorf("unsupported version %d: %w", ks.Version, ErrInvalid)
+	}
+	if ks.Metadata.GroupKey == nil {
+		return fmt.Errorf("missing group key: %w", ErrInvalid)
+	}
+	return nil
+}
+
+// aead derives the key from passphrase, and returns the cipher together with the additional data to authenticate.
+func (h *header) aead(passphrase []byte) (cipher.AEAD, []byte, error) {
+	ad, err := json.Marshal(h)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := scrypt.Key(passphrase, h.Salt, 1<<h.Scrypt.LogN, h.Scrypt.R, h.Scrypt.P, keySize)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer zero(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, ad, nil
+}
+
+func (p ScryptParams) validate() error {
+	if p.LogN == 0 || p.LogN >= 32 || p.R <= 0 || p.P <= 0 || uint64(p.R)*uint64(p.P) >= 1<<30 ||
+		128*uint64(p.R)<<p.LogN > maxScryptMemory {
+		return fmt.Errorf("invalid scrypt parameters N=2^%d, r=%d, p=%d", p.LogN, p.R, p.P)
+	}
+	return nil
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
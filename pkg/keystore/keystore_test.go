@@ -0,0 +1,105 @@
+package keystore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+)
+
+// testParams make the tests fast, and must not be used otherwise.
+var testParams = ScryptParams{LogN: 10, R: 8, P: 1}
+
+func TestSealOpen(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	_, secrets := helpers.GenerateSecrets(partyIDs, 1)
+	public := helpers.GeneratePublic(1, secrets)
+	secret := secrets[partyIDs[1]]
+	passphrase := []byte("correct horse battery staple")
+
+	data, err := SealWithParams(secret, public, passphrase, testParams)
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(data, secret.Secret.Bytes()), "the secret must not be stored in the clear")
+
+	ks, err := Open(data, passphrase)
+	require.NoError(t, err)
+	assert.True(t, ks.Secret.Equal(secret))
+	assert.True(t, ks.Public.Equal(public))
+	assert.Equal(t, secret.ID, ks.Metadata.ID)
+	assert.Equal(t, public.Threshold, ks.Metadata.Threshold)
+	assert.True(t, public.GroupKey.Equal(ks.Metadata.GroupKey))
+	assert.False(t, ks.Metadata.Created.IsZero())
+
+	metadata, err := ReadMetadata(data)
+	require.NoError(t, err)
+	assert.Equal(t, ks.Metadata.ID, metadata.ID)
+	assert.True(t, ks.Metadata.Created.Equal(metadata.Created))
+
+	_, err = Open(data, []byte("wrong"))
+	assert.True(t, errors.Is(err, ErrWrongPassphrase))
+
+	_, otherSecrets := helpers.GenerateSecrets(partyIDs, 1)
+	_, err = SealWithParams(otherSecrets[secret.ID], public, passphrase, testParams)
+	assert.Error(t, err, "a secret share of another group should be rejected")
+}
+
+func TestOpen_Tampered(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	_, secrets := helpers.GenerateSecrets(partyIDs, 1)
+	public := helpers.GeneratePublic(1, secrets)
+	passphrase := []byte("passphrase")
+
+	data, err := SealWithParams(secrets[partyIDs[0]], public, passphrase, testParams)
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	fields["metadata"].(map[string]interface{})["threshold"] = "2"
+	tampered, err := json.Marshal(fields)
+	require.NoError(t, err)
+	_, err = Open(tampered, passphrase)
+	assert.True(t, errors.Is(err, ErrWrongPassphrase), "modified metadata should be detected")
+
+	require.NoError(t, json.Unmarshal(data, &fields))
+	fields["scrypt"].(map[string]interface{})["logn"] = 40
+	tampered, err = json.Marshal(fields)
+	require.NoError(t, err)
+	_, err = Open(tampered, passphrase)
+	assert.True(t, errors.Is(err, ErrInvalid), "excessive scrypt parameters should be rejected")
+
+	require.NoError(t, json.Unmarshal(data, &fields))
+	fields["version"] = 2
+	tampered, err = json.Marshal(fields)
+	require.NoError(t, err)
+	_, err = ReadMetadata(tampered)
+	assert.True(t, errors.Is(err, ErrInvalid), "unknown versions should be rejected")
+}
+
+func TestChangePassphrase(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	_, secrets := helpers.GenerateSecrets(partyIDs, 1)
+	public := helpers.GeneratePublic(1, secrets)
+	secret := secrets[partyIDs[2]]
+
+	data, err := SealWithParams(secret, public, []byte("old"), testParams)
+	require.NoError(t, err)
+	before, err := ReadMetadata(data)
+	require.NoError(t, err)
+
+	_, err = ChangePassphrase(data, []byte("wrong"), []byte("new"), testParams)
+	assert.True(t, errors.Is(err, ErrWrongPassphrase))
+
+	newData, err := ChangePassphrase(data, []byte("old"), []byte("new"), testParams)
+	require.NoError(t, err)
+
+	_, err = Open(newData, []byte("old"))
+	assert.True(t, errors.Is(err, ErrWrongPassphrase))
+	ks, err := Open(newData, []byte("new"))
+	require.NoError(t, err)
+	assert.True(t, ks.Secret.Equal(secret))
+	assert.True(t, before.Created.Equal(ks.Metadata.Created), "the creation time should be kept")
+}
@@ -0,0 +1,266 @@
+package messages
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/cbor"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/zk"
+)
+
+// batchCountSize is the number of bytes used to encode the number of keys in a batch message.
+const batchCountSize = 2
+
+// MaxBatchSize is the maximum number of keys that can be generated in a single batch keygen session.
+const MaxBatchSize = math.MaxUint16
+
+func init() {
+	Register(MessageTypeBatchKeyGen1, TypeInfo{
+		Name:       "batchkeygen1",
+		Broadcast:  true,
+		NewPayload: func() FROSTMarshaler { return new(BatchKeyGen1) },
+	})
+}
+
+type BatchKeyGen1 struct {
+	// Proofs[k] is the proof of knowledge of the constant coefficient of the k-th polynomial
+	Proofs []*zk.Schnorr
+	// Commitments[k] is the commitment to the k-th polynomial
+	Commitments []*polynomial.Exponent
+}
+
+func NewBatchKeyGen1(from party.ID, proofs []*zk.Schnorr, commitments []*polynomial.Exponent) *Message {
+	return &Message{
+		Header: Header{
+			Type: MessageTypeBatchKeyGen1,
+			From: from,
+		},
+		Payload: &BatchKeyGen1{
+			Proofs:      proofs,
+			Commitments: commitments,
+		},
+	}
+}
+
+func (m *BatchKeyGen1) BytesAppend(existing []byte) ([]byte, error) {
+	err := m.check()
+	if err != nil {
+		return nil, err
+	}
+
+	existing = append(existing, 0, 0)
+	binary.BigEndian.PutUint16(existing[len(existing)-batchCountSize:], uint16(len(m.Proofs)))
+
+	for _, proof := range m.Proofs {
+		existing, err = proof.BytesAppend(existing)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, commitments := range m.Commitments {
+		existing, err = commitments.BytesAppend(existing)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return existing, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (m *BatchKeyGen1) MarshalBinary() (data []byte, err error) {
+	buf := make([]byte, 0, m.Size())
+	return m.BytesAppend(buf)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+//
+// All commitment polynomials must have the same degree.
+func (m *BatchKeyGen1) UnmarshalBinary(data []byte) error {
+	if len(data) < batchCountSize {
+		return fmt.Errorf("batch msg1: %w", ErrInvalidMessage)
+	}
+	count := int(binary.BigEndian.Uint16(data))
+	if count == 0 {
+		return fmt.Errorf("batch msg1: empty batch: %w", ErrInvalidMessage)
+	}
+	data = data[batchCountSize:]
+
+	if len(data) < 64*count+party.IDByteSize {
+		return fmt.Errorf("batch msg1: %w", ErrInvalidMessage)
+	}
+
+	m.Proofs = make([]*zk.Schnorr, count)
+	for k := range m.Proofs {
+		m.Proofs[k] = &zk.Schnorr{}
+		if err := m.Proofs[k].UnmarshalBinary(data[:64]); err != nil {
+			return fmt.Errorf("batch msg1.Proofs[%d]: %w", k, err)
+		}
+		data = data[64:]
+	}
+
+	// All polynomials have the same degree, so we can deduce the size of each one from the first.
+	degree, err := party.FromBytes(data)
+	if err != nil {
+		return fmt.Errorf("batch msg1: %w", err)
+	}
+	commitmentSize := party.IDByteSize + 32*(int(degree)+1)
+	if len(data) != count*commitmentSize {
+		return fmt.Errorf("batch msg1: wrong commitments length: %w", ErrInvalidMessage)
+	}
+
+	m.Commitments = make([]*polynomial.Exponent, count)
+	for k := range m.Commitments {
+		m.Commitments[k] = &polynomial.Exponent{}
+		if err = m.Commitments[k].UnmarshalBinary(data[:commitmentSize]); err != nil {
+			return fmt.Errorf("batch msg1.Commitments[%d]: %w", k, err)
+		}
+		if m.Commitments[k].Degree() != degree {
+			return fmt.Errorf("batch msg1.Commitments[%d]: inconsistent degree: %w", k, ErrInvalidMessage)
+		}
+		data = data[commitmentSize:]
+	}
+
+	return nil
+}
+
+func (m *BatchKeyGen1) Size() int {
+	size := batchCountSize
+	for _, proof := range m.Proofs {
+		size += proof.Size()
+	}
+	for _, commitments := range m.Commitments {
+		size += commitments.Size()
+	}
+	return size
+}
+
+func (m *BatchKeyGen1) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*BatchKeyGen1)
+	if !ok {
+		return false
+	}
+	if len(m.Proofs) != len(otherMsg.Proofs) || len(m.Commitments) != len(otherMsg.Commitments) {
+		return false
+	}
+	for k := range m.Proofs {
+		if !otherMsg.Proofs[k].Equal(m.Proofs[k]) {
+			return false
+		}
+	}
+	for k := range m.Commitments {
+		if !otherMsg.Commitments[k].Equal(m.Commitments[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+type jsonBatchKeyGen1 struct {
+	Proofs      []*zk.Schnorr          `json:"proofs"`
+	Commitments []*polynomial.Exponent `json:"commitments"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (m *BatchKeyGen1) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonBatchKeyGen1{
+		Proofs:      m.Proofs,
+		Commitments: m.Commitments,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (m *BatchKeyGen1) UnmarshalJSON(data []byte) error {
+	var out jsonBatchKeyGen1
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+	decoded := BatchKeyGen1{
+		Proofs:      out.Proofs,
+		Commitments: out.Commitments,
+	}
+	if err := decoded.check(); err != nil {
+		return err
+	}
+	*m = decoded
+	return nil
+}
+
+// MarshalCBOR returns the CBOR array [[proofs...], [commitments...]].
+func (m *BatchKeyGen1) MarshalCBOR() ([]byte, error) {
+	if err := m.check(); err != nil {
+		return nil, err
+	}
+	w := cbor.NewWriter(nil)
+	w.Array(2)
+	w.Array(len(m.Proofs))
+	for _, proof := range m.Proofs {
+		data, err := proof.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+		w.Raw(data)
+	}
+	w.Array(len(m.Commitments))
+	for _, commitments := range m.Commitments {
+		data, err := commitments.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+		w.Raw(data)
+	}
+	return w.Bytes(), nil
+}
+
+// UnmarshalCBOR decodes a message encoded by MarshalCBOR.
+func (m *BatchKeyGen1) UnmarshalCBOR(data []byte) error {
+	r := cbor.NewReader(data)
+	r.ArrayOf(2)
+
+	var decoded BatchKeyGen1
+	decoded.Proofs = make([]*zk.Schnorr, r.Array())
+	for k := range decoded.Proofs {
+		decoded.Proofs[k] = &zk.Schnorr{}
+		if err := decoded.Proofs[k].UnmarshalCBOR(r.Raw()); err != nil {
+			r.Fail(fmt.Errorf("batch msg1.Proofs[%d]: %w", k, err))
+		}
+	}
+	decoded.Commitments = make([]*polynomial.Exponent, r.Array())
+	for k := range decoded.Commitments {
+		decoded.Commitments[k] = &polynomial.Exponent{}
+		if err := decoded.Commitments[k].UnmarshalCBOR(r.Raw()); err != nil {
+			r.Fail(fmt.Errorf("batch msg1.Commitments[%d]: %w", k, err))
+		}
+	}
+	if err := r.Finish(); err != nil {
+		return fmt.Errorf("batch msg1: %w", err)
+	}
+	if err := decoded.check(); err != nil {
+		return err
+	}
+	*m = decoded
+	return nil
+}
+
+// check verifies the constraints of the binary encoding:
+// the batch is not empty, there are as many proofs as commitments, and all commitments have the same degree.
+func (m *BatchKeyGen1) check() error {
+	if len(m.Proofs) != len(m.Commitments) {
+		return fmt.Errorf("batch msg1: number of proofs and commitments differ: %w", ErrInvalidMessage)
+	}
+	if len(m.Proofs) == 0 || len(m.Proofs) > MaxBatchSize {
+		return fmt.Errorf("batch msg1: invalid batch size %d: %w", len(m.Proofs), ErrInvalidMessage)
+	}
+	for k := range m.Proofs {
+		if m.Proofs[k] == nil || m.Commitments[k] == nil {
+			return fmt.Errorf("batch msg1: missing key %d: %w", k, ErrInvalidMessage)
+		}
+		if m.Commitments[k].Degree() != m.Commitments[0].Degree() {
+			return fmt.Errorf("batch msg1.Commitments[%d]: inconsistent degree: %w", k, ErrInvalidMessage)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/zk"
+)
+
+func TestBatchKeyGen1_MarshalBinary(t *testing.T) {
+	from := party.RandID()
+	deg := 10
+	batchSize := 5
+	context := make([]byte, 32)
+
+	proofs := make([]*zk.Schnorr, batchSize)
+	comms := make([]*polynomial.Exponent, batchSize)
+	for k := range proofs {
+		secret := scalar.NewScalarRandom()
+		poly := polynomial.NewPolynomial(party.Size(deg), secret, nil)
+		comms[k] = polynomial.NewPolynomialExponent(poly)
+		proofs[k] = zk.NewSchnorrProof(from, comms[k].Constant(), context, poly.Constant(), nil)
+	}
+
+	msg := NewBatchKeyGen1(from, proofs, comms)
+
+	var msg2 Message
+	require.NoError(t, CheckFROSTMarshaler(msg, &msg2))
+	require.NoError(t, CheckJSONMarshaler(msg, &Message{}))
+	require.NoError(t, CheckCBORMarshaler(msg, &Message{}))
+	assert.True(t, msg2.Equal(msg), "messages are not equal")
+
+	// a truncated commitment must be rejected
+	data, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	var body BatchKeyGen1
+	assert.Error(t, body.UnmarshalBinary(data[headerSize:len(data)-32]), "truncated message should fail")
+}
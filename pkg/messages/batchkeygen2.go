@@ -0,0 +1,159 @@
+package messages
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/cbor"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+func init() {
+	Register(MessageTypeBatchKeyGen2, TypeInfo{
+		Name:       "batchkeygen2",
+		Broadcast:  false,
+		NewPayload: func() FROSTMarshaler { return new(BatchKeyGen2) },
+	})
+}
+
+type BatchKeyGen2 struct {
+	// Shares[k] is a Shamir additive share of the k-th key for the destination party
+	Shares []ristretto.Scalar
+}
+
+func NewBatchKeyGen2(from, to party.ID, shares []ristretto.Scalar) *Message {
+	return &Message{
+		Header: Header{
+			Type: MessageTypeBatchKeyGen2,
+			From: from,
+			To:   to,
+		},
+		Payload: &BatchKeyGen2{Shares: shares},
+	}
+}
+
+func (m *BatchKeyGen2) BytesAppend(existing []byte) ([]byte, error) {
+	if len(m.Shares) == 0 || len(m.Shares) > MaxBatchSize {
+		return nil, fmt.Errorf("batch msg2: invalid batch size %d: %w", len(m.Shares), ErrInvalidMessage)
+	}
+	existing = append(existing, 0, 0)
+	binary.BigEndian.PutUint16(existing[len(existing)-batchCountSize:], uint16(len(m.Shares)))
+	for k := range m.Shares {
+		existing = append(existing, m.Shares[k].Bytes()...)
+	}
+	return existing, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (m *BatchKeyGen2) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, m.Size())
+	return m.BytesAppend(buf)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (m *BatchKeyGen2) UnmarshalBinary(data []byte) error {
+	if len(data) < batchCountSize {
+		return fmt.Errorf("batch msg2: %w", ErrInvalidMessage)
+	}
+	count := int(binary.BigEndian.Uint16(data))
+	if count == 0 {
+		return fmt.Errorf("batch msg2: empty batch: %w", ErrInvalidMessage)
+	}
+	data = data[batchCountSize:]
+	if len(data) != 32*count {
+		return fmt.Errorf("batch msg2: %w", ErrInvalidMessage)
+	}
+
+	m.Shares = make([]ristretto.Scalar, count)
+	for k := range m.Shares {
+		if _, err := m.Shares[k].SetCanonicalBytes(data[:32]); err != nil {
+			return fmt.Errorf("batch msg2.Shares[%d]: %w", k, err)
+		}
+		data = data[32:]
+	}
+	return nil
+}
+
+func (m *BatchKeyGen2) Size() int {
+	return batchCountSize + 32*len(m.Shares)
+}
+
+func (m *BatchKeyGen2) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*BatchKeyGen2)
+	if !ok {
+		return false
+	}
+	if len(m.Shares) != len(otherMsg.Shares) {
+		return false
+	}
+	for k := range m.Shares {
+		if otherMsg.Shares[k].Equal(&m.Shares[k]) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+type jsonBatchKeyGen2 struct {
+	Shares [][]byte `json:"shares"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (m *BatchKeyGen2) MarshalJSON() ([]byte, error) {
+	shares := make([][]byte, len(m.Shares))
+	for k := range m.Shares {
+		shares[k] = m.Shares[k].Bytes()
+	}
+	return json.Marshal(jsonBatchKeyGen2{Shares: shares})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (m *BatchKeyGen2) UnmarshalJSON(data []byte) error {
+	var out jsonBatchKeyGen2
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+	return m.setShares(out.Shares)
+}
+
+// MarshalCBOR returns the CBOR array [[shares...]].
+func (m *BatchKeyGen2) MarshalCBOR() ([]byte, error) {
+	w := cbor.NewWriter(make([]byte, 0, 10+34*len(m.Shares)))
+	w.Array(1)
+	w.Array(len(m.Shares))
+	for k := range m.Shares {
+		w.ByteString(m.Shares[k].Bytes())
+	}
+	return w.Bytes(), nil
+}
+
+// UnmarshalCBOR decodes a message encoded by MarshalCBOR.
+func (m *BatchKeyGen2) UnmarshalCBOR(data []byte) error {
+	r := cbor.NewReader(data)
+	r.ArrayOf(1)
+	shares := make([][]byte, r.Array())
+	for k := range shares {
+		shares[k] = r.ByteString()
+	}
+	if err := r.Finish(); err != nil {
+		return fmt.Errorf("batch msg2: %w", err)
+	}
+	return m.setShares(shares)
+}
+
+// setShares sets the shares from their canonical encodings, and leaves m unchanged if one of them is invalid.
+func (m *BatchKeyGen2) setShares(encoded [][]byte) error {
+	if len(encoded) == 0 || len(encoded) > MaxBatchSize {
+		return fmt.Errorf("batch msg2: invalid batch size %d: %w", len(encoded), ErrInvalidMessage)
+	}
+	shares := make([]ristretto.Scalar, len(encoded))
+	for k := range shares {
+		if err := setScalar(&shares[k], encoded[k], fmt.Sprintf("batch msg2.Shares[%d]", k)); err != nil {
+			return err
+		}
+	}
+	m.Shares = shares
+	return nil
+}
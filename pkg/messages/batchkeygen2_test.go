@@ -0,0 +1,28 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+func TestBatchKeyGen2_MarshalBinary(t *testing.T) {
+	from := party.RandID()
+	to := party.RandID()
+	shares := make([]ristretto.Scalar, 7)
+	for k := range shares {
+		scalar.SetScalarRandom(&shares[k], nil)
+	}
+
+	msg := NewBatchKeyGen2(from, to, shares)
+
+	var msg2 Message
+	require.NoError(t, CheckFROSTMarshaler(msg, &msg2))
+	require.NoError(t, CheckJSONMarshaler(msg, &Message{}))
+	require.NoError(t, CheckCBORMarshaler(msg, &Message{}))
+	assert.True(t, msg2.Equal(msg), "messages are not equal")
+}
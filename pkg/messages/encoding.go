@@ -0,0 +1,214 @@
+package messages
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/cbor"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+// CBORMarshaler is implemented by payloads which define their own CBOR encoding.
+// Payloads which do not implement it are encoded as a CBOR byte string containing their binary encoding.
+type CBORMarshaler interface {
+	MarshalCBOR() ([]byte, error)
+	UnmarshalCBOR(data []byte) error
+}
+
+// jsonMessage is the JSON representation of a Message.
+// Payloads which do not implement json.Marshaler are encoded as the base64 string of their binary encoding.
+type jsonMessage struct {
+	Version   uint8           `json:"version"`
+	Suite     Suite           `json:"suite"`
+	SessionID SessionID       `json:"session"`
+	Type      string          `json:"type"`
+	From      party.ID        `json:"from"`
+	To        party.ID        `json:"to"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (m *Message) MarshalJSON() ([]byte, error) {
+	if err := m.Header.check(); err != nil {
+		return nil, fmt.Errorf("message.MarshalJSON: %w", err)
+	}
+	if m.Payload == nil {
+		return nil, errors.New("message.MarshalJSON: message does not contain any data")
+	}
+
+	var payload []byte
+	var err error
+	if marshaler, ok := m.Payload.(json.Marshaler); ok {
+		payload, err = marshaler.MarshalJSON()
+	} else {
+		var data []byte
+		if data, err = m.Payload.MarshalBinary(); err == nil {
+			payload, err = json.Marshal(data)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("message.MarshalJSON: %w", err)
+	}
+
+	return json.Marshal(jsonMessage{
+		Version:   WireVersion,
+		Suite:     CurrentSuite,
+		SessionID: m.SessionID,
+		Type:      m.Type.String(),
+		From:      m.From,
+		To:        m.To,
+		Payload:   payload,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The header is validated as in UnmarshalBinary, and all points and scalars of the payload must be canonical.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var out jsonMessage
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+	if out.Version != WireVersion {
+		return fmt.Errorf("message.UnmarshalJSON: got version %d, expected %d: %w", out.Version, WireVersion, ErrUnsupportedVersion)
+	}
+	if out.Suite != CurrentSuite {
+		return fmt.Errorf("message.UnmarshalJSON: got suite %d, expected %d: %w", out.Suite, CurrentSuite, ErrUnsupportedSuite)
+	}
+	msgType, ok := LookupName(out.Type)
+	if !ok {
+		return fmt.Errorf("message.UnmarshalJSON: unknown message type %q", out.Type)
+	}
+	header := Header{
+		Type:      msgType,
+		From:      out.From,
+		To:        out.To,
+		SessionID: out.SessionID,
+	}
+	if err := header.check(); err != nil {
+		return fmt.Errorf("message.UnmarshalJSON: %w", err)
+	}
+	if len(out.Payload) == 0 || string(out.Payload) == "null" {
+		return errors.New("message.UnmarshalJSON: message does not contain any data")
+	}
+
+	info, _ := Lookup(msgType)
+	payload := info.NewPayload()
+	var err error
+	if unmarshaler, ok := payload.(json.Unmarshaler); ok {
+		err = unmarshaler.UnmarshalJSON(out.Payload)
+	} else {
+		var data []byte
+		if err = json.Unmarshal(out.Payload, &data); err == nil {
+			err = payload.UnmarshalBinary(data)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("message.UnmarshalJSON: %s: %w", info.Name, err)
+	}
+
+	m.Header = header
+	m.Payload = payload
+	return nil
+}
+
+// MarshalCBOR returns the CBOR array [version, suite, session ID, type, from, to, payload].
+func (m *Message) MarshalCBOR() ([]byte, error) {
+	if err := m.Header.check(); err != nil {
+		return nil, fmt.Errorf("message.MarshalCBOR: %w", err)
+	}
+	if m.Payload == nil {
+		return nil, errors.New("message.MarshalCBOR: message does not contain any data")
+	}
+
+	w := cbor.NewWriter(make([]byte, 0, headerSize+16+m.Payload.Size()))
+	w.Array(7)
+	w.Uint(uint64(WireVersion))
+	w.Uint(uint64(CurrentSuite))
+	w.ByteString(m.SessionID[:])
+	w.Uint(uint64(m.Type))
+	w.Uint(uint64(m.From))
+	w.Uint(uint64(m.To))
+	if marshaler, ok := m.Payload.(CBORMarshaler); ok {
+		payload, err := marshaler.MarshalCBOR()
+		if err != nil {
+			return nil, fmt.Errorf("message.MarshalCBOR: %w", err)
+		}
+		w.Raw(payload)
+	} else {
+		payload, err := m.Payload.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("message.MarshalCBOR: %w", err)
+		}
+		w.ByteString(payload)
+	}
+	return w.Bytes(), nil
+}
+
+// UnmarshalCBOR decodes a message encoded by MarshalCBOR.
+// Only the deterministic encoding is accepted, and all points and scalars of the payload must be canonical.
+func (m *Message) UnmarshalCBOR(data []byte) error {
+	r := cbor.NewReader(data)
+	r.ArrayOf(7)
+	version, suite := r.Uint(), r.Uint()
+	sessionID := r.ByteString()
+	msgType, from, to := r.Uint(), r.Uint(), r.Uint()
+	payloadData := r.Raw()
+	if err := r.Finish(); err != nil {
+		return fmt.Errorf("message.UnmarshalCBOR: %w", err)
+	}
+
+	if version != uint64(WireVersion) {
+		return fmt.Errorf("message.UnmarshalCBOR: got version %d, expected %d: %w", version, WireVersion, ErrUnsupportedVersion)
+	}
+	if suite != uint64(CurrentSuite) {
+		return fmt.Errorf("message.UnmarshalCBOR: got suite %d, expected %d: %w", suite, CurrentSuite, ErrUnsupportedSuite)
+	}
+	if len(sessionID) != SessionIDSize {
+		return fmt.Errorf("message.UnmarshalCBOR: session ID must be %d bytes: %w", SessionIDSize, ErrInvalidMessage)
+	}
+	if msgType > 0xff || from > math.MaxUint16 || to > math.MaxUint16 {
+		return fmt.Errorf("message.UnmarshalCBOR: header field out of range: %w", ErrInvalidMessage)
+	}
+	header := Header{
+		Type: MessageType(msgType),
+		From: party.ID(from),
+		To:   party.ID(to),
+	}
+	copy(header.SessionID[:], sessionID)
+	if err := header.check(); err != nil {
+		return fmt.Errorf("message.UnmarshalCBOR: %w", err)
+	}
+
+	info, _ := Lookup(header.Type)
+	payload := info.NewPayload()
+	var err error
+	if unmarshaler, ok := payload.(CBORMarshaler); ok {
+		err = unmarshaler.UnmarshalCBOR(payloadData)
+	} else {
+		r = cbor.NewReader(payloadData)
+		binary := r.ByteString()
+		if err = r.Finish(); err == nil {
+			err = payload.UnmarshalBinary(binary)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("message.UnmarshalCBOR: %s: %w", info.Name, err)
+	}
+
+	m.Header = header
+	m.Payload = payload
+	return nil
+}
+
+// setScalar sets s to the canonical encoding data, and leaves it unchanged if data is invalid.
+func setScalar(s *ristretto.Scalar, data []byte, field string) error {
+	var decoded ristretto.Scalar
+	if _, err := decoded.SetCanonicalBytes(data); err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	s.Set(&decoded)
+	return nil
+}
@@ -0,0 +1,156 @@
+package messages
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/cbor"
+)
+
+func TestMessage_JSON(t *testing.T) {
+	msg := goldenMessages()[MessageTypeSign2]
+	data, err := json.Marshal(msg)
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	assert.Equal(t, float64(WireVersion), fields["version"])
+	assert.Equal(t, float64(CurrentSuite), fields["suite"])
+	assert.Equal(t, goldenSession.String(), fields["session"])
+	assert.Equal(t, "sign2", fields["type"])
+	assert.Equal(t, "3", fields["from"])
+	assert.Equal(t, map[string]interface{}{
+		"z": base64.StdEncoding.EncodeToString(msg.Payload.(*Sign2).Zi.Bytes()),
+	}, fields["payload"])
+
+	var decoded Message
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, msg.Equal(&decoded))
+}
+
+func TestMessage_JSON_Invalid(t *testing.T) {
+	valid, err := json.Marshal(goldenMessages()[MessageTypeSign1])
+	require.NoError(t, err)
+
+	nonCanonical := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{0xff}, 32))
+	tests := []struct {
+		name   string
+		modify func(fields map[string]interface{})
+		err    error
+	}{
+		{"version", func(f map[string]interface{}) { f["version"] = WireVersion + 1 }, ErrUnsupportedVersion},
+		{"suite", func(f map[string]interface{}) { f["suite"] = 42 }, ErrUnsupportedSuite},
+		{"type", func(f map[string]interface{}) { f["type"] = "sign3" }, nil},
+		{"broadcast with receiver", func(f map[string]interface{}) { f["to"] = "2" }, nil},
+		{"no sender", func(f map[string]interface{}) { f["from"] = "0" }, nil},
+		{"session", func(f map[string]interface{}) { f["session"] = "0102" }, nil},
+		{"missing payload", func(f map[string]interface{}) { delete(f, "payload") }, nil},
+		{"non canonical point", func(f map[string]interface{}) {
+			f["payload"].(map[string]interface{})["d"] = nonCanonical
+		}, nil},
+		{"short point", func(f map[string]interface{}) {
+			f["payload"].(map[string]interface{})["e"] = "AAAA"
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields map[string]interface{}
+			require.NoError(t, json.Unmarshal(valid, &fields))
+			tt.modify(fields)
+			data, err := json.Marshal(fields)
+			require.NoError(t, err)
+
+			var msg Message
+			err = json.Unmarshal(data, &msg)
+			require.Error(t, err)
+			if tt.err != nil {
+				assert.True(t, errors.Is(err, tt.err), "got %v", err)
+			}
+		})
+	}
+
+	var share KeyGen2
+	assert.Error(t, share.UnmarshalJSON([]byte(`{"share":"`+nonCanonical+`"}`)), "non canonical scalar")
+	var batch BatchKeyGen2
+	assert.Error(t, batch.UnmarshalJSON([]byte(`{"shares":[]}`)), "empty batch")
+	var keygen1 KeyGen1
+	assert.Error(t, keygen1.UnmarshalJSON([]byte(`{"proof":null,"commitments":null}`)), "missing fields")
+}
+
+func TestMessage_CBOR_Invalid(t *testing.T) {
+	msg := goldenMessages()[MessageTypeKeyGen2]
+	valid, err := msg.MarshalCBOR()
+	require.NoError(t, err)
+
+	var decoded Message
+	require.NoError(t, decoded.UnmarshalCBOR(valid))
+
+	// [version, suite, session, type, from, to, payload]
+	encode := func(version, suite uint64, session []byte, msgType, from, to uint64, payload []byte) []byte {
+		w := cbor.NewWriter(nil)
+		w.Array(7)
+		w.Uint(version)
+		w.Uint(suite)
+		w.ByteString(session)
+		w.Uint(msgType)
+		w.Uint(from)
+		w.Uint(to)
+		w.Raw(payload)
+		return w.Bytes()
+	}
+	payload, err := msg.Payload.(*KeyGen2).MarshalCBOR()
+	require.NoError(t, err)
+	session := goldenSession[:]
+	kg2 := uint64(MessageTypeKeyGen2)
+	require.NoError(t, decoded.UnmarshalCBOR(encode(1, 1, session, kg2, 1, 2, payload)))
+
+	nonCanonicalScalar := cbor.NewWriter(nil)
+	nonCanonicalScalar.Array(1)
+	nonCanonicalScalar.ByteString(bytes.Repeat([]byte{0xff}, 32))
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"version", encode(2, 1, session, kg2, 1, 2, payload), ErrUnsupportedVersion},
+		{"suite", encode(1, 2, session, kg2, 1, 2, payload), ErrUnsupportedSuite},
+		{"session", encode(1, 1, session[:8], kg2, 1, 2, payload), ErrInvalidMessage},
+		{"from overflow", encode(1, 1, session, kg2, 1<<16, 2, payload), ErrInvalidMessage},
+		{"point-to-point without receiver", encode(1, 1, session, kg2, 1, 0, payload), nil},
+		{"unknown type", encode(1, 1, session, 100, 1, 2, payload), nil},
+		{"non canonical scalar", encode(1, 1, session, kg2, 1, 2, nonCanonicalScalar.Bytes()), nil},
+		{"trailing data", append(append([]byte{}, valid...), 0), cbor.ErrTrailingData},
+		{"truncated", valid[:len(valid)-1], cbor.ErrTruncated},
+		{"non canonical integer", bytes.Replace(valid, []byte{0x87, 0x01}, []byte{0x87, 0x18, 0x01}, 1), cbor.ErrNotCanonical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var msg Message
+			err := msg.UnmarshalCBOR(tt.data)
+			require.Error(t, err)
+			if tt.err != nil {
+				assert.True(t, errors.Is(err, tt.err), "got %v", err)
+			}
+		})
+	}
+}
+
+func TestMessage_Encodings_CustomPayload(t *testing.T) {
+	msg := &Message{
+		Header:  Header{Type: messageTypeTest, From: 1, To: 2, SessionID: goldenSession},
+		Payload: &testPayload{Data: []byte("no JSON or CBOR")},
+	}
+	require.NoError(t, CheckJSONMarshaler(msg, &Message{}))
+	require.NoError(t, CheckCBORMarshaler(msg, &Message{}))
+
+	data, err := msg.MarshalJSON()
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(data), base64.StdEncoding.EncodeToString([]byte("no JSON or CBOR"))))
+}
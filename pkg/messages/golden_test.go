@@ -0,0 +1,101 @@
+package messages
+
+import (
+	"encoding/hex"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/zk"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+// The golden files fix the encoding of every built-in message type for the current WireVersion.
+// They must only be regenerated, with
+//
+//   go test ./pkg/messages -run TestGolden -update
+//
+// when WireVersion is increased.
+var update = flag.Bool("update", false, "update the golden files in testdata/")
+
+var goldenSession = SessionID{0x46, 0x52, 0x4f, 0x53, 0x54, 0x2d, 0x47, 0x4f, 0x4c, 0x44, 0x45, 0x4e, 0, 0, 0, 1}
+
+func goldenScalar(x uint32) *ristretto.Scalar {
+	return scalar.NewScalarUInt32(x)
+}
+
+func goldenElement(x uint32) *ristretto.Element {
+	return new(ristretto.Element).ScalarBaseMult(goldenScalar(x))
+}
+
+func goldenCommitments(degree party.Size, offset uint32) *polynomial.Exponent {
+	data := degree.Bytes()
+	for i := uint32(0); i <= uint32(degree); i++ {
+		data = append(data, goldenScalar(offset+i).Bytes()...)
+	}
+	var poly polynomial.Polynomial
+	if err := poly.UnmarshalBinary(data); err != nil {
+		panic(err)
+	}
+	return polynomial.NewPolynomialExponent(&poly)
+}
+
+func goldenProof(x uint32) *zk.Schnorr {
+	return &zk.Schnorr{S: *goldenScalar(x), R: *goldenScalar(x + 1)}
+}
+
+func goldenMessages() map[MessageType]*Message {
+	msgs := map[MessageType]*Message{
+		MessageTypeKeyGen1:      NewKeyGen1(1, goldenProof(1), goldenCommitments(2, 10)),
+		MessageTypeKeyGen2:      NewKeyGen2(1, 2, goldenScalar(42)),
+		MessageTypeSign1:        NewSign1(3, goldenElement(5), goldenElement(6)),
+		MessageTypeSign2:        NewSign2(3, goldenScalar(7)),
+		MessageTypeBatchKeyGen1: NewBatchKeyGen1(4, []*zk.Schnorr{goldenProof(1), goldenProof(3)}, []*polynomial.Exponent{goldenCommitments(1, 20), goldenCommitments(1, 30)}),
+		MessageTypeBatchKeyGen2: NewBatchKeyGen2(4, 5, []ristretto.Scalar{*goldenScalar(8), *goldenScalar(9)}),
+	}
+	for _, msg := range msgs {
+		msg.SessionID = goldenSession
+	}
+	return msgs
+}
+
+func TestGolden(t *testing.T) {
+	msgs := goldenMessages()
+
+	for msgType := MessageTypeNone + 1; msgType < MessageTypeFirstCustom; msgType++ {
+		if _, ok := Lookup(msgType); !ok {
+			continue
+		}
+		msg, ok := msgs[msgType]
+		require.True(t, ok, "missing golden message for type %s", msgType)
+
+		t.Run(msgType.String(), func(t *testing.T) {
+			path := filepath.Join("testdata", msgType.String()+".golden")
+			data, err := msg.MarshalBinary()
+			require.NoError(t, err)
+
+			if *update {
+				require.NoError(t, ioutil.WriteFile(path, []byte(hex.EncodeToString(data)+"\n"), 0644))
+			}
+
+			golden, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			expected, err := hex.DecodeString(strings.TrimSpace(string(golden)))
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, data, "encoding changed, WireVersion must be increased")
+
+			var decoded Message
+			require.NoError(t, decoded.UnmarshalBinary(expected))
+			assert.True(t, msg.Equal(&decoded))
+			assert.Equal(t, goldenSession, decoded.SessionID)
+		})
+	}
+}
@@ -1,13 +1,80 @@
 package messages
 
 import (
+	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 )
 
-const headerSize = 1 + 2*party.IDByteSize
+// The encoding of a Header is
+//
+//   version (1) || suite (2) || session ID (16) || type (1) || from (2) || to (2)
+//
+// Compatibility policy:
+//   - WireVersion is increased whenever the encoding of the header or of any existing payload changes.
+//     Messages with a different version are rejected with ErrUnsupportedVersion, there is no negotiation.
+//   - The Suite identifies the group, hash function and challenge computation.
+//     Parties using different suites cannot produce valid signatures together, and their messages are rejected with ErrUnsupportedSuite.
+//   - Registering new message types does not change the version, since unknown types are rejected by older decoders.
+//   - The golden vectors in testdata/ fix the encoding of every built-in message type for the current version,
+//     and must only be regenerated together with an increase of WireVersion.
+const headerSize = 1 + 2 + SessionIDSize + 1 + 2*party.IDByteSize
+
+// WireVersion is the version of the message encoding produced and accepted by this package.
+const WireVersion uint8 = 1
+
+// Suite identifies the cryptographic primitives used by the protocol.
+type Suite uint16
+
+const (
+	// SuiteFROSTRistretto255SHA512 is FROST over the ristretto255 group,
+	// with SHA-512 challenges computed as in Ed25519.
+	SuiteFROSTRistretto255SHA512 Suite = 1
+
+	// CurrentSuite is the only Suite supported by this version of the package.
+	CurrentSuite = SuiteFROSTRistretto255SHA512
+)
+
+// SessionIDSize is the size in bytes of a SessionID.
+const SessionIDSize = 16
+
+// SessionID identifies a single execution of a protocol.
+// All parties must agree on it beforehand, so that messages from another execution are rejected.
+type SessionID [SessionIDSize]byte
+
+// String returns the hexadecimal encoding of the SessionID.
+func (id SessionID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// MarshalText implements encoding/TextMarshaler interface
+func (id SessionID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding/TextMarshaler interface
+func (id *SessionID) UnmarshalText(text []byte) error {
+	if hex.DecodedLen(len(text)) != SessionIDSize {
+		return fmt.Errorf("SessionID.UnmarshalText: expected %d hexadecimal characters", 2*SessionIDSize)
+	}
+	var decoded SessionID
+	if _, err := hex.Decode(decoded[:], text); err != nil {
+		return fmt.Errorf("SessionID.UnmarshalText: %w", err)
+	}
+	*id = decoded
+	return nil
+}
+
+var (
+	// ErrUnsupportedVersion is returned when decoding a message encoded with a different WireVersion.
+	ErrUnsupportedVersion = errors.New("unsupported wire format version")
+
+	// ErrUnsupportedSuite is returned when decoding a message for a different Suite.
+	ErrUnsupportedSuite = errors.New("unsupported suite")
+)
 
 type Header struct {
 	// Type is the message type
@@ -21,6 +88,10 @@ type Header struct {
 	// If the message is intended for broadcast, the ID returned is 0 (invalid),
 	// therefore, you should call IsBroadcast() first.
 	To party.ID
+
+	// SessionID is the session the message belongs to.
+	// It is set by the state.State which generated the message.
+	SessionID SessionID
 }
 
 func (h *Header) MarshalBinary() (data []byte, err error) {
@@ -33,6 +104,16 @@ func (h *Header) UnmarshalBinary(data []byte) error {
 		return fmt.Errorf("Header.UnmarshalBinary: data should be at least %d bytes (got %d)", headerSize, l)
 	}
 
+	if version := data[0]; version != WireVersion {
+		return fmt.Errorf("Header.UnmarshalBinary: got version %d, expected %d: %w", version, WireVersion, ErrUnsupportedVersion)
+	}
+	if suite := Suite(binary.BigEndian.Uint16(data[1:])); suite != CurrentSuite {
+		return fmt.Errorf("Header.UnmarshalBinary: got suite %d, expected %d: %w", suite, CurrentSuite, ErrUnsupportedSuite)
+	}
+	var sessionID SessionID
+	copy(sessionID[:], data[3:])
+	data = data[3+SessionIDSize:]
+
 	msgType := MessageType(data[0])
 	var (
 		from, to party.ID
@@ -42,53 +123,55 @@ func (h *Header) UnmarshalBinary(data []byte) error {
 		return fmt.Errorf("Header.UnmarshalBinary: from: %w", err)
 	}
 	if to, err = party.FromBytes(data[1+party.IDByteSize:]); err != nil {
-		return fmt.Errorf("Header.UnmarshalBinary: from: %w", err)
+		return fmt.Errorf("Header.UnmarshalBinary: to: %w", err)
 	}
 
-	switch msgType {
-	case MessageTypeKeyGen1, MessageTypeSign1, MessageTypeSign2:
-		if to != 0 {
-			return errors.New("Header.UnmarshalBinary: .To field must be 0 to indicate broadcast")
-		}
-	case MessageTypeKeyGen2:
-		if to == 0 {
-			return errors.New("Header.UnmarshalBinary: MessageTypeKeyGen2 requires a sender (.To field)")
-		}
-	default:
-		return errors.New("Header.UnmarshalBinary: invalid message type")
+	decoded := Header{
+		Type:      msgType,
+		From:      from,
+		To:        to,
+		SessionID: sessionID,
 	}
-	if from == 0 {
-		return errors.New("Header.UnmarshalBinary: message must include a non 0 From value")
+	if err = decoded.check(); err != nil {
+		return fmt.Errorf("Header.UnmarshalBinary: %w", err)
 	}
 
-	h.Type = msgType
-	h.From = from
-	h.To = to
+	*h = decoded
 	return nil
 }
 
 func (h *Header) BytesAppend(existing []byte) (data []byte, err error) {
-	switch h.Type {
-	case MessageTypeKeyGen1, MessageTypeSign1, MessageTypeSign2:
-		if h.To != 0 {
-			return nil, errors.New("Header.BytesAppend: .To field must be 0 to indicate broadcast")
-		}
-	case MessageTypeKeyGen2:
-		if h.To == 0 {
-			return nil, errors.New("Header.BytesAppend: MessageTypeKeyGen2 requires a sender (.To field)")
-		}
-	default:
-		return nil, errors.New("Header.BytesAppend: invalid message type")
-	}
-	if h.From == 0 {
-		return nil, errors.New("Header.BytesAppend: message must include a non 0 From value")
+	if err = h.check(); err != nil {
+		return nil, fmt.Errorf("Header.BytesAppend: %w", err)
 	}
+	existing = append(existing, WireVersion, 0, 0)
+	binary.BigEndian.PutUint16(existing[len(existing)-2:], uint16(CurrentSuite))
+	existing = append(existing, h.SessionID[:]...)
 	existing = append(existing, byte(h.Type))
 	existing = append(existing, h.From.Bytes()...)
 	existing = append(existing, h.To.Bytes()...)
 	return existing, nil
 }
 
+// check verifies that the type is registered, that the sender is set,
+// and that the receiver is consistent with the routing rule of the type.
+func (h *Header) check() error {
+	info, ok := Lookup(h.Type)
+	if !ok {
+		return errors.New("invalid message type")
+	}
+	if info.Broadcast && h.To != 0 {
+		return errors.New(".To field must be 0 to indicate broadcast")
+	}
+	if !info.Broadcast && h.To == 0 {
+		return fmt.Errorf("%s requires a receiver (.To field)", info.Name)
+	}
+	if h.From == 0 {
+		return errors.New("message must include a non 0 From value")
+	}
+	return nil
+}
+
 func (h *Header) Size() int {
 	return headerSize
 }
@@ -1,8 +1,12 @@
 package messages
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 )
 
@@ -120,7 +124,7 @@ func TestHeader_UnmarshalBinary(t *testing.T) {
 				To:   tt.fields.To,
 			}
 			h2 := &Header{}
-			err := h2.UnmarshalBinary(tt.args.data)
+			err := h2.UnmarshalBinary(append(headerPrefix(), tt.args.data...))
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UnmarshalBinary() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -130,3 +134,33 @@ func TestHeader_UnmarshalBinary(t *testing.T) {
 		})
 	}
 }
+
+// headerPrefix returns the version, suite and an empty session ID.
+func headerPrefix() []byte {
+	return append([]byte{WireVersion, 0, byte(CurrentSuite)}, make([]byte, SessionIDSize)...)
+}
+
+func TestHeader_Versioning(t *testing.T) {
+	h := Header{
+		Type:      MessageTypeSign1,
+		From:      3,
+		SessionID: SessionID{1, 2, 3},
+	}
+	data, err := h.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, h.Size())
+
+	var h2 Header
+	require.NoError(t, h2.UnmarshalBinary(data))
+	assert.True(t, h.Equal(h2))
+
+	badVersion := append([]byte{}, data...)
+	badVersion[0] = WireVersion + 1
+	err = h2.UnmarshalBinary(badVersion)
+	assert.True(t, errors.Is(err, ErrUnsupportedVersion), "got %v", err)
+
+	badSuite := append([]byte{}, data...)
+	badSuite[2] = byte(CurrentSuite + 1)
+	err = h2.UnmarshalBinary(badSuite)
+	assert.True(t, errors.Is(err, ErrUnsupportedSuite), "got %v", err)
+}
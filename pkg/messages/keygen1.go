@@ -1,13 +1,23 @@
 package messages
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/cbor"
 	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
 	"github.com/taurusgroup/frost-ed25519/pkg/internal/zk"
 )
 
+func init() {
+	Register(MessageTypeKeyGen1, TypeInfo{
+		Name:       "keygen1",
+		Broadcast:  true,
+		NewPayload: func() FROSTMarshaler { return new(KeyGen1) },
+	})
+}
+
 type KeyGen1 struct {
 	Proof       *zk.Schnorr
 	Commitments *polynomial.Exponent
@@ -19,7 +29,7 @@ func NewKeyGen1(from party.ID, proof *zk.Schnorr, commitments *polynomial.Expone
 			Type: MessageTypeKeyGen1,
 			From: from,
 		},
-		KeyGen1: &KeyGen1{
+		Payload: &KeyGen1{
 			Proof:       proof,
 			Commitments: commitments,
 		},
@@ -81,3 +91,66 @@ func (m *KeyGen1) Equal(other interface{}) bool {
 	}
 	return true
 }
+
+type jsonKeyGen1 struct {
+	Proof       *zk.Schnorr          `json:"proof"`
+	Commitments *polynomial.Exponent `json:"commitments"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (m *KeyGen1) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonKeyGen1{
+		Proof:       m.Proof,
+		Commitments: m.Commitments,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (m *KeyGen1) UnmarshalJSON(data []byte) error {
+	var out jsonKeyGen1
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+	if out.Proof == nil || out.Commitments == nil {
+		return fmt.Errorf("msg1: missing field: %w", ErrInvalidMessage)
+	}
+	m.Proof, m.Commitments = out.Proof, out.Commitments
+	return nil
+}
+
+// MarshalCBOR returns the CBOR array [proof, commitments].
+func (m *KeyGen1) MarshalCBOR() ([]byte, error) {
+	proof, err := m.Proof.MarshalCBOR()
+	if err != nil {
+		return nil, err
+	}
+	commitments, err := m.Commitments.MarshalCBOR()
+	if err != nil {
+		return nil, err
+	}
+	w := cbor.NewWriter(make([]byte, 0, 1+len(proof)+len(commitments)))
+	w.Array(2)
+	w.Raw(proof)
+	w.Raw(commitments)
+	return w.Bytes(), nil
+}
+
+// UnmarshalCBOR decodes a message encoded by MarshalCBOR.
+func (m *KeyGen1) UnmarshalCBOR(data []byte) error {
+	r := cbor.NewReader(data)
+	r.ArrayOf(2)
+	proofData, commitmentsData := r.Raw(), r.Raw()
+	if err := r.Finish(); err != nil {
+		return fmt.Errorf("msg1: %w", err)
+	}
+	var proof zk.Schnorr
+	if err := proof.UnmarshalCBOR(proofData); err != nil {
+		return fmt.Errorf("msg1.Proof: %w", err)
+	}
+	var commitments polynomial.Exponent
+	if err := commitments.UnmarshalCBOR(commitmentsData); err != nil {
+		return fmt.Errorf("msg1.Commitments: %w", err)
+	}
+	m.Proof, m.Commitments = &proof, &commitments
+	return nil
+}
@@ -17,14 +17,16 @@ func TestKeyGen1_MarshalBinary(t *testing.T) {
 	secret := scalar.NewScalarRandom()
 	context := make([]byte, 32)
 
-	poly := polynomial.NewPolynomial(party.Size(deg), secret)
+	poly := polynomial.NewPolynomial(party.Size(deg), secret, nil)
 	comm := polynomial.NewPolynomialExponent(poly)
 
-	proof := zk.NewSchnorrProof(from, comm.Constant(), context, poly.Constant())
+	proof := zk.NewSchnorrProof(from, comm.Constant(), context, poly.Constant(), nil)
 
 	msg := NewKeyGen1(from, proof, comm)
 
 	var msg2 Message
 	require.NoError(t, CheckFROSTMarshaler(msg, &msg2))
+	require.NoError(t, CheckJSONMarshaler(msg, &Message{}))
+	require.NoError(t, CheckCBORMarshaler(msg, &Message{}))
 	assert.True(t, msg2.Equal(msg), "messages are not equal")
 }
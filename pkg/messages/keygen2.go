@@ -1,14 +1,24 @@
 package messages
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/cbor"
 	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
 )
 
 const sizeKeygen2 = 32
 
+func init() {
+	Register(MessageTypeKeyGen2, TypeInfo{
+		Name:       "keygen2",
+		Broadcast:  false,
+		NewPayload: func() FROSTMarshaler { return new(KeyGen2) },
+	})
+}
+
 type KeyGen2 struct {
 	// Share is a Shamir additive share for the destination party
 	Share ristretto.Scalar
@@ -21,7 +31,7 @@ func NewKeyGen2(from, to party.ID, share *ristretto.Scalar) *Message {
 			From: from,
 			To:   to,
 		},
-		KeyGen2: &KeyGen2{Share: *share},
+		Payload: &KeyGen2{Share: *share},
 	}
 }
 
@@ -59,3 +69,40 @@ func (m *KeyGen2) Equal(other interface{}) bool {
 	}
 	return true
 }
+
+type jsonKeyGen2 struct {
+	Share []byte `json:"share"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (m *KeyGen2) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonKeyGen2{Share: m.Share.Bytes()})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (m *KeyGen2) UnmarshalJSON(data []byte) error {
+	var out jsonKeyGen2
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+	return setScalar(&m.Share, out.Share, "msg2.Share")
+}
+
+// MarshalCBOR returns the CBOR array [share].
+func (m *KeyGen2) MarshalCBOR() ([]byte, error) {
+	w := cbor.NewWriter(make([]byte, 0, 1+34))
+	w.Array(1)
+	w.ByteString(m.Share.Bytes())
+	return w.Bytes(), nil
+}
+
+// UnmarshalCBOR decodes a message encoded by MarshalCBOR.
+func (m *KeyGen2) UnmarshalCBOR(data []byte) error {
+	r := cbor.NewReader(data)
+	r.ArrayOf(1)
+	share := r.ByteString()
+	if err := r.Finish(); err != nil {
+		return fmt.Errorf("msg2: %w", err)
+	}
+	return setScalar(&m.Share, share, "msg2.Share")
+}
@@ -3,6 +3,7 @@ package messages
 import (
 	"bytes"
 	"encoding"
+	"encoding/json"
 	"fmt"
 )
 
@@ -63,3 +64,64 @@ func CheckFROSTMarshaler(input, output FROSTMarshaler) error {
 	}
 	return nil
 }
+
+// JSONMarshaler is a FROSTMarshaler which also has a JSON encoding.
+type JSONMarshaler interface {
+	FROSTMarshaler
+	json.Marshaler
+	json.Unmarshaler
+}
+
+// CheckJSONMarshaler verifies that input survives a round trip through its JSON encoding,
+// and that the encoding is deterministic.
+// Should be used for tests of message types.
+func CheckJSONMarshaler(input, output JSONMarshaler) error {
+	firstData, err := input.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("failed to marshall struct to JSON: %w", err)
+	}
+	if err = output.UnmarshalJSON(firstData); err != nil {
+		return fmt.Errorf("failed to unmarshall JSON data: %w", err)
+	}
+	if !input.Equal(output) {
+		return fmt.Errorf("decoded JSON struct should be equal to the input")
+	}
+	secondData, err := output.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("failed to marshall struct of unmarshalled JSON: %w", err)
+	}
+	if !bytes.Equal(firstData, secondData) {
+		return fmt.Errorf("both JSON outputs should be the same")
+	}
+	return nil
+}
+
+// CBORFROSTMarshaler is a FROSTMarshaler which also has a CBOR encoding.
+type CBORFROSTMarshaler interface {
+	FROSTMarshaler
+	CBORMarshaler
+}
+
+// CheckCBORMarshaler verifies that input survives a round trip through its CBOR encoding,
+// and that the encoding is deterministic.
+// Should be used for tests of message types.
+func CheckCBORMarshaler(input, output CBORFROSTMarshaler) error {
+	firstData, err := input.MarshalCBOR()
+	if err != nil {
+		return fmt.Errorf("failed to marshall struct to CBOR: %w", err)
+	}
+	if err = output.UnmarshalCBOR(firstData); err != nil {
+		return fmt.Errorf("failed to unmarshall CBOR data: %w", err)
+	}
+	if !input.Equal(output) {
+		return fmt.Errorf("decoded CBOR struct should be equal to the input")
+	}
+	secondData, err := output.MarshalCBOR()
+	if err != nil {
+		return fmt.Errorf("failed to marshall struct of unmarshalled CBOR: %w", err)
+	}
+	if !bytes.Equal(firstData, secondData) {
+		return fmt.Errorf("both CBOR outputs should be the same")
+	}
+	return nil
+}
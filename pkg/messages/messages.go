@@ -7,10 +7,11 @@ import (
 
 type Message struct {
 	Header
-	KeyGen1 *KeyGen1
-	KeyGen2 *KeyGen2
-	Sign1   *Sign1
-	Sign2   *Sign2
+
+	// Payload is the content of the message.
+	// Its concrete type is the one returned by the NewPayload function registered for Header.Type,
+	// for example *KeyGen1 for MessageTypeKeyGen1.
+	Payload FROSTMarshaler
 }
 
 var ErrInvalidMessage = errors.New("invalid message")
@@ -24,55 +25,37 @@ const (
 	MessageTypeKeyGen2
 	MessageTypeSign1
 	MessageTypeSign2
+	MessageTypeBatchKeyGen1
+	MessageTypeBatchKeyGen2
 )
 
+// String returns a short lower-case name of the message type, suitable for logs and metric labels.
+func (t MessageType) String() string {
+	if t == MessageTypeNone {
+		return "none"
+	}
+	if info, ok := Lookup(t); ok {
+		return info.Name
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(t))
+}
+
 func (m *Message) BytesAppend(existing []byte) (data []byte, err error) {
 	existing, err = m.Header.BytesAppend(existing)
 	if err != nil {
 		return nil, fmt.Errorf("message.BytesAppend: %w", err)
 	}
 
-	switch m.Type {
-	case MessageTypeKeyGen1:
-		if m.KeyGen1 != nil {
-			return m.KeyGen1.BytesAppend(existing)
-		}
-	case MessageTypeKeyGen2:
-		if m.KeyGen2 != nil {
-			return m.KeyGen2.BytesAppend(existing)
-		}
-	case MessageTypeSign1:
-		if m.Sign1 != nil {
-			return m.Sign1.BytesAppend(existing)
-		}
-	case MessageTypeSign2:
-		if m.Sign2 != nil {
-			return m.Sign2.BytesAppend(existing)
-		}
+	if m.Payload == nil {
+		return nil, errors.New("message does not contain any data")
 	}
-
-	return nil, errors.New("message does not contain any data")
+	return m.Payload.BytesAppend(existing)
 }
 
 func (m *Message) Size() int {
 	var size int
-	switch m.Type {
-	case MessageTypeKeyGen1:
-		if m.KeyGen1 != nil {
-			size = m.KeyGen1.Size()
-		}
-	case MessageTypeKeyGen2:
-		if m.KeyGen2 != nil {
-			size = m.KeyGen2.Size()
-		}
-	case MessageTypeSign1:
-		if m.Sign1 != nil {
-			size = m.Sign1.Size()
-		}
-	case MessageTypeSign2:
-		if m.Sign2 != nil {
-			size = m.Sign2.Size()
-		}
+	if m.Payload != nil {
+		size = m.Payload.Size()
 	}
 	return m.Header.Size() + size
 }
@@ -92,32 +75,18 @@ func (m *Message) UnmarshalBinary(data []byte) error {
 	}
 	data = data[m.Header.Size():]
 
-	switch m.Type {
-	case MessageTypeKeyGen1:
-		var keygen1 KeyGen1
-		if err = keygen1.UnmarshalBinary(data); err == nil {
-			m.KeyGen1 = &keygen1
-		}
-
-	case MessageTypeKeyGen2:
-		var keygen2 KeyGen2
-		if err = keygen2.UnmarshalBinary(data); err == nil {
-			m.KeyGen2 = &keygen2
-		}
-
-	case MessageTypeSign1:
-		var sign1 Sign1
-		if err = sign1.UnmarshalBinary(data); err == nil {
-			m.Sign1 = &sign1
-		}
-	case MessageTypeSign2:
-		var sign2 Sign2
-		if err = sign2.UnmarshalBinary(data); err == nil {
-			m.Sign2 = &sign2
-		}
-	default:
+	info, ok := Lookup(m.Type)
+	if !ok {
 		return errors.New("messages.UnmarshalBinary: invalid message type")
 	}
+	payload := info.NewPayload()
+	if err = payload.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("messages.UnmarshalBinary: %s: %w", info.Name, err)
+	}
+	if payload.Size() != len(data) {
+		return fmt.Errorf("messages.UnmarshalBinary: %s: %w", info.Name, ErrTrailingData)
+	}
+	m.Payload = payload
 
 	return nil
 }
@@ -132,23 +101,8 @@ func (m *Message) Equal(other interface{}) bool {
 		return false
 	}
 
-	switch m.Type {
-	case MessageTypeKeyGen1:
-		if m.KeyGen1 != nil && otherMsg.KeyGen1 != nil {
-			return m.KeyGen1.Equal(otherMsg.KeyGen1)
-		}
-	case MessageTypeKeyGen2:
-		if m.KeyGen2 != nil && otherMsg.KeyGen2 != nil {
-			return m.KeyGen2.Equal(otherMsg.KeyGen2)
-		}
-	case MessageTypeSign1:
-		if m.Sign1 != nil && otherMsg.Sign1 != nil {
-			return m.Sign1.Equal(otherMsg.Sign1)
-		}
-	case MessageTypeSign2:
-		if m.Sign2 != nil && otherMsg.Sign2 != nil {
-			return m.Sign2.Equal(otherMsg.Sign2)
-		}
+	if m.Payload != nil && otherMsg.Payload != nil {
+		return m.Payload.Equal(otherMsg.Payload)
 	}
 	return false
 }
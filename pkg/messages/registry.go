@@ -0,0 +1,68 @@
+package messages
+
+import (
+	"fmt"
+	"sync"
+)
+
+// MessageTypeFirstCustom is the first MessageType available to protocols defined outside of this module.
+// Types below it are reserved for the protocols implemented in pkg/frost.
+const MessageTypeFirstCustom MessageType = 128
+
+// TypeInfo describes how messages of a given MessageType are routed and encoded.
+type TypeInfo struct {
+	// Name is a short lower-case name of the type, returned by MessageType.String.
+	Name string
+
+	// Broadcast is true if messages of this type are sent to all parties, in which case Header.To must be 0.
+	// Otherwise, messages are sent to the single party Header.To.
+	Broadcast bool
+
+	// NewPayload returns an empty payload, which is filled by calling UnmarshalBinary on the message's content.
+	NewPayload func() FROSTMarshaler
+}
+
+var (
+	registryMtx   sync.RWMutex
+	registry      = map[MessageType]TypeInfo{}
+	registryNames = map[string]MessageType{}
+)
+
+// Register makes a message type available for encoding and decoding Message s.
+// It is meant to be called from the init function of the package defining the protocol,
+// and panics if msgType is MessageTypeNone, if it was already registered, or if info is incomplete.
+func Register(msgType MessageType, info TypeInfo) {
+	if msgType == MessageTypeNone {
+		panic("messages: cannot register MessageTypeNone")
+	}
+	if info.Name == "" || info.NewPayload == nil {
+		panic(fmt.Sprintf("messages: incomplete TypeInfo for type %d", uint8(msgType)))
+	}
+
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+	if existing, ok := registry[msgType]; ok {
+		panic(fmt.Sprintf("messages: type %d registered twice (%s and %s)", uint8(msgType), existing.Name, info.Name))
+	}
+	if _, ok := registryNames[info.Name]; ok {
+		panic(fmt.Sprintf("messages: name %s registered twice", info.Name))
+	}
+	registry[msgType] = info
+	registryNames[info.Name] = msgType
+}
+
+// Lookup returns the TypeInfo registered for msgType.
+func Lookup(msgType MessageType) (TypeInfo, bool) {
+	registryMtx.RLock()
+	defer registryMtx.RUnlock()
+	info, ok := registry[msgType]
+	return info, ok
+}
+
+// LookupName returns the MessageType registered with the given name.
+func LookupName(name string) (MessageType, bool) {
+	registryMtx.RLock()
+	defer registryMtx.RUnlock()
+	msgType, ok := registryNames[name]
+	return msgType, ok
+}
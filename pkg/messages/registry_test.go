@@ -0,0 +1,92 @@
+package messages
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+)
+
+const messageTypeTest = MessageTypeFirstCustom + 1
+
+// testPayload is a payload of arbitrary bytes used to test custom message types.
+type testPayload struct {
+	Data []byte
+}
+
+func (m *testPayload) BytesAppend(existing []byte) ([]byte, error) {
+	return append(existing, m.Data...), nil
+}
+
+func (m *testPayload) MarshalBinary() ([]byte, error) {
+	return m.BytesAppend(nil)
+}
+
+func (m *testPayload) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return ErrInvalidMessage
+	}
+	m.Data = append([]byte{}, data...)
+	return nil
+}
+
+func (m *testPayload) Size() int {
+	return len(m.Data)
+}
+
+func (m *testPayload) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*testPayload)
+	return ok && bytes.Equal(m.Data, otherMsg.Data)
+}
+
+func init() {
+	Register(messageTypeTest, TypeInfo{
+		Name:       "test",
+		Broadcast:  false,
+		NewPayload: func() FROSTMarshaler { return new(testPayload) },
+	})
+}
+
+func TestRegister_Custom(t *testing.T) {
+	assert.Equal(t, "test", messageTypeTest.String())
+	assert.Equal(t, "unknown(255)", MessageType(255).String())
+
+	msg := &Message{
+		Header:  Header{Type: messageTypeTest, From: 1, To: 2},
+		Payload: &testPayload{Data: []byte("hello")},
+	}
+	var decoded Message
+	require.NoError(t, CheckFROSTMarshaler(msg, &decoded))
+	assert.True(t, msg.Equal(&decoded))
+	assert.IsType(t, &testPayload{}, decoded.Payload)
+
+	// point-to-point messages require a receiver
+	msg.To = 0
+	_, err := msg.MarshalBinary()
+	assert.Error(t, err)
+}
+
+func TestRegister_Invalid(t *testing.T) {
+	info := TypeInfo{
+		Name:       "other",
+		NewPayload: func() FROSTMarshaler { return new(testPayload) },
+	}
+	assert.Panics(t, func() { Register(messageTypeTest, info) }, "duplicate type")
+	assert.Panics(t, func() { Register(MessageTypeKeyGen1, info) }, "built-in type")
+	assert.Panics(t, func() { Register(MessageTypeNone, info) }, "none type")
+	assert.Panics(t, func() { Register(messageTypeTest+1, TypeInfo{Name: "incomplete"}) }, "missing NewPayload")
+}
+
+func TestMessage_UnmarshalBinary_InvalidPayload(t *testing.T) {
+	data := append(headerPrefix(), byte(messageTypeTest))
+	data = append(data, party.ID(1).Bytes()...)
+	data = append(data, party.ID(2).Bytes()...)
+
+	var msg Message
+	assert.Error(t, msg.UnmarshalBinary(data), "an empty payload is rejected")
+
+	data[len(data)-1-2*party.IDByteSize] = byte(MessageTypeFirstCustom + 100)
+	assert.Error(t, msg.UnmarshalBinary(data), "unregistered type is rejected")
+}
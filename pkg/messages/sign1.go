@@ -1,14 +1,24 @@
 package messages
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/cbor"
 	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
 )
 
 const sizeSign1 = 32 + 32
 
+func init() {
+	Register(MessageTypeSign1, TypeInfo{
+		Name:       "sign1",
+		Broadcast:  true,
+		NewPayload: func() FROSTMarshaler { return new(Sign1) },
+	})
+}
+
 type Sign1 struct {
 	// Di = [di] B
 	// Ei = [ei] B
@@ -21,7 +31,7 @@ func NewSign1(from party.ID, commitmentD, commitmentE *ristretto.Element) *Messa
 			Type: MessageTypeSign1,
 			From: from,
 		},
-		Sign1: &Sign1{
+		Payload: &Sign1{
 			Di: *commitmentD,
 			Ei: *commitmentE,
 		},
@@ -78,3 +88,59 @@ func (m *Sign1) Equal(other interface{}) bool {
 	}
 	return true
 }
+
+type jsonSign1 struct {
+	Di []byte `json:"d"`
+	Ei []byte `json:"e"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (m *Sign1) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonSign1{
+		Di: m.Di.Bytes(),
+		Ei: m.Ei.Bytes(),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (m *Sign1) UnmarshalJSON(data []byte) error {
+	var out jsonSign1
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+	return m.setElements(out.Di, out.Ei)
+}
+
+// MarshalCBOR returns the CBOR array [Di, Ei].
+func (m *Sign1) MarshalCBOR() ([]byte, error) {
+	w := cbor.NewWriter(make([]byte, 0, 1+2*34))
+	w.Array(2)
+	w.ByteString(m.Di.Bytes())
+	w.ByteString(m.Ei.Bytes())
+	return w.Bytes(), nil
+}
+
+// UnmarshalCBOR decodes a message encoded by MarshalCBOR.
+func (m *Sign1) UnmarshalCBOR(data []byte) error {
+	r := cbor.NewReader(data)
+	r.ArrayOf(2)
+	Di, Ei := r.ByteString(), r.ByteString()
+	if err := r.Finish(); err != nil {
+		return fmt.Errorf("msg1: %w", err)
+	}
+	return m.setElements(Di, Ei)
+}
+
+// setElements sets Di and Ei from their canonical encodings, and leaves m unchanged if one of them is invalid.
+func (m *Sign1) setElements(Di, Ei []byte) error {
+	var decoded Sign1
+	if _, err := decoded.Di.SetCanonicalBytes(Di); err != nil {
+		return fmt.Errorf("msg1.D: %w", err)
+	}
+	if _, err := decoded.Ei.SetCanonicalBytes(Ei); err != nil {
+		return fmt.Errorf("msg1.E: %w", err)
+	}
+	m.Di.Set(&decoded.Di)
+	m.Ei.Set(&decoded.Ei)
+	return nil
+}
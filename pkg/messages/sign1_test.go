@@ -21,5 +21,7 @@ func TestSign1_MarshalBinary(t *testing.T) {
 
 	var msgDec Message
 	require.NoError(t, CheckFROSTMarshaler(msg, &msgDec))
+	require.NoError(t, CheckJSONMarshaler(msg, &Message{}))
+	require.NoError(t, CheckCBORMarshaler(msg, &Message{}))
 	require.True(t, msg.Equal(&msgDec), "messages are not equal")
 }
@@ -1,14 +1,24 @@
 package messages
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/cbor"
 	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
 )
 
 const sizeSign2 = 32
 
+func init() {
+	Register(MessageTypeSign2, TypeInfo{
+		Name:       "sign2",
+		Broadcast:  true,
+		NewPayload: func() FROSTMarshaler { return new(Sign2) },
+	})
+}
+
 type Sign2 struct {
 	// Zi is a ristretto.Scalar.
 	// It represents the sender's share of the 's' part of the final signature
@@ -21,7 +31,7 @@ func NewSign2(from party.ID, signatureShare *ristretto.Scalar) *Message {
 			Type: MessageTypeSign2,
 			From: from,
 		},
-		Sign2: &Sign2{Zi: *signatureShare},
+		Payload: &Sign2{Zi: *signatureShare},
 	}
 }
 
@@ -63,3 +73,40 @@ func (m *Sign2) Equal(other interface{}) bool {
 	}
 	return true
 }
+
+type jsonSign2 struct {
+	Zi []byte `json:"z"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (m *Sign2) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonSign2{Zi: m.Zi.Bytes()})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (m *Sign2) UnmarshalJSON(data []byte) error {
+	var out jsonSign2
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+	return setScalar(&m.Zi, out.Zi, "msg2.Zi")
+}
+
+// MarshalCBOR returns the CBOR array [Zi].
+func (m *Sign2) MarshalCBOR() ([]byte, error) {
+	w := cbor.NewWriter(make([]byte, 0, 1+34))
+	w.Array(1)
+	w.ByteString(m.Zi.Bytes())
+	return w.Bytes(), nil
+}
+
+// UnmarshalCBOR decodes a message encoded by MarshalCBOR.
+func (m *Sign2) UnmarshalCBOR(data []byte) error {
+	r := cbor.NewReader(data)
+	r.ArrayOf(1)
+	Zi := r.ByteString()
+	if err := r.Finish(); err != nil {
+		return fmt.Errorf("msg2: %w", err)
+	}
+	return setScalar(&m.Zi, Zi, "msg2.Zi")
+}
@@ -17,5 +17,7 @@ func TestSign2_MarshalBinary(t *testing.T) {
 
 	var msg2 Message
 	require.NoError(t, CheckFROSTMarshaler(msg, &msg2))
+	require.NoError(t, CheckJSONMarshaler(msg, &Message{}))
+	require.NoError(t, CheckCBORMarshaler(msg, &Message{}))
 	assert.Equal(t, *msg, msg2, "messages are not equal")
 }
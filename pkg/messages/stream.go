@@ -0,0 +1,177 @@
+package messages
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+)
+
+// frameLengthSize is the size of the big-endian length prefix of each frame.
+const frameLengthSize = 4
+
+// MaxFrameSize is an upper bound on the size of any frame handled by an Encoder or Decoder,
+// regardless of the limit they were created with.
+const MaxFrameSize = 1 << 24
+
+var (
+	// ErrFrameTooLarge is returned when a message is larger than the limit of the Encoder or Decoder.
+	ErrFrameTooLarge = errors.New("frame exceeds the maximum message size")
+
+	// ErrFrameTruncated is returned when the stream ends in the middle of a frame.
+	ErrFrameTruncated = errors.New("truncated frame")
+
+	// ErrTrailingData is returned when a frame contains more data than the message it encodes.
+	ErrTrailingData = errors.New("trailing data after message")
+)
+
+// MaxMessageSize returns the size of the largest message exchanged by the keygen and sign protocols
+// between n parties with threshold t. It can be used as the limit of an Encoder or Decoder.
+func MaxMessageSize(n, t party.Size) int {
+	if n > 0 && t >= n {
+		t = n - 1
+	}
+	// KeyGen1 contains a Schnorr proof and the commitments to a polynomial of degree t,
+	// and is larger than all the other messages.
+	keygen1 := 64 + party.IDByteSize + 32*(int(t)+1)
+	size := keygen1
+	for _, other := range []int{sizeKeygen2, sizeSign1, sizeSign2} {
+		if other > size {
+			size = other
+		}
+	}
+	return headerSize + size
+}
+
+// MaxBatchMessageSize is the equivalent of MaxMessageSize for a batch keygen generating batchSize keys.
+func MaxBatchMessageSize(n, t party.Size, batchSize int) int {
+	if n > 0 && t >= n {
+		t = n - 1
+	}
+	batch1 := batchCountSize + batchSize*(64+party.IDByteSize+32*(int(t)+1))
+	batch2 := batchCountSize + 32*batchSize
+	if batch2 > batch1 {
+		return headerSize + batch2
+	}
+	return headerSize + batch1
+}
+
+// An Encoder writes Message s to a stream, each one prefixed by its length as a 4 byte big-endian integer.
+type Encoder struct {
+	w       io.Writer
+	maxSize int
+	buf     []byte
+}
+
+// NewEncoder returns an Encoder writing to w, which refuses to encode messages larger than maxSize bytes.
+// If maxSize is not positive, or larger than MaxFrameSize, then MaxFrameSize is used.
+func NewEncoder(w io.Writer, maxSize int) *Encoder {
+	return &Encoder{
+		w:       w,
+		maxSize: frameLimit(maxSize),
+	}
+}
+
+// Encode writes a single frame containing msg, with one call to Write.
+func (e *Encoder) Encode(msg *Message) error {
+	size := msg.Size()
+	if size > e.maxSize {
+		return fmt.Errorf("messages.Encoder: message of %d bytes (limit %d): %w", size, e.maxSize, ErrFrameTooLarge)
+	}
+
+	buf := append(e.buf[:0], 0, 0, 0, 0)
+	buf, err := msg.BytesAppend(buf)
+	if err != nil {
+		return fmt.Errorf("messages.Encoder: %w", err)
+	}
+	binary.BigEndian.PutUint32(buf, uint32(len(buf)-frameLengthSize))
+	e.buf = buf
+
+	if _, err = e.w.Write(buf); err != nil {
+		return fmt.Errorf("messages.Encoder: %w", err)
+	}
+	return nil
+}
+
+// A Decoder reads Message s written by an Encoder.
+//
+// Once Decode has returned an error, the position in the stream is unknown,
+// and all subsequent calls return the same error.
+type Decoder struct {
+	r       io.Reader
+	maxSize int
+	buf     []byte
+	err     error
+}
+
+// NewDecoder returns a Decoder reading from r, which rejects frames larger than maxSize bytes
+// without reading them.
+// If maxSize is not positive, or larger than MaxFrameSize, then MaxFrameSize is used.
+func NewDecoder(r io.Reader, maxSize int) *Decoder {
+	return &Decoder{
+		r:       r,
+		maxSize: frameLimit(maxSize),
+	}
+}
+
+// Decode reads the next frame and returns the message it contains.
+//
+// It returns io.EOF if the stream ended cleanly before a new frame,
+// an error wrapping ErrFrameTruncated if it ended in the middle of a frame,
+// ErrFrameTooLarge if the announced length exceeds the limit,
+// and ErrTrailingData if the message does not span the whole frame.
+func (d *Decoder) Decode() (*Message, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	msg, err := d.decode()
+	if err != nil {
+		d.err = err
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (d *Decoder) decode() (*Message, error) {
+	var prefix [frameLengthSize]byte
+	if _, err := io.ReadFull(d.r, prefix[:]); err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("messages.Decoder: length prefix: %w", ErrFrameTruncated)
+		}
+		return nil, fmt.Errorf("messages.Decoder: %w", err)
+	}
+
+	size := binary.BigEndian.Uint32(prefix[:])
+	if uint64(size) > uint64(d.maxSize) {
+		return nil, fmt.Errorf("messages.Decoder: frame of %d bytes (limit %d): %w", size, d.maxSize, ErrFrameTooLarge)
+	}
+
+	if cap(d.buf) < int(size) {
+		d.buf = make([]byte, size)
+	}
+	frame := d.buf[:size]
+	if _, err := io.ReadFull(d.r, frame); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("messages.Decoder: frame of %d bytes: %w", size, ErrFrameTruncated)
+		}
+		return nil, fmt.Errorf("messages.Decoder: %w", err)
+	}
+
+	var msg Message
+	if err := msg.UnmarshalBinary(frame); err != nil {
+		return nil, fmt.Errorf("messages.Decoder: %w", err)
+	}
+	return &msg, nil
+}
+
+func frameLimit(maxSize int) int {
+	if maxSize <= 0 || maxSize > MaxFrameSize {
+		return MaxFrameSize
+	}
+	return maxSize
+}
@@ -0,0 +1,155 @@
+package messages
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func frame(t *testing.T, msg *Message) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, NewEncoder(&buf, 0).Encode(msg))
+	return buf.Bytes()
+}
+
+func TestStream_RoundTrip(t *testing.T) {
+	msgs := goldenMessages()
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, MaxBatchMessageSize(5, 2, 2))
+	for msgType := MessageTypeKeyGen1; msgType <= MessageTypeBatchKeyGen2; msgType++ {
+		require.NoError(t, enc.Encode(msgs[msgType]))
+	}
+
+	dec := NewDecoder(&buf, MaxBatchMessageSize(5, 2, 2))
+	for msgType := MessageTypeKeyGen1; msgType <= MessageTypeBatchKeyGen2; msgType++ {
+		msg, err := dec.Decode()
+		require.NoError(t, err)
+		assert.True(t, msgs[msgType].Equal(msg), "type %s", msgType)
+	}
+	_, err := dec.Decode()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestStream_Pipe(t *testing.T) {
+	msg := goldenMessages()[MessageTypeSign1]
+	r, w := io.Pipe()
+	go func() {
+		enc := NewEncoder(w, 0)
+		for i := 0; i < 3; i++ {
+			_ = enc.Encode(msg)
+		}
+		_ = w.Close()
+	}()
+
+	dec := NewDecoder(r, 0)
+	for i := 0; i < 3; i++ {
+		decoded, err := dec.Decode()
+		require.NoError(t, err)
+		assert.True(t, msg.Equal(decoded))
+	}
+	_, err := dec.Decode()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestStream_Errors(t *testing.T) {
+	msg := goldenMessages()[MessageTypeKeyGen1]
+	data := frame(t, msg)
+
+	t.Run("truncated prefix", func(t *testing.T) {
+		_, err := NewDecoder(bytes.NewReader(data[:2]), 0).Decode()
+		assert.True(t, errors.Is(err, ErrFrameTruncated), "got %v", err)
+	})
+
+	t.Run("truncated frame", func(t *testing.T) {
+		dec := NewDecoder(bytes.NewReader(data[:len(data)-1]), 0)
+		_, err := dec.Decode()
+		assert.True(t, errors.Is(err, ErrFrameTruncated), "got %v", err)
+		_, err2 := dec.Decode()
+		assert.Equal(t, err, err2, "errors should be sticky")
+	})
+
+	t.Run("oversized", func(t *testing.T) {
+		limit := MaxMessageSize(3, 1)
+		require.Greater(t, msg.Size(), limit)
+		_, err := NewDecoder(bytes.NewReader(data), limit).Decode()
+		assert.True(t, errors.Is(err, ErrFrameTooLarge), "got %v", err)
+
+		err = NewEncoder(ioutil.Discard, limit).Encode(msg)
+		assert.True(t, errors.Is(err, ErrFrameTooLarge), "got %v", err)
+	})
+
+	t.Run("trailing data", func(t *testing.T) {
+		garbage := append(append([]byte{}, data...), 0xff)
+		binary.BigEndian.PutUint32(garbage, binary.BigEndian.Uint32(garbage)+1)
+		_, err := NewDecoder(bytes.NewReader(garbage), 0).Decode()
+		assert.Error(t, err)
+	})
+
+	t.Run("trailing data lenient payload", func(t *testing.T) {
+		data := frame(t, &Message{
+			Header:  Header{Type: messageTypeLenient, From: 1},
+			Payload: &lenientPayload{Value: 7},
+		})
+		garbage := append(append([]byte{}, data...), 1, 2, 3)
+		binary.BigEndian.PutUint32(garbage, binary.BigEndian.Uint32(garbage)+3)
+		_, err := NewDecoder(bytes.NewReader(garbage), 0).Decode()
+		assert.True(t, errors.Is(err, ErrTrailingData), "got %v", err)
+	})
+}
+
+func TestMaxMessageSize(t *testing.T) {
+	msg := goldenMessages()[MessageTypeKeyGen1]
+	// The golden KeyGen1 message has threshold 2
+	assert.Equal(t, msg.Size(), MaxMessageSize(5, 2))
+	assert.Equal(t, MaxMessageSize(3, 2), MaxMessageSize(3, 5), "threshold is bounded by the number of parties")
+
+	batch := goldenMessages()[MessageTypeBatchKeyGen1]
+	assert.Equal(t, batch.Size(), MaxBatchMessageSize(5, 1, 2))
+}
+
+const messageTypeLenient = MessageTypeFirstCustom + 2
+
+// lenientPayload ignores any data after its first byte.
+type lenientPayload struct {
+	Value byte
+}
+
+func (m *lenientPayload) BytesAppend(existing []byte) ([]byte, error) {
+	return append(existing, m.Value), nil
+}
+
+func (m *lenientPayload) MarshalBinary() ([]byte, error) {
+	return m.BytesAppend(nil)
+}
+
+func (m *lenientPayload) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return ErrInvalidMessage
+	}
+	m.Value = data[0]
+	return nil
+}
+
+func (m *lenientPayload) Size() int {
+	return 1
+}
+
+func (m *lenientPayload) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*lenientPayload)
+	return ok && m.Value == otherMsg.Value
+}
+
+func init() {
+	Register(messageTypeLenient, TypeInfo{
+		Name:       "lenient",
+		Broadcast:  true,
+		NewPayload: func() FROSTMarshaler { return new(lenientPayload) },
+	})
+}
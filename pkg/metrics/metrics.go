@@ -0,0 +1,312 @@
+// Package metrics provides a state.Observer which collects counters and latency histograms
+// of protocol executions, and exports them in the Prometheus text exposition format.
+//
+// A single Collector is usually created for the whole process, and a new Observer is obtained
+// for every State with Collector.Observer:
+//
+//	collector := metrics.NewCollector()
+//	http.Handle("/metrics", collector)
+//	s, output, err := frost.NewSignState(ctx, partyIDs, secret, shares, message, timeout,
+//		state.WithObserver(collector.Observer("sign")))
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// contentType is the content type of version 0.0.4 of the Prometheus text format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the upper bounds, in seconds, of the buckets of the duration histograms.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Results reported by the frost_sessions_finished_total counter.
+const (
+	ResultSuccess   = "success"
+	ResultTimeout   = "timeout"
+	ResultSuspended = "suspended"
+	ResultError     = "error"
+)
+
+// Collector aggregates the events reported by the Observer s it creates.
+// It is safe for concurrent use, and implements http.Handler to serve the collected metrics.
+type Collector struct {
+	mtx sync.Mutex
+
+	buckets []float64
+
+	accepted        map[labels]uint64
+	rejected        map[labels]uint64
+	timeouts        map[labels]uint64
+	finished        map[labels]uint64
+	active          map[labels]int64
+	roundDuration   map[labels]*histogram
+	sessionDuration map[labels]*histogram
+}
+
+// NewCollector returns an empty Collector using DefaultBuckets.
+func NewCollector() *Collector {
+	return &Collector{
+		buckets:         DefaultBuckets,
+		accepted:        map[labels]uint64{},
+		rejected:        map[labels]uint64{},
+		timeouts:        map[labels]uint64{},
+		finished:        map[labels]uint64{},
+		active:          map[labels]int64{},
+		roundDuration:   map[labels]*histogram{},
+		sessionDuration: map[labels]*histogram{},
+	}
+}
+
+// Observer returns a new state.Observer reporting to c, which should be given to exactly one State.
+// protocol is used as the value of the "protocol" label of all metrics, for example "keygen" or "sign".
+//
+// The session is considered active from the moment this method is called until the State finishes.
+func (c *Collector) Observer(protocol string) state.Observer {
+	c.mtx.Lock()
+	c.active[labels{protocol: protocol}]++
+	c.mtx.Unlock()
+	return &observer{
+		collector: c,
+		protocol:  protocol,
+		start:     time.Now(),
+	}
+}
+
+// ServeHTTP writes all metrics in the Prometheus text format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = c.WriteTo(w)
+}
+
+// WriteTo writes all metrics to w in the Prometheus text format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+
+	c.mtx.Lock()
+	writeCounter(&b, "frost_messages_accepted_total", "Number of messages accepted by a State.", c.accepted)
+	writeCounter(&b, "frost_messages_rejected_total", "Number of messages rejected by a State.", c.rejected)
+	writeCounter(&b, "frost_round_timeouts_total", "Number of sessions aborted because a round timed out.", c.timeouts)
+	writeCounter(&b, "frost_sessions_finished_total", "Number of sessions which finished, by result.", c.finished)
+	writeGauge(&b, "frost_sessions_active", "Number of sessions currently running.", c.active)
+	writeHistogram(&b, "frost_round_duration_seconds", "Time taken to complete a round.", c.buckets, c.roundDuration)
+	writeHistogram(&b, "frost_session_duration_seconds", "Time taken by a session until it finished.", c.buckets, c.sessionDuration)
+	c.mtx.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (c *Collector) observe(hists map[labels]*histogram, l labels, d time.Duration) {
+	h, ok := hists[l]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(c.buckets))}
+		hists[l] = h
+	}
+	h.observe(c.buckets, d.Seconds())
+}
+
+// observer reports the events of a single State to its Collector.
+type observer struct {
+	collector *Collector
+	protocol  string
+	start     time.Time
+}
+
+func (o *observer) MessageAccepted(_ int, msg *messages.Message) {
+	c := o.collector
+	c.mtx.Lock()
+	c.accepted[labels{protocol: o.protocol, msgType: msg.Type.String()}]++
+	c.mtx.Unlock()
+}
+
+func (o *observer) MessageRejected(_ int, msg *messages.Message, _ error) {
+	c := o.collector
+	c.mtx.Lock()
+	c.rejected[labels{protocol: o.protocol, msgType: msg.Type.String()}]++
+	c.mtx.Unlock()
+}
+
+func (o *observer) RoundCompleted(round int, duration time.Duration) {
+	c := o.collector
+	c.mtx.Lock()
+	c.observe(c.roundDuration, labels{protocol: o.protocol, round: strconv.Itoa(round)}, duration)
+	c.mtx.Unlock()
+}
+
+func (o *observer) TimedOut(round int) {
+	c := o.collector
+	c.mtx.Lock()
+	c.timeouts[labels{protocol: o.protocol, round: strconv.Itoa(round)}]++
+	c.mtx.Unlock()
+}
+
+func (o *observer) Finished(err error) {
+	var result string
+	var timeoutErr *state.TimeoutError
+	switch {
+	case err == nil:
+		result = ResultSuccess
+	case errors.As(err, &timeoutErr):
+		result = ResultTimeout
+	case errors.Is(err, state.ErrSuspended):
+		result = ResultSuspended
+	default:
+		result = ResultError
+	}
+
+	c := o.collector
+	c.mtx.Lock()
+	c.active[labels{protocol: o.protocol}]--
+	c.finished[labels{protocol: o.protocol, result: result}]++
+	c.observe(c.sessionDuration, labels{protocol: o.protocol}, time.Since(o.start))
+	c.mtx.Unlock()
+}
+
+// labels identifies a single series of a metric. Empty labels are omitted.
+type labels struct {
+	protocol, msgType, round, result string
+}
+
+func (l labels) pairs() [][2]string {
+	var pairs [][2]string
+	if l.protocol != "" {
+		pairs = append(pairs, [2]string{"protocol", l.protocol})
+	}
+	if l.msgType != "" {
+		pairs = append(pairs, [2]string{"type", l.msgType})
+	}
+	if l.round != "" {
+		pairs = append(pairs, [2]string{"round", l.round})
+	}
+	if l.result != "" {
+		pairs = append(pairs, [2]string{"result", l.result})
+	}
+	return pairs
+}
+
+// format returns the label set as `{name="value",...}` with extra appended at the end.
+func (l labels) format(extra ...[2]string) string {
+	pairs := append(l.pairs(), extra...)
+	if len(pairs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, p := range pairs {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(p[0])
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(p[1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+// sortLabels sorts the series of a metric so that the output is deterministic.
+func sortLabels(all []labels) []labels {
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].format() < all[j].format()
+	})
+	return all
+}
+
+func writeHeader(b *strings.Builder, name, help, kind string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeCounter(b *strings.Builder, name, help string, values map[labels]uint64) {
+	writeHeader(b, name, help, "counter")
+	all := make([]labels, 0, len(values))
+	for l := range values {
+		all = append(all, l)
+	}
+	for _, l := range sortLabels(all) {
+		fmt.Fprintf(b, "%s%s %d\n", name, l.format(), values[l])
+	}
+}
+
+func writeGauge(b *strings.Builder, name, help string, values map[labels]int64) {
+	writeHeader(b, name, help, "gauge")
+	all := make([]labels, 0, len(values))
+	for l := range values {
+		all = append(all, l)
+	}
+	for _, l := range sortLabels(all) {
+		fmt.Fprintf(b, "%s%s %d\n", name, l.format(), values[l])
+	}
+}
+
+func writeHistogram(b *strings.Builder, name, help string, buckets []float64, values map[labels]*histogram) {
+	writeHeader(b, name, help, "histogram")
+	all := make([]labels, 0, len(values))
+	for l := range values {
+		all = append(all, l)
+	}
+	for _, l := range sortLabels(all) {
+		h := values[l]
+		var cumulative uint64
+		for i, upper := range buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(b, "%s_bucket%s %d\n", name, l.format([2]string{"le", formatFloat(upper)}), cumulative)
+		}
+		fmt.Fprintf(b, "%s_bucket%s %d\n", name, l.format([2]string{"le", "+Inf"}), h.count)
+		fmt.Fprintf(b, "%s_sum%s %s\n", name, l.format(), formatFloat(h.sum))
+		fmt.Fprintf(b, "%s_count%s %d\n", name, l.format(), h.count)
+	}
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// histogram holds the non-cumulative count of observations for each bucket.
+// Observations larger than the last bucket are only counted in count.
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func (h *histogram) observe(buckets []float64, v float64) {
+	h.count++
+	h.sum += v
+	for i, upper := range buckets {
+		if v <= upper {
+			h.counts[i]++
+			return
+		}
+	}
+}
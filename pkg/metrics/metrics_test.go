@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func TestCollector_Histogram(t *testing.T) {
+	c := NewCollector()
+	o := c.Observer("sign")
+	o.RoundCompleted(0, 3*time.Millisecond)
+	o.RoundCompleted(0, 200*time.Millisecond)
+	o.RoundCompleted(0, 2*time.Minute)
+
+	var b strings.Builder
+	_, err := c.WriteTo(&b)
+	require.NoError(t, err)
+	out := b.String()
+
+	assert.Contains(t, out, "# TYPE frost_round_duration_seconds histogram\n")
+	assert.Contains(t, out, `frost_round_duration_seconds_bucket{protocol="sign",round="0",le="0.005"} 1`+"\n")
+	assert.Contains(t, out, `frost_round_duration_seconds_bucket{protocol="sign",round="0",le="0.1"} 1`+"\n")
+	assert.Contains(t, out, `frost_round_duration_seconds_bucket{protocol="sign",round="0",le="0.25"} 2`+"\n")
+	assert.Contains(t, out, `frost_round_duration_seconds_bucket{protocol="sign",round="0",le="60"} 2`+"\n")
+	assert.Contains(t, out, `frost_round_duration_seconds_bucket{protocol="sign",round="0",le="+Inf"} 3`+"\n")
+	assert.Contains(t, out, `frost_round_duration_seconds_sum{protocol="sign",round="0"} 120.203`+"\n")
+	assert.Contains(t, out, `frost_round_duration_seconds_count{protocol="sign",round="0"} 3`+"\n")
+	assert.Contains(t, out, `frost_sessions_active{protocol="sign"} 1`+"\n")
+}
+
+func TestCollector_Results(t *testing.T) {
+	c := NewCollector()
+	c.Observer("keygen").Finished(nil)
+	c.Observer("keygen").Finished(state.NewError(0, state.ErrSuspended))
+	c.Observer("keygen").Finished(errors.New("abort"))
+	o := c.Observer("keygen")
+	o.TimedOut(1)
+	o.Finished(state.NewError(0, &state.TimeoutError{RoundNumber: 1}))
+
+	var b strings.Builder
+	_, err := c.WriteTo(&b)
+	require.NoError(t, err)
+	out := b.String()
+	for _, result := range []string{ResultSuccess, ResultSuspended, ResultError, ResultTimeout} {
+		assert.Contains(t, out, `frost_sessions_finished_total{protocol="keygen",result="`+result+`"} 1`)
+	}
+	assert.Contains(t, out, `frost_sessions_active{protocol="keygen"} 0`)
+	assert.Contains(t, out, `frost_round_timeouts_total{protocol="keygen",round="1"} 1`)
+}
+
+func TestCollector_EscapeLabels(t *testing.T) {
+	c := NewCollector()
+	o := c.Observer("a\"b\\c\nd")
+	o.MessageAccepted(0, &messages.Message{Header: messages.Header{Type: messages.MessageTypeSign1}})
+
+	var b strings.Builder
+	_, err := c.WriteTo(&b)
+	require.NoError(t, err)
+	assert.Contains(t, b.String(), `frost_messages_accepted_total{protocol="a\"b\\c\nd",type="sign1"} 1`)
+}
+
+func TestCollector_ServeHTTP(t *testing.T) {
+	c := NewCollector()
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, contentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "# TYPE frost_messages_accepted_total counter\n")
+}
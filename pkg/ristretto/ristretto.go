@@ -443,22 +443,45 @@ func (e *Element) String() string {
 	return string(result)
 }
 
+// eightInv is the representation of 8^{-1} mod q.
+var eightInv, _ = edwards25519.NewScalar().SetCanonicalBytes([]byte{
+	121, 47, 220, 226, 41, 229, 6, 97,
+	208, 218, 28, 125, 179, 157, 211, 7,
+	0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 6,
+})
+
+// clearTorsion sets p to [8^{-1}][8]q, the point of prime order in the coset of q.
+func clearTorsion(p, q *edwards25519.Point) *edwards25519.Point {
+	p.MultByCofactor(q)
+	return p.ScalarMult(eightInv, p)
+}
+
 // BytesEd25519 returns the canonical byte representation of the underlying
 // edwards25519.Point, normalized with regard to the cofactor.
 func (e *Element) BytesEd25519() []byte {
 	// we can't just return the bytes of the underlying point, since it may not be of order 8.
 	// so we do [8^{-1}][8]P to clear any cofactor
 	var p edwards25519.Point
-	// this is the byte representation of 8^{-1} mod q
-	var eightInv, _ = edwards25519.NewScalar().SetCanonicalBytes([]byte{
-		121, 47, 220, 226, 41, 229, 6, 97,
-		208, 218, 28, 125, 179, 157, 211, 7,
-		0, 0, 0, 0, 0, 0, 0, 0,
-		0, 0, 0, 0, 0, 0, 0, 6,
-	})
-	p.Set(&e.r)
-	p.MultByCofactor(&p)
-	p.ScalarMult(eightInv, &p)
-
+	clearTorsion(&p, &e.r)
 	return p.Bytes()
 }
+
+// SetEd25519Bytes sets e to the element represented by the Ed25519 encoding of a point,
+// as returned by BytesEd25519, and returns e.
+// The encoding must be canonical, and the point must be in the prime order subgroup.
+// Otherwise, SetEd25519Bytes returns nil and an error, and e is unchanged.
+func (e *Element) SetEd25519Bytes(in []byte) (*Element, error) {
+	var p, q edwards25519.Point
+	if _, err := p.SetBytes(in); err != nil {
+		return nil, errInvalidEncoding
+	}
+	if !bytes.Equal(p.Bytes(), in) {
+		return nil, errInvalidEncoding
+	}
+	if clearTorsion(&q, &p).Equal(&p) != 1 {
+		return nil, errors.New("ristretto: point has a torsion component")
+	}
+	e.r.Set(&p)
+	return e, nil
+}
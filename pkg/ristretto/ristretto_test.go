@@ -9,6 +9,7 @@ import (
 	"math/big"
 	"testing"
 
+	"filippo.io/edwards25519"
 	"filippo.io/edwards25519/field"
 )
 
@@ -329,3 +330,38 @@ func testConstant(t *testing.T, f *field.Element, decimal string) {
 		t.Errorf("expected %x", buf)
 	}
 }
+
+func TestSetEd25519Bytes(t *testing.T) {
+	x := new(Element)
+	xbytes := sha512.Sum512([]byte("Hello World"))
+	_, _ = x.SetUniformBytes(xbytes[:])
+
+	y, err := new(Element).SetEd25519Bytes(x.BytesEd25519())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if y.Equal(x) != 1 {
+		t.Error("decode succeeded, but got wrong element")
+	}
+	if !bytes.Equal(y.BytesEd25519(), x.BytesEd25519()) {
+		t.Error("decode<>encode roundtrip produced different results")
+	}
+
+	// B + T where T = (0, -1) is the point of order 2
+	var withTorsion edwards25519.Point
+	order2Bytes, _ := hex.DecodeString("ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	order2, _ := new(edwards25519.Point).SetBytes(order2Bytes)
+	withTorsion.Add(edwards25519.NewGeneratorPoint(), order2)
+	if _, err = new(Element).SetEd25519Bytes(withTorsion.Bytes()); err == nil {
+		t.Error("a point with a torsion component was accepted")
+	}
+
+	// non-canonical encoding of y = 1, the identity
+	identityBytes, _ := hex.DecodeString("eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	if _, err = new(Element).SetEd25519Bytes(identityBytes); err == nil {
+		t.Error("a non-canonical encoding was accepted")
+	}
+	if _, err = new(Element).SetEd25519Bytes(make([]byte, 31)); err == nil {
+		t.Error("a short encoding was accepted")
+	}
+}
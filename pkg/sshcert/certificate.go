@@ -0,0 +1,339 @@
+package sshcert
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Types of certificates.
+const (
+	UserCert = 1
+	HostCert = 2
+)
+
+// CertTimeInfinity is the value of ValidBefore for certificates which do not expire.
+const CertTimeInfinity = math.MaxUint64
+
+// certSuffix is appended to the name of a key algorithm, without its @openssh.com domain, to obtain the certificate algorithm.
+const certSuffix = "-cert-v01@openssh.com"
+
+// keyFields is the number of fields after the name of the algorithm in the encoding of the keys which can be certified.
+var keyFields = map[string]int{
+	"ssh-rsa":                            2, // e, n
+	"ssh-dss":                            4, // p, q, g, y
+	"ecdsa-sha2-nistp256":                2, // curve, Q
+	"ecdsa-sha2-nistp384":                2,
+	"ecdsa-sha2-nistp521":                2,
+	"ssh-ed25519":                        1, // key
+	"sk-ecdsa-sha2-nistp256@openssh.com": 3, // curve, Q, application
+	"sk-ssh-ed25519@openssh.com":         2, // key, application
+}
+
+// certAlgo returns the name of the certificate algorithm for keys of the given algorithm.
+func certAlgo(keyAlgo string) string {
+	return strings.TrimSuffix(keyAlgo, "@openssh.com") + certSuffix
+}
+
+// keyAlgo returns the name of the key algorithm for the given certificate algorithm.
+func keyAlgo(name string) (string, bool) {
+	for algo := range keyFields {
+		if certAlgo(algo) == name {
+			return algo, true
+		}
+	}
+	return "", false
+}
+
+// Certificate is an OpenSSH certificate.
+type Certificate struct {
+	// Nonce is a random value which prevents signature collisions. It is set by Sign if it is empty.
+	Nonce []byte
+
+	// Key is the wire encoding of the certified key, as returned by ParseAuthorizedKey.
+	Key []byte
+
+	Serial   uint64
+	CertType uint32
+	KeyID    string
+
+	// ValidPrincipals are the user names or host names for which the certificate is valid.
+	// An empty list means any principal.
+	ValidPrincipals []string
+
+	// ValidAfter and ValidBefore are Unix times.
+	ValidAfter  uint64
+	ValidBefore uint64
+
+	// CriticalOptions and Extensions map names to values, which are empty for flags such as permit-pty.
+	CriticalOptions map[string]string
+	Extensions      map[string]string
+
+	// SignatureKey is the wire encoding of the key of the certificate authority.
+	SignatureKey []byte
+
+	// Signature is the wire encoding of the signature, which is empty if the certificate is not signed yet.
+	Signature []byte
+}
+
+// Algo returns the name of the certificate algorithm, such as ssh-ed25519-cert-v01@openssh.com.
+func (c *Certificate) Algo() (string, error) {
+	r := reader{data: c.Key}
+	algo := string(r.string())
+	if r.err != nil {
+		return "", fmt.Errorf("sshcert: invalid certified key: %w", r.err)
+	}
+	if _, ok := keyFields[algo]; !ok {
+		return "", fmt.Errorf("sshcert: unsupported key type %q", algo)
+	}
+	return certAlgo(algo), nil
+}
+
+// BytesForSigning returns the encoding of the certificate without its signature, which is the message signed by the CA.
+func (c *Certificate) BytesForSigning() ([]byte, error) {
+	algo, err := c.Algo()
+	if err != nil {
+		return nil, err
+	}
+	if len(c.SignatureKey) == 0 {
+		return nil, errors.New("sshcert: the certificate has no SignatureKey")
+	}
+	r := reader{data: c.Key}
+	_ = r.string()
+
+	out := appendString(nil, []byte(algo))
+	out = appendString(out, c.Nonce)
+	// the fields of the certified key, without its algorithm
+	out = append(out, r.data...)
+	out = appendUint64(out, c.Serial)
+	out = appendUint32(out, c.CertType)
+	out = appendString(out, []byte(c.KeyID))
+	var principals []byte
+	for _, p := range c.ValidPrincipals {
+		principals = appendString(principals, []byte(p))
+	}
+	out = appendString(out, principals)
+	out = appendUint64(out, c.ValidAfter)
+	out = appendUint64(out, c.ValidBefore)
+	out = appendString(out, marshalOptions(c.CriticalOptions))
+	out = appendString(out, marshalOptions(c.Extensions))
+	out = appendString(out, nil) // reserved
+	out = appendString(out, c.SignatureKey)
+	return out, nil
+}
+
+// Marshal returns the wire encoding of the signed certificate.
+func (c *Certificate) Marshal() ([]byte, error) {
+	if len(c.Signature) == 0 {
+		return nil, errors.New("sshcert: the certificate is not signed")
+	}
+	out, err := c.BytesForSigning()
+	if err != nil {
+		return nil, err
+	}
+	return appendString(out, c.Signature), nil
+}
+
+// MarshalAuthorizedKey returns the signed certificate in the format of a -cert.pub file.
+func (c *Certificate) MarshalAuthorizedKey(comment string) ([]byte, error) {
+	algo, err := c.Algo()
+	if err != nil {
+		return nil, err
+	}
+	blob, err := c.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return marshalAuthorizedKey(algo, blob, comment), nil
+}
+
+// SetSignature sets the Ed25519 signature of the certificate, as returned by ed25519.Sign or signer.Signer.
+// It returns an error if the signature is not valid for SignatureKey.
+func (c *Certificate) SetSignature(sig []byte) error {
+	sigBlob := appendString(nil, []byte(KeyAlgoED25519))
+	sigBlob = appendString(sigBlob, sig)
+	previous := c.Signature
+	c.Signature = sigBlob
+	if err := c.Verify(); err != nil {
+		c.Signature = previous
+		return err
+	}
+	return nil
+}
+
+// Sign sets the SignatureKey of the certificate to the key of signer, which must be an Ed25519 key,
+// and signs it. A random Nonce is generated if it is empty.
+// signer can be a signer.Signer, in which case the other parties must sign the same certificate,
+// which must therefore be encoded with BytesForSigning and shared with them first.
+func (c *Certificate) Sign(random io.Reader, signer crypto.Signer) error {
+	pk, ok := signer.Public().(ed25519.PublicKey)
+	if !ok {
+		return errors.New("sshcert: the signer does not have an Ed25519 key")
+	}
+	if len(c.Nonce) == 0 {
+		if random == nil {
+			random = rand.Reader
+		}
+		c.Nonce = make([]byte, 32)
+		if _, err := io.ReadFull(random, c.Nonce); err != nil {
+			return err
+		}
+	}
+	c.SignatureKey = marshalEd25519(pk)
+	message, err := c.BytesForSigning()
+	if err != nil {
+		return err
+	}
+	sig, err := signer.Sign(random, message, crypto.Hash(0))
+	if err != nil {
+		return err
+	}
+	return c.SetSignature(sig)
+}
+
+// Verify checks that the certificate is signed by the Ed25519 key SignatureKey.
+// It does not check the validity period or the principals.
+func (c *Certificate) Verify() error {
+	pk, err := parseEd25519(c.SignatureKey)
+	if err != nil {
+		return fmt.Errorf("sshcert: signature key: %w", err)
+	}
+	r := reader{data: c.Signature}
+	algo := string(r.string())
+	sig := r.string()
+	if err = r.finish(); err != nil {
+		return fmt.Errorf("sshcert: signature: %w", err)
+	}
+	if algo != KeyAlgoED25519 {
+		return fmt.Errorf("sshcert: unsupported signature algorithm %q", algo)
+	}
+	message, err := c.BytesForSigning()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(pk, message, sig) {
+		return errors.New("sshcert: invalid signature")
+	}
+	return nil
+}
+
+// ParseCertificate parses the wire encoding of a certificate, either signed or as returned by BytesForSigning.
+func ParseCertificate(data []byte) (*Certificate, error) {
+	r := reader{data: data}
+	name := string(r.string())
+	if r.err != nil {
+		return nil, r.err
+	}
+	algo, ok := keyAlgo(name)
+	if !ok {
+		return nil, fmt.Errorf("sshcert: unsupported certificate type %q", name)
+	}
+
+	var c Certificate
+	c.Nonce = r.string()
+	c.Key = appendString(nil, []byte(algo))
+	for i := 0; i < keyFields[algo]; i++ {
+		c.Key = appendString(c.Key, r.string())
+	}
+	c.Serial = r.uint64()
+	c.CertType = r.uint32()
+	c.KeyID = string(r.string())
+	principals := reader{data: r.string()}
+	for principals.err == nil && len(principals.data) > 0 {
+		c.ValidPrincipals = append(c.ValidPrincipals, string(principals.string()))
+	}
+	c.ValidAfter = r.uint64()
+	c.ValidBefore = r.uint64()
+	var err error
+	if c.CriticalOptions, err = parseOptions(r.string()); err != nil {
+		return nil, err
+	}
+	if c.Extensions, err = parseOptions(r.string()); err != nil {
+		return nil, err
+	}
+	_ = r.string() // reserved
+	c.SignatureKey = r.string()
+	if r.err == nil && len(r.data) > 0 {
+		c.Signature = r.string()
+	}
+	if err = principals.finish(); err != nil {
+		return nil, err
+	}
+	if err = r.finish(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ParseCertificateAuthorizedKey parses a certificate in the format of a -cert.pub file.
+func ParseCertificateAuthorizedKey(in []byte) (*Certificate, string, error) {
+	algo, blob, comment, err := ParseAuthorizedKey(in)
+	if err != nil {
+		return nil, "", err
+	}
+	if !strings.HasSuffix(algo, certSuffix) {
+		return nil, "", fmt.Errorf("sshcert: %q is not a certificate", algo)
+	}
+	c, err := ParseCertificate(blob)
+	if err != nil {
+		return nil, "", err
+	}
+	return c, comment, nil
+}
+
+// marshalOptions encodes critical options or extensions, sorted by name.
+// A non-empty value is encoded as a string inside the data field.
+func marshalOptions(options map[string]string) []byte {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out []byte
+	for _, name := range names {
+		out = appendString(out, []byte(name))
+		var data []byte
+		if value := options[name]; value != "" {
+			data = appendString(nil, []byte(value))
+		}
+		out = appendString(out, data)
+	}
+	return out
+}
+
+func parseOptions(data []byte) (map[string]string, error) {
+	options := map[string]string{}
+	r := reader{data: data}
+	previous := ""
+	for r.err == nil && len(r.data) > 0 {
+		name := string(r.string())
+		value := r.string()
+		if r.err != nil {
+			break
+		}
+		if len(options) > 0 && name <= previous {
+			return nil, errors.New("sshcert: options are not sorted")
+		}
+		previous = name
+		if len(value) == 0 {
+			options[name] = ""
+			continue
+		}
+		inner := reader{data: value}
+		options[name] = string(inner.string())
+		if err := inner.finish(); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.finish(); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
@@ -0,0 +1,90 @@
+// Package sshcert encodes FROST group keys in the OpenSSH formats, and builds OpenSSH certificates
+// signed by a group key, so that an SSH certificate authority can require t+1 parties to issue a certificate.
+//
+// The formats are described in RFC 4253, Section 6.6, RFC 8709, and in the PROTOCOL.certkeys file of OpenSSH.
+package sshcert
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+)
+
+// KeyAlgoED25519 is the name of Ed25519 keys and signatures.
+const KeyAlgoED25519 = "ssh-ed25519"
+
+// MarshalPublicKey returns the wire encoding of the Ed25519 key of pk, as included in certificates.
+func MarshalPublicKey(pk *eddsa.PublicKey) []byte {
+	return marshalEd25519(pk.ToEd25519())
+}
+
+func marshalEd25519(pk ed25519.PublicKey) []byte {
+	out := appendString(nil, []byte(KeyAlgoED25519))
+	return appendString(out, pk)
+}
+
+// MarshalAuthorizedKey returns pk in the format of the authorized_keys file, followed by comment if it is not empty.
+// The same line can be used in the TrustedUserCAKeys file of sshd, or after @cert-authority in known_hosts.
+func MarshalAuthorizedKey(pk *eddsa.PublicKey, comment string) []byte {
+	return marshalAuthorizedKey(KeyAlgoED25519, MarshalPublicKey(pk), comment)
+}
+
+func marshalAuthorizedKey(algo string, blob []byte, comment string) []byte {
+	var b bytes.Buffer
+	b.WriteString(algo)
+	b.WriteByte(' ')
+	b.WriteString(base64.StdEncoding.EncodeToString(blob))
+	if comment != "" {
+		b.WriteByte(' ')
+		b.WriteString(comment)
+	}
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+// ParseAuthorizedKey parses the first key of a file in the format of authorized_keys, such as id_ed25519.pub.
+// It returns the name of the algorithm, the wire encoding of the key, and its comment.
+// Options before the key are not supported.
+func ParseAuthorizedKey(in []byte) (algo string, blob []byte, comment string, err error) {
+	for _, line := range bytes.Split(in, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		fields := bytes.Fields(line)
+		if len(fields) < 2 {
+			return "", nil, "", errors.New("sshcert: invalid authorized key")
+		}
+		algo = string(fields[0])
+		if blob, err = base64.StdEncoding.DecodeString(string(fields[1])); err != nil {
+			return "", nil, "", fmt.Errorf("sshcert: invalid authorized key: %w", err)
+		}
+		r := reader{data: blob}
+		if inner := string(r.string()); r.err != nil || inner != algo {
+			return "", nil, "", fmt.Errorf("sshcert: key type %q does not match the encoded key", algo)
+		}
+		if len(fields) > 2 {
+			comment = string(bytes.Join(fields[2:], []byte(" ")))
+		}
+		return algo, blob, comment, nil
+	}
+	return "", nil, "", errors.New("sshcert: no key found")
+}
+
+// parseEd25519 returns the key encoded in blob, which must be an ssh-ed25519 key.
+func parseEd25519(blob []byte) (ed25519.PublicKey, error) {
+	r := reader{data: blob}
+	algo := string(r.string())
+	key := r.string()
+	if err := r.finish(); err != nil {
+		return nil, err
+	}
+	if algo != KeyAlgoED25519 || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("sshcert: %q is not an Ed25519 key", algo)
+	}
+	return key, nil
+}
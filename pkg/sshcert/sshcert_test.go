@@ -0,0 +1,166 @@
+package sshcert
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestMarshalAuthorizedKey(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	_, secrets := helpers.GenerateSecrets(partyIDs, 1)
+	public := helpers.GeneratePublic(1, secrets)
+
+	line := MarshalAuthorizedKey(public.GroupKey, "frost ca")
+	key, comment, _, _, err := ssh.ParseAuthorizedKey(line)
+	require.NoError(t, err)
+	assert.Equal(t, "frost ca", comment)
+	assert.Equal(t, ssh.KeyAlgoED25519, key.Type())
+	assert.Equal(t, MarshalPublicKey(public.GroupKey), key.Marshal())
+
+	algo, blob, comment, err := ParseAuthorizedKey(line)
+	require.NoError(t, err)
+	assert.Equal(t, KeyAlgoED25519, algo)
+	assert.Equal(t, key.Marshal(), blob)
+	assert.Equal(t, "frost ca", comment)
+
+	_, _, _, err = ParseAuthorizedKey([]byte("ssh-rsa " + string(line[len("ssh-ed25519 "):])))
+	assert.Error(t, err, "mismatched key type")
+}
+
+func newTestCertificate(t *testing.T) *Certificate {
+	userKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshKey, err := ssh.NewPublicKey(userKey)
+	require.NoError(t, err)
+	now := uint64(time.Now().Unix())
+	return &Certificate{
+		Key:             sshKey.Marshal(),
+		Serial:          42,
+		CertType:        UserCert,
+		KeyID:           "alice@example.com",
+		ValidPrincipals: []string{"alice", "root"},
+		ValidAfter:      now - 60,
+		ValidBefore:     now + 3600,
+		CriticalOptions: map[string]string{"force-command": "/usr/bin/true", "source-address": "10.0.0.0/8"},
+		Extensions:      map[string]string{"permit-pty": "", "permit-port-forwarding": ""},
+	}
+}
+
+func TestCertificate_Sign(t *testing.T) {
+	caPublic, caPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	caKey, err := ssh.NewPublicKey(caPublic)
+	require.NoError(t, err)
+
+	cert := newTestCertificate(t)
+	require.NoError(t, cert.Sign(rand.Reader, caPrivate))
+	line, err := cert.MarshalAuthorizedKey("alice")
+	require.NoError(t, err)
+
+	// The certificate is accepted by golang.org/x/crypto/ssh
+	parsed, comment, _, _, err := ssh.ParseAuthorizedKey(line)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", comment)
+	sshCert, ok := parsed.(*ssh.Certificate)
+	require.True(t, ok)
+	assert.Equal(t, cert.KeyID, sshCert.KeyId)
+	assert.Equal(t, cert.ValidPrincipals, sshCert.ValidPrincipals)
+	assert.Equal(t, cert.CriticalOptions, sshCert.CriticalOptions)
+	assert.Equal(t, cert.Extensions, sshCert.Extensions)
+	assert.Equal(t, cert.Key, sshCert.Key.Marshal())
+
+	checker := ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool {
+			return bytes.Equal(auth.Marshal(), caKey.Marshal())
+		},
+		SupportedCriticalOptions: []string{"force-command", "source-address"},
+	}
+	assert.NoError(t, checker.CheckCert("alice", sshCert))
+	assert.Error(t, checker.CheckCert("bob", sshCert))
+
+	// and can be parsed back
+	decoded, comment, err := ParseCertificateAuthorizedKey(line)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", comment)
+	assert.Equal(t, cert, decoded)
+	assert.NoError(t, decoded.Verify())
+
+	// the encoding without the signature can be parsed, so that it can be signed by other parties
+	tbs, err := cert.BytesForSigning()
+	require.NoError(t, err)
+	unsigned, err := ParseCertificate(tbs)
+	require.NoError(t, err)
+	assert.Empty(t, unsigned.Signature)
+	assert.Error(t, unsigned.Verify())
+	assert.NoError(t, unsigned.SetSignature(ed25519.Sign(caPrivate, tbs)))
+	assert.Equal(t, cert, unsigned)
+
+	unsigned.Serial++
+	assert.Error(t, unsigned.Verify())
+	assert.Error(t, unsigned.SetSignature(ed25519.Sign(caPrivate, tbs)))
+}
+
+func TestCertificate_SignFROST(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	_, secrets := helpers.GenerateSecrets(partyIDs, 1)
+	public := helpers.GeneratePublic(1, secrets)
+
+	cert := newTestCertificate(t)
+	cert.SignatureKey = MarshalPublicKey(public.GroupKey)
+	cert.Nonce = make([]byte, 32)
+	tbs, err := cert.BytesForSigning()
+	require.NoError(t, err)
+
+	sig := signFROST(t, party.IDSlice{partyIDs[0], partyIDs[2]}, secrets, public, tbs)
+	require.NoError(t, cert.SetSignature(sig.ToEd25519()))
+
+	caKey, err := ssh.NewPublicKey(public.GroupKey.ToEd25519())
+	require.NoError(t, err)
+	line, err := cert.MarshalAuthorizedKey("")
+	require.NoError(t, err)
+	parsed, _, _, _, err := ssh.ParseAuthorizedKey(line)
+	require.NoError(t, err)
+	checker := ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool {
+			return bytes.Equal(auth.Marshal(), caKey.Marshal())
+		},
+		SupportedCriticalOptions: []string{"force-command", "source-address"},
+	}
+	assert.NoError(t, checker.CheckCert("root", parsed.(*ssh.Certificate)))
+}
+
+// signFROST runs the sign protocol between the parties in signIDs.
+func signFROST(t *testing.T, signIDs party.IDSlice, secrets map[party.ID]*eddsa.SecretShare, public *eddsa.Public, message []byte) *eddsa.Signature {
+	states := map[party.ID]*state.State{}
+	outputs := map[party.ID]*sign.Output{}
+	for _, id := range signIDs {
+		var err error
+		states[id], outputs[id], err = frost.NewSignState(context.Background(), signIDs, secrets[id], public, message, 0)
+		require.NoError(t, err)
+	}
+	var msgs [][]byte
+	for round := 0; round < 3; round++ {
+		var out [][]byte
+		for _, id := range signIDs {
+			msgsOut, err := helpers.PartyRoutine(msgs, states[id])
+			require.NoError(t, err)
+			out = append(out, msgsOut...)
+		}
+		msgs = out
+	}
+	return outputs[signIDs[0]].Signature
+}
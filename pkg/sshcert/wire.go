@@ -0,0 +1,79 @@
+package sshcert
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var errShortData = errors.New("sshcert: data is too short")
+
+// The encodings below are those of RFC 4251, Section 5.
+
+func appendUint32(b []byte, x uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], x)
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, x uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], x)
+	return append(b, buf[:]...)
+}
+
+func appendString(b []byte, s []byte) []byte {
+	b = appendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// reader decodes a sequence of fields, and records the first error.
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data) < n {
+		r.err = errShortData
+		return nil
+	}
+	out := r.data[:n]
+	r.data = r.data[n:]
+	return out
+}
+
+func (r *reader) uint32() uint32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (r *reader) uint64() uint64 {
+	b := r.next(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+func (r *reader) string() []byte {
+	n := r.uint32()
+	if r.err == nil && uint64(n) > uint64(len(r.data)) {
+		r.err = errShortData
+		return nil
+	}
+	return r.next(int(n))
+}
+
+// finish returns the first error, or an error if data remains.
+func (r *reader) finish() error {
+	if r.err == nil && len(r.data) != 0 {
+		return errors.New("sshcert: unexpected trailing data")
+	}
+	return r.err
+}
@@ -1,7 +1,9 @@
 package state
 
 import (
+	"crypto/rand"
 	"errors"
+	"io"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 	"github.com/taurusgroup/frost-ed25519/pkg/messages"
@@ -10,6 +12,7 @@ import (
 type BaseRound struct {
 	selfID   party.ID
 	partyIDs party.IDSlice
+	random   io.Reader
 }
 
 func NewBaseRound(selfID party.ID, partyIDs party.IDSlice) (*BaseRound, error) {
@@ -33,3 +36,18 @@ func (r BaseRound) SelfID() party.ID {
 func (r BaseRound) PartyIDs() party.IDSlice {
 	return r.partyIDs
 }
+
+// Random returns the source of randomness which the round should use.
+// It is crypto/rand.Reader, unless it was replaced with SetRandom.
+func (r *BaseRound) Random() io.Reader {
+	if r.random == nil {
+		return rand.Reader
+	}
+	return r.random
+}
+
+// SetRandom replaces the source of randomness of the round.
+// It is called by NewBaseState when the WithRandom option is given.
+func (r *BaseRound) SetRandom(random io.Reader) {
+	r.random = random
+}
@@ -2,8 +2,10 @@ package state
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
 )
 
 // Error represents an error related to the protocol execution, and requires an abort.
@@ -26,3 +28,43 @@ func NewError(partyID party.ID, err error) *Error {
 func (e Error) Error() string {
 	return fmt.Sprintf("party %d: round %d: %s", e.PartyID, e.RoundNumber, e.err.Error())
 }
+
+// Unwrap returns the underlying error, so that it can be inspected with errors.Is and errors.As.
+func (e Error) Unwrap() error {
+	return e.err
+}
+
+// TimeoutError is the reason a protocol was aborted because a timeout expired.
+// It can be extracted from the error returned by State.Err with errors.As.
+type TimeoutError struct {
+	// RoundNumber is the round in which the protocol was waiting.
+	RoundNumber int
+	// Missing are the parties from which we did not receive a message for this round.
+	Missing party.IDSlice
+}
+
+// Error implement error
+func (e *TimeoutError) Error() string {
+	if len(e.Missing) == 0 {
+		return fmt.Sprintf("timeout in round %d", e.RoundNumber)
+	}
+	ids := make([]string, len(e.Missing))
+	for i, id := range e.Missing {
+		ids[i] = id.String()
+	}
+	return fmt.Sprintf("timeout in round %d: no message from parties %s", e.RoundNumber, strings.Join(ids, ", "))
+}
+
+// EquivocationError is the reason a protocol was aborted because a party sent two different messages of the same type.
+// It can be extracted from the error returned by State.Err with errors.As, and contains both messages as evidence.
+type EquivocationError struct {
+	// PartyID is the party which sent both messages.
+	PartyID party.ID
+	// First is the message which was accepted first, and Second the conflicting one.
+	First, Second *messages.Message
+}
+
+// Error implement error
+func (e *EquivocationError) Error() string {
+	return fmt.Sprintf("party %d sent two different %s messages", e.PartyID, e.First.Type)
+}
@@ -0,0 +1,70 @@
+package state
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrAlreadyResumed is returned by a ResumeGuard when a snapshot is resumed a second time.
+var ErrAlreadyResumed = errors.New("snapshot was already resumed")
+
+// A ResumeGuard keeps track of the snapshots which have been resumed.
+// Resuming a snapshot twice could result in the reuse of secret nonces,
+// and therefore leak the secret key share.
+type ResumeGuard interface {
+	// Consume atomically marks the snapshot with the given id as resumed.
+	// It must return ErrAlreadyResumed if it was already consumed.
+	Consume(id []byte) error
+}
+
+type memoryGuard struct {
+	used map[string]struct{}
+	mtx  sync.Mutex
+}
+
+// NewMemoryGuard returns a ResumeGuard which stores consumed snapshot IDs in memory.
+// It is only useful if the snapshots do not outlive the process.
+func NewMemoryGuard() ResumeGuard {
+	return &memoryGuard{used: map[string]struct{}{}}
+}
+
+func (g *memoryGuard) Consume(id []byte) error {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	if _, ok := g.used[string(id)]; ok {
+		return ErrAlreadyResumed
+	}
+	g.used[string(id)] = struct{}{}
+	return nil
+}
+
+type fileGuard struct {
+	dir string
+}
+
+// NewFileGuard returns a ResumeGuard which records consumed snapshot IDs as empty files in dir.
+// The directory must exist, and should be on persistent storage shared by all processes
+// that may resume the same snapshots.
+func NewFileGuard(dir string) ResumeGuard {
+	return &fileGuard{dir: dir}
+}
+
+func (g *fileGuard) Consume(id []byte) error {
+	path := filepath.Join(g.dir, hex.EncodeToString(id))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return ErrAlreadyResumed
+		}
+		return fmt.Errorf("state: failed to record snapshot: %w", err)
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("state: failed to record snapshot: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,39 @@
+package state
+
+import (
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+)
+
+// An Observer is notified by a State of the progress of the protocol.
+//
+// The methods are called synchronously while the State is locked.
+// They should therefore return quickly, and must not call any method of the State.
+type Observer interface {
+	// MessageAccepted is called when HandleMessage accepts msg for the current round or a future one.
+	MessageAccepted(round int, msg *messages.Message)
+
+	// MessageRejected is called when HandleMessage returns an error for msg,
+	// and reason is the error that was returned.
+	MessageRejected(round int, msg *messages.Message, reason error)
+
+	// RoundCompleted is called when all messages for round were processed, and the messages for the next round generated.
+	// duration is the time elapsed since the round started.
+	RoundCompleted(round int, duration time.Duration)
+
+	// TimedOut is called when the protocol aborts because no message was received in time during round.
+	TimedOut(round int)
+
+	// Finished is called exactly once, when the protocol has finished.
+	// err is nil if the protocol has completed successfully, and is otherwise the reason it aborted.
+	Finished(err error)
+}
+
+type noopObserver struct{}
+
+func (noopObserver) MessageAccepted(int, *messages.Message)        {}
+func (noopObserver) MessageRejected(int, *messages.Message, error) {}
+func (noopObserver) RoundCompleted(int, time.Duration)             {}
+func (noopObserver) TimedOut(int)                                  {}
+func (noopObserver) Finished(error)                                {}
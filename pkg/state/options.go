@@ -0,0 +1,58 @@
+package state
+
+import (
+	"io"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+)
+
+// An Option configures optional behaviour of a State.
+type Option func(s *State)
+
+// WithObserver registers an Observer which is notified of the progress of the protocol.
+func WithObserver(observer Observer) Option {
+	return func(s *State) {
+		if observer != nil {
+			s.observer = observer
+		}
+	}
+}
+
+// WithRoundTimeout aborts the protocol when a round takes longer than d to complete,
+// regardless of whether other messages were received in the meantime.
+// Unlike the idle timeout given to NewBaseState, it is not reset when a message is received.
+func WithRoundTimeout(d time.Duration) Option {
+	return func(s *State) {
+		s.roundTimeout = d
+	}
+}
+
+// WithSessionID sets the session ID which is added to all outgoing messages.
+// Incoming messages with a different session ID are rejected.
+// All parties must use the same ID, which should be unique for each execution of the protocol.
+func WithSessionID(id messages.SessionID) Option {
+	return func(s *State) {
+		s.sessionID = id
+	}
+}
+
+// SessionIDOf returns the session ID set by the WithSessionID among opts, or the zero ID if there is none.
+func SessionIDOf(opts ...Option) messages.SessionID {
+	var s State
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s.sessionID
+}
+
+// WithRandom replaces crypto/rand as the source of randomness of the protocol,
+// for example to reproduce an execution in tests.
+// The round given to NewBaseState must implement RandomizedRound, as those embedding BaseRound do.
+//
+// The secrets of the party are derived from random, which must therefore be unpredictable outside of tests.
+func WithRandom(random io.Reader) Option {
+	return func(s *State) {
+		s.random = random
+	}
+}
@@ -1,6 +1,8 @@
 package state
 
 import (
+	"io"
+
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 	"github.com/taurusgroup/frost-ed25519/pkg/messages"
 )
@@ -73,3 +75,10 @@ type Round interface {
 	// PartyIDs returns a set containing all parties participating in the round
 	PartyIDs() party.IDSlice
 }
+
+// A RandomizedRound is a Round whose source of randomness can be replaced with the WithRandom option.
+// It is implemented by BaseRound.
+type RandomizedRound interface {
+	Round
+	SetRandom(random io.Reader)
+}
@@ -0,0 +1,296 @@
+package state
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/wire"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+)
+
+// snapshotVersion is the version of the snapshot encoding.
+// It is increased whenever the format changes, and older versions are rejected.
+const snapshotVersion = 3
+
+const (
+	snapshotKeySize   = 32
+	snapshotIDSize    = 16
+	snapshotNonceSize = 12
+)
+
+var (
+	// ErrSuspended is the error returned by State.Err after State.Suspend was called.
+	ErrSuspended = errors.New("protocol suspended")
+
+	// ErrSnapshotInvalid is returned when a snapshot cannot be decrypted or decoded.
+	ErrSnapshotInvalid = errors.New("invalid snapshot")
+)
+
+// A Resumable round can be saved with State.Suspend.
+// Since all rounds of a protocol usually embed the same Round0 struct,
+// it suffices to implement MarshalState there.
+type Resumable interface {
+	Round
+
+	// MarshalState returns an encoding of all the data held by the round, including secrets.
+	// The protocol must provide a RestoreFunc which is able to decode it.
+	MarshalState() ([]byte, error)
+}
+
+// RestoreFunc recreates the round with the given number from the output of Resumable.MarshalState.
+type RestoreFunc func(roundNumber int, data []byte) (Round, error)
+
+// Suspend serializes the full state of the protocol, including the messages received so far,
+// and encrypts it with key, which must be 32 bytes long.
+//
+// The State is then aborted with ErrSuspended, and all secrets are erased from memory.
+// This guarantees that a snapshot is never used concurrently with the State it was taken from.
+// The protocol can be continued by giving the snapshot to Resume.
+func (s *State) Suspend(key []byte) ([]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.done {
+		return nil, errors.New("state.Suspend: protocol already finished")
+	}
+
+	round, ok := s.round.(Resumable)
+	if !ok {
+		return nil, errors.New("state.Suspend: round does not support suspension")
+	}
+
+	roundData, err := round.MarshalState()
+	if err != nil {
+		return nil, fmt.Errorf("state.Suspend: %w", err)
+	}
+
+	id := make([]byte, snapshotIDSize)
+	if _, err = rand.Read(id); err != nil {
+		return nil, fmt.Errorf("state.Suspend: %w", err)
+	}
+
+	w := wire.NewWriter(nil)
+	w.Raw(id)
+	w.Raw(s.sessionID[:])
+	w.Uint16(uint16(s.roundNumber))
+	types := round.AcceptedMessageTypes()
+	w.Uint8(uint8(len(types)))
+	for _, t := range types {
+		w.Uint8(uint8(t))
+	}
+	w.VarBytes(roundData)
+
+	// Messages of round 0 are placeholders, and are recreated on resumption.
+	if err = writeMessages(w, s.receivedMessages, s.roundNumber > 0); err != nil {
+		return nil, fmt.Errorf("state.Suspend: %w", err)
+	}
+	w.Uint32(uint32(len(s.queue)))
+	for _, msg := range s.queue {
+		data, err := msg.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("state.Suspend: %w", err)
+		}
+		w.VarBytes(data)
+	}
+	// The history also covers the messages of earlier rounds,
+	// so that their retransmissions are still ignored and their equivocations detected.
+	w.Uint32(uint32(len(s.history)))
+	for key, data := range s.history {
+		w.Uint8(uint8(key.msgType))
+		w.ID(key.from)
+		w.VarBytes(data)
+	}
+
+	snapshot, err := seal(key, w.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("state.Suspend: %w", err)
+	}
+
+	s.reportError(NewError(0, ErrSuspended))
+	return snapshot, nil
+}
+
+func writeMessages(w *wire.Writer, msgs map[party.ID]*messages.Message, include bool) error {
+	if !include {
+		w.Uint32(0)
+		return nil
+	}
+	w.Uint32(uint32(len(msgs)))
+	for _, msg := range msgs {
+		data, err := msg.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		w.VarBytes(data)
+	}
+	return nil
+}
+
+// Resume decrypts a snapshot created by State.Suspend, and returns a State which continues the protocol
+// from where it was suspended. restore is provided by the protocol and recreates the round.
+//
+// If guard is not nil, then the snapshot is consumed by the guard before the State is returned,
+// so that it can never be resumed a second time. It is not consumed if Resume fails for any other reason.
+// Protocols which hold secret nonces, such as signing, must always be resumed with a guard.
+//
+// ctx and timeout have the same meaning as in NewBaseState, and the timeout starts from the moment the State is resumed.
+// The session ID is restored from the snapshot.
+func Resume(ctx context.Context, snapshot, key []byte, restore RestoreFunc, guard ResumeGuard, timeout time.Duration, opts ...Option) (*State, error) {
+	plaintext, err := open(key, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	r := wire.NewReader(plaintext)
+	id := r.Raw(snapshotIDSize)
+	var sessionID messages.SessionID
+	copy(sessionID[:], r.Raw(messages.SessionIDSize))
+	roundNumber := int(r.Uint16())
+	types := make([]messages.MessageType, r.Uint8())
+	for i := range types {
+		types[i] = messages.MessageType(r.Uint8())
+	}
+	roundData := r.VarBytes()
+
+	received := make([]*messages.Message, r.Uint32())
+	for i := range received {
+		received[i] = readMessage(r)
+	}
+	queue := make([]*messages.Message, r.Uint32())
+	for i := range queue {
+		queue[i] = readMessage(r)
+	}
+	history := make(map[historyKey][]byte)
+	for i := r.Uint32(); i > 0 && r.Err() == nil; i-- {
+		key := historyKey{messages.MessageType(r.Uint8()), r.ID()}
+		history[key] = r.VarBytes()
+	}
+	if err = r.Finish(); err != nil {
+		return nil, fmt.Errorf("state.Resume: %v: %w", err, ErrSnapshotInvalid)
+	}
+	if roundNumber >= len(types) {
+		return nil, fmt.Errorf("state.Resume: round number out of range: %w", ErrSnapshotInvalid)
+	}
+
+	round, err := restore(roundNumber, roundData)
+	if err != nil {
+		return nil, fmt.Errorf("state.Resume: %w", err)
+	}
+	if !equalTypes(types, round.AcceptedMessageTypes()) {
+		round.Reset()
+		return nil, fmt.Errorf("state.Resume: snapshot is for a different protocol: %w", ErrSnapshotInvalid)
+	}
+
+	s, err := NewBaseState(ctx, round, timeout, opts...)
+	if err != nil {
+		round.Reset()
+		return nil, err
+	}
+
+	// The timers and the context of s are already running.
+	s.mtx.Lock()
+	s.roundNumber = roundNumber
+	s.sessionID = sessionID
+	s.acceptedTypes = s.acceptedTypes[roundNumber:]
+	if roundNumber > 0 {
+		for id := range s.receivedMessages {
+			delete(s.receivedMessages, id)
+		}
+	}
+	for _, msg := range received {
+		s.receivedMessages[msg.From] = msg
+	}
+	s.queue = append(s.queue, queue...)
+	s.history = history
+	// The timer started by NewBaseState was for round 0.
+	s.startRoundTimer()
+	s.mtx.Unlock()
+
+	// The snapshot is only consumed once nothing else can fail.
+	if guard != nil {
+		if err = guard.Consume(id); err != nil {
+			s.mtx.Lock()
+			s.reportError(NewError(0, err))
+			s.mtx.Unlock()
+			return nil, fmt.Errorf("state.Resume: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+func readMessage(r *wire.Reader) *messages.Message {
+	var msg messages.Message
+	data := r.VarBytes()
+	if r.Err() != nil {
+		return nil
+	}
+	if err := msg.UnmarshalBinary(data); err != nil {
+		r.Fail(err)
+		return nil
+	}
+	return &msg
+}
+
+func equalTypes(a, b []messages.MessageType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// seal encrypts plaintext with AES-256-GCM.
+// The output is version || nonce || ciphertext, and the version is authenticated.
+func seal(key, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 1+snapshotNonceSize, 1+snapshotNonceSize+len(plaintext)+aead.Overhead())
+	out[0] = snapshotVersion
+	if _, err = rand.Read(out[1:]); err != nil {
+		return nil, err
+	}
+	return aead.Seal(out, out[1:], plaintext, out[:1]), nil
+}
+
+func open(key, snapshot []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshot) < 1+snapshotNonceSize+aead.Overhead() {
+		return nil, fmt.Errorf("state.Resume: snapshot too short: %w", ErrSnapshotInvalid)
+	}
+	if snapshot[0] != snapshotVersion {
+		return nil, fmt.Errorf("state.Resume: unsupported snapshot version %d: %w", snapshot[0], ErrSnapshotInvalid)
+	}
+	nonce := snapshot[1 : 1+snapshotNonceSize]
+	plaintext, err := aead.Open(nil, nonce, snapshot[1+snapshotNonceSize:], snapshot[:1])
+	if err != nil {
+		return nil, fmt.Errorf("state.Resume: failed to decrypt: %w", ErrSnapshotInvalid)
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != snapshotKeySize {
+		return nil, fmt.Errorf("snapshot key must be %d bytes", snapshotKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -1,8 +1,11 @@
 package state
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 
@@ -19,12 +22,26 @@ type State struct {
 	receivedMessages map[party.ID]*messages.Message
 	queue            []*messages.Message
 
+	// history contains the encoding of every message accepted from another party during this session,
+	// so that retransmissions can be told apart from equivocations.
+	history map[historyKey][]byte
+
 	timer
 
-	roundNumber int
+	roundNumber  int
+	roundStart   time.Time
+	roundTimeout time.Duration
+	roundTimer   *time.Timer
 
 	round Round
 
+	sessionID messages.SessionID
+
+	// random is the source of randomness given with WithRandom, if any
+	random io.Reader
+
+	observer Observer
+
 	doneChan chan struct{}
 	done     bool
 	err      *Error
@@ -32,21 +49,46 @@ type State struct {
 	mtx sync.Mutex
 }
 
-func NewBaseState(round Round, timeout time.Duration) (*State, error) {
+// NewBaseState returns a State which executes the protocol starting with round.
+//
+// The protocol is aborted when ctx is done, in which case the error returned by State.Err
+// wraps ctx.Err(), so that it can be checked with errors.Is(err, context.Canceled) or
+// errors.Is(err, context.DeadlineExceeded).
+// If timeout is not 0, the protocol also aborts when no message was received for that duration,
+// in which case the error returned by State.Err wraps a *TimeoutError listing the parties which did not send their message.
+//
+// Additional behaviour can be configured by giving Option s.
+func NewBaseState(ctx context.Context, round Round, timeout time.Duration, opts ...Option) (*State, error) {
 	N := round.PartyIDs().N()
 	s := &State{
 		acceptedTypes:    append([]messages.MessageType{}, round.AcceptedMessageTypes()...),
 		receivedMessages: make(map[party.ID]*messages.Message, N),
 		queue:            make([]*messages.Message, 0, N),
+		history:          make(map[historyKey][]byte, N),
 		round:            round,
+		roundStart:       time.Now(),
+		observer:         noopObserver{},
 		doneChan:         make(chan struct{}),
 	}
 
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.random != nil {
+		r, ok := round.(RandomizedRound)
+		if !ok {
+			return nil, errors.New("state.NewBaseState: round does not support WithRandom")
+		}
+		r.SetRandom(s.random)
+	}
+
 	s.timer = newTimer(timeout, func() {
 		s.mtx.Lock()
-		s.reportError(NewError(0, errors.New("message timeout")))
+		s.timeout()
 		s.mtx.Unlock()
 	})
+	s.startRoundTimer()
 
 	for _, id := range round.PartyIDs() {
 		if id != round.SelfID() {
@@ -54,14 +96,29 @@ func NewBaseState(round Round, timeout time.Duration) (*State, error) {
 		}
 	}
 
+	if ctx.Done() != nil {
+		go s.watchContext(ctx)
+	}
+
 	return s, nil
 }
 
+// watchContext aborts the protocol when ctx is done, and returns as soon as the protocol finishes.
+func (s *State) watchContext(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		s.mtx.Lock()
+		s.reportError(NewError(0, ctx.Err()))
+		s.mtx.Unlock()
+	case <-s.doneChan:
+	}
+}
+
 func (s *State) wrapError(err error, culprit party.ID) error {
 	if culprit == 0 {
 		return fmt.Errorf("party %d, round %d: %w", s.round.SelfID(), s.roundNumber, err)
 	}
-	return fmt.Errorf("party %d, round %d, culprit %d: %w", s.round.SelfID(), culprit, s.roundNumber, err)
+	return fmt.Errorf("party %d, round %d, culprit %d: %w", s.round.SelfID(), s.roundNumber, culprit, err)
 }
 
 // HandleMessage should be called on an unmarshalled messages.Message appropriate for the protocol execution.
@@ -70,11 +127,16 @@ func (s *State) wrapError(err error, culprit party.ID) error {
 // - Is msg is valid for this round or a future one
 // - Is msg for us and not from us
 // - Is the sender a party in the protocol
-// - Have we already received a message from the party for this round?
+// - Does msg belong to our session?
+// - Have we already received a message of the same type from the party?
 //
 // If all these checks pass, then the message is either stored for the current round,
 // or put in a queue for later rounds.
 //
+// A message which is identical to one that was already accepted is ignored, so that it is safe
+// to deliver the same message more than once.
+// If it differs, then the sender has equivocated, and the protocol is aborted with an EquivocationError.
+//
 // Note: the properties of the messages are checked in ProcessAll.
 // Therefore, the check here should be a quite fast.
 func (s *State) HandleMessage(msg *messages.Message) error {
@@ -84,11 +146,11 @@ func (s *State) HandleMessage(msg *messages.Message) error {
 	defer s.mtx.Unlock()
 
 	if s.done {
-		return s.wrapError(errors.New("protocol already finished"), senderID)
+		return s.reject(msg, errors.New("protocol already finished"))
 	}
 
 	if len(s.acceptedTypes) == 0 {
-		return s.wrapError(errors.New("no more messages being accepted"), senderID)
+		return s.reject(msg, errors.New("no more messages being accepted"))
 	}
 
 	// Ignore messages from self
@@ -100,32 +162,75 @@ func (s *State) HandleMessage(msg *messages.Message) error {
 	if !msg.IsBroadcast() && msg.To != s.round.SelfID() {
 		return nil
 	}
+	if msg.SessionID != s.sessionID {
+		return s.reject(msg, errors.New("message belongs to another session"))
+	}
+
 	// Is the sender in our list of participants?
 	if !s.round.PartyIDs().Contains(senderID) {
-		return s.wrapError(errors.New("sender is not a party"), senderID)
+		return s.reject(msg, errors.New("sender is not a party"))
 	}
 
-	// Check if we have already received a message from this party.
-	// exists should never be false, but you never know
-	if _, exists := s.receivedMessages[senderID]; exists {
-		return s.wrapError(errors.New("message from this party was already received"), senderID)
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return s.reject(msg, err)
+	}
+
+	// Check if we have already received a message of this type from this party.
+	// This is done before checking the type, so that retransmissions from earlier rounds are also ignored.
+	key := historyKey{msg.Type, senderID}
+	if previous, exists := s.history[key]; exists {
+		if bytes.Equal(previous, data) {
+			return nil
+		}
+		return s.equivocation(msg, previous)
 	}
 
 	if !s.isAcceptedType(msg.Type) {
-		return s.wrapError(errors.New("message type is not accepted for this type of round"), senderID)
+		return s.reject(msg, errors.New("message type is not accepted for this type of round"))
 	}
 
 	s.ackMessage()
+	s.history[key] = data
 
 	if msg.Type == s.acceptedTypes[0] {
 		s.receivedMessages[senderID] = msg
 	} else {
 		s.queue = append(s.queue, msg)
 	}
+	s.observer.MessageAccepted(s.roundNumber, msg)
 
 	return nil
 }
 
+// reject notifies the observer that msg was rejected, and returns the error that HandleMessage should return.
+func (s *State) reject(msg *messages.Message, err error) error {
+	s.observer.MessageRejected(s.roundNumber, msg, err)
+	return s.wrapError(err, msg.From)
+}
+
+// equivocation aborts the protocol after msg was found to differ from the previously accepted message,
+// whose encoding is previous.
+func (s *State) equivocation(msg *messages.Message, previous []byte) error {
+	var first messages.Message
+	if err := first.UnmarshalBinary(previous); err != nil {
+		return s.reject(msg, err)
+	}
+	err := NewError(msg.From, &EquivocationError{
+		PartyID: msg.From,
+		First:   &first,
+		Second:  msg,
+	})
+	s.observer.MessageRejected(s.roundNumber, msg, err)
+	s.reportError(err)
+	return err
+}
+
+type historyKey struct {
+	msgType messages.MessageType
+	from    party.ID
+}
+
 // ProcessAll checks whether all messages for this round have been received.
 // If so then all messages are fed to Round.ProcessMessage.
 // If no error was detected, then the round is processed and new messages are generated.
@@ -162,6 +267,9 @@ func (s *State) ProcessAll() []*messages.Message {
 		s.reportError(err)
 		return nil
 	}
+	for _, msg := range newMessages {
+		msg.SessionID = s.sessionID
+	}
 
 	// remove the messages for the next round from the queue
 	s.acceptedTypes = s.acceptedTypes[1:]
@@ -178,6 +286,9 @@ func (s *State) ProcessAll() []*messages.Message {
 		s.queue = newQueue
 	}
 
+	s.observer.RoundCompleted(s.roundNumber, time.Since(s.roundStart))
+	s.roundStart = time.Now()
+
 	// We are finished and move on to the next round
 	nextRound := s.round.NextRound()
 	if nextRound == nil {
@@ -185,6 +296,7 @@ func (s *State) ProcessAll() []*messages.Message {
 	} else {
 		s.roundNumber++
 		s.round = nextRound
+		s.startRoundTimer()
 	}
 
 	return newMessages
@@ -208,7 +320,22 @@ func (s *State) finish() {
 	}
 	s.done = true
 	s.round.Reset()
+	// messages may contain secret shares
+	for key, data := range s.history {
+		for i := range data {
+			data[i] = 0
+		}
+		delete(s.history, key)
+	}
 	s.stopTimer()
+	if s.roundTimer != nil {
+		s.roundTimer.Stop()
+	}
+	if s.err != nil {
+		s.observer.Finished(s.err)
+	} else {
+		s.observer.Finished(nil)
+	}
 	close(s.doneChan)
 }
 
@@ -237,6 +364,8 @@ func (s *State) Done() <-chan struct{} {
 }
 
 func (s *State) Err() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
 	if s.err != nil {
 		return s.err
 	}
@@ -247,14 +376,45 @@ func (s *State) Err() error {
 // This happens either when the protocol has finished correctly,
 // or if an error has been detected.
 func (s *State) WaitForError() error {
-	if !s.done {
-		<-s.doneChan
-	}
+	<-s.doneChan
 	return s.Err()
 }
 
+// RoundNumber returns the number of the round the protocol is currently in, starting from 0.
+func (s *State) RoundNumber() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.roundNumber
+}
+
+// MissingParties returns the parties from which we are still waiting for a message in the current round.
+// It returns nil when the protocol has finished.
+func (s *State) MissingParties() party.IDSlice {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.done {
+		return nil
+	}
+	return s.missingParties()
+}
+
+func (s *State) missingParties() party.IDSlice {
+	missing := make(party.IDSlice, 0, len(s.round.PartyIDs()))
+	for _, id := range s.round.PartyIDs() {
+		if id == s.round.SelfID() {
+			continue
+		}
+		if _, ok := s.receivedMessages[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
 // IsFinished returns true if the protocol has aborted or successfully finished.
 func (s *State) IsFinished() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
 	return s.done
 }
 
@@ -262,6 +422,47 @@ func (s *State) IsFinished() bool {
 // Timeout
 //
 
+// Expire aborts the protocol with a TimeoutError, as if the timeout had fired.
+// It allows the caller to enforce its own deadlines, and does nothing if the protocol has already finished.
+func (s *State) Expire() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.timeout()
+}
+
+// timeout aborts the protocol with a TimeoutError for the current round.
+// s.mtx must be held.
+func (s *State) timeout() {
+	if s.done {
+		return
+	}
+	s.observer.TimedOut(s.roundNumber)
+	s.reportError(NewError(0, &TimeoutError{
+		RoundNumber: s.roundNumber,
+		Missing:     s.missingParties(),
+	}))
+}
+
+// startRoundTimer stops the timer of the previous round, and starts a new one for the current round.
+// The new timer only aborts the protocol if it is still in the same round when it fires.
+// s.mtx must be held, unless s is not yet shared.
+func (s *State) startRoundTimer() {
+	if s.roundTimeout <= 0 {
+		return
+	}
+	if s.roundTimer != nil {
+		s.roundTimer.Stop()
+	}
+	roundNumber := s.roundNumber
+	s.roundTimer = time.AfterFunc(s.roundTimeout, func() {
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+		if s.roundNumber == roundNumber {
+			s.timeout()
+		}
+	})
+}
+
 type timer struct {
 	t *time.Timer
 	d time.Duration
@@ -0,0 +1,373 @@
+package transport
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+// DefaultPollInterval is the delay between two fetches of a Mailbox from the relay.
+const DefaultPollInterval = time.Second
+
+// The encoding of an envelope is
+//
+//	version (1) || from (2) || to (2) || nonce (12) || AES-256-GCM ciphertext of the message
+//
+// The first 5 bytes, preceded by the session ID, are authenticated as additional data.
+// The key is derived from the Diffie-Hellman secret between the mailbox keys of both parties,
+// so that only the sender and receiver can create or read the envelope.
+const (
+	envelopeVersion    = 1
+	envelopeHeaderSize = 1 + 2*party.IDByteSize
+	envelopeNonceSize  = 12
+)
+
+// envelopeDomain separates the keys derived for envelopes from other uses of the mailbox keys.
+var envelopeDomain = []byte("FROST-Ed25519 mailbox envelope v1")
+
+// NewMailboxKey generates a new secret key for a Mailbox, and returns it with its public key.
+// The public key must be given to all other parties.
+func NewMailboxKey() (secret *ristretto.Scalar, public *ristretto.Element) {
+	secret = scalar.NewScalarRandom()
+	public = new(ristretto.Element).ScalarBaseMult(secret)
+	return
+}
+
+// MailboxConfig configures a Mailbox.
+type MailboxConfig struct {
+	// ID is the party.ID of this party.
+	ID party.ID
+
+	// SessionID identifies the protocol execution, and selects the mailboxes on the relay.
+	// It should also be given to the State with state.WithSessionID.
+	SessionID messages.SessionID
+
+	// RelayURL is the base URL of the Relay, such as "https://relay.example.com".
+	RelayURL string
+
+	// Directory is a directory shared by all parties, such as a network file system,
+	// which is used to exchange the envelopes instead of a relay.
+	// Exactly one of RelayURL and Directory must be set.
+	Directory string
+
+	// Secret is the mailbox key of this party, created by NewMailboxKey.
+	Secret *ristretto.Scalar
+
+	// Peers are the public mailbox keys of all other parties.
+	Peers map[party.ID]*ristretto.Element
+
+	// Client is used to contact the relay. If it is nil, then http.DefaultClient is used.
+	Client *http.Client
+
+	// PollInterval is the delay between two fetches from the relay or the directory.
+	// If it is 0, then DefaultPollInterval is used.
+	PollInterval time.Duration
+
+	// MaxMessageSize is the size of the largest message that can be sent or received.
+	// If it is 0, then messages.MaxFrameSize is used.
+	MaxMessageSize int
+
+	// MinBackoff and MaxBackoff bound the exponential backoff between attempts to post a message.
+	// If they are 0, then DefaultMinBackoff and DefaultMaxBackoff are used.
+	MinBackoff, MaxBackoff time.Duration
+
+	// Timeout is returned by Mailbox.Timeout. Since parties may be offline for long periods,
+	// it should be large, or 0 to wait indefinitely.
+	Timeout time.Duration
+}
+
+// Mailbox is a Communicator which exchanges messages through a Relay, or a shared directory.
+//
+// Every message is encrypted and authenticated for its receiver, and posted to the receiver's mailbox.
+// Broadcast messages are posted once for every other party.
+// The messages in the mailbox of this party are fetched periodically, starting from the oldest one still kept,
+// so that a party which restarts, for instance by resuming a suspended State, receives them again.
+type Mailbox struct {
+	id        party.ID
+	sessionID messages.SessionID
+	store     mailboxStore
+	interval  time.Duration
+	maxSize   int
+	timeout   time.Duration
+
+	minBackoff, maxBackoff time.Duration
+
+	// sealKeys are used for envelopes to each peer, and openKeys for envelopes from each peer.
+	sealKeys, openKeys map[party.ID]cipher.AEAD
+
+	incoming chan *messages.Message
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewMailbox returns a Mailbox which starts fetching messages from the relay or the directory immediately.
+func NewMailbox(cfg MailboxConfig) (*Mailbox, error) {
+	if cfg.Secret == nil {
+		return nil, errors.New("transport.NewMailbox: a secret key is required")
+	}
+	if (cfg.RelayURL == "") == (cfg.Directory == "") {
+		return nil, errors.New("transport.NewMailbox: exactly one of a relay URL and a directory is required")
+	}
+
+	c := &Mailbox{
+		id:         cfg.ID,
+		sessionID:  cfg.SessionID,
+		interval:   cfg.PollInterval,
+		maxSize:    cfg.MaxMessageSize,
+		timeout:    cfg.Timeout,
+		minBackoff: cfg.MinBackoff,
+		maxBackoff: cfg.MaxBackoff,
+		sealKeys:   make(map[party.ID]cipher.AEAD, len(cfg.Peers)),
+		openKeys:   make(map[party.ID]cipher.AEAD, len(cfg.Peers)),
+		incoming:   make(chan *messages.Message, DefaultQueueSize),
+	}
+	if c.interval <= 0 {
+		c.interval = DefaultPollInterval
+	}
+	if c.maxSize <= 0 || c.maxSize > messages.MaxFrameSize {
+		c.maxSize = messages.MaxFrameSize
+	}
+	if c.minBackoff <= 0 {
+		c.minBackoff = DefaultMinBackoff
+	}
+	if c.maxBackoff <= 0 {
+		c.maxBackoff = DefaultMaxBackoff
+	}
+	if cfg.RelayURL != "" {
+		client := cfg.Client
+		if client == nil {
+			client = http.DefaultClient
+		}
+		c.store = &relayStore{
+			client:    client,
+			url:       strings.TrimSuffix(cfg.RelayURL, "/"),
+			id:        cfg.ID,
+			sessionID: cfg.SessionID,
+		}
+	} else {
+		c.store = newDirectoryStore(cfg.Directory, cfg.ID, cfg.SessionID, c.maxSize)
+	}
+
+	identity := ristretto.NewIdentityElement()
+	for id, public := range cfg.Peers {
+		if id == cfg.ID {
+			continue
+		}
+		if id == 0 || public == nil || public.Equal(identity) == 1 {
+			return nil, fmt.Errorf("transport.NewMailbox: invalid key for party %d", id)
+		}
+		shared := new(ristretto.Element).ScalarMult(cfg.Secret, public)
+		var err error
+		if c.sealKeys[id], err = c.envelopeKey(shared, cfg.ID, id); err != nil {
+			return nil, fmt.Errorf("transport.NewMailbox: %w", err)
+		}
+		if c.openKeys[id], err = c.envelopeKey(shared, id, cfg.ID); err != nil {
+			return nil, fmt.Errorf("transport.NewMailbox: %w", err)
+		}
+	}
+
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	c.wg.Add(1)
+	go c.poll()
+
+	return c, nil
+}
+
+// envelopeKey derives the key for envelopes from one party to another.
+func (c *Mailbox) envelopeKey(shared *ristretto.Element, from, to party.ID) (cipher.AEAD, error) {
+	h := sha512.New()
+	_, _ = h.Write(envelopeDomain)
+	_, _ = h.Write(c.sessionID[:])
+	_, _ = h.Write(from.Bytes())
+	_, _ = h.Write(to.Bytes())
+	_, _ = h.Write(shared.Bytes())
+	digest := h.Sum(nil)
+	defer func() {
+		for i := range digest {
+			digest[i] = 0
+		}
+	}()
+
+	block, err := aes.NewCipher(digest[:32])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Send posts msg to the mailbox of its receiver, or of every other party if it is a broadcast.
+// It retries with an exponential backoff while the relay is unreachable, and returns ErrClosed if Done was called.
+func (c *Mailbox) Send(msg *messages.Message) error {
+	if size := msg.Size(); size > c.maxSize {
+		return fmt.Errorf("transport.Mailbox: message of %d bytes (limit %d): %w", size, c.maxSize, messages.ErrFrameTooLarge)
+	}
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("transport.Mailbox: %w", err)
+	}
+
+	if msg.IsBroadcast() {
+		for id := range c.sealKeys {
+			if err = c.post(id, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if msg.To == c.id {
+		return nil
+	}
+	if _, ok := c.sealKeys[msg.To]; !ok {
+		return fmt.Errorf("transport.Mailbox: no key for party %d", msg.To)
+	}
+	return c.post(msg.To, data)
+}
+
+// post seals data for the party to, and posts it to its mailbox.
+func (c *Mailbox) post(to party.ID, data []byte) error {
+	envelope, err := c.seal(to, data)
+	if err != nil {
+		return fmt.Errorf("transport.Mailbox: %w", err)
+	}
+	backoff := c.minBackoff
+	for {
+		retry, err := c.store.post(c.ctx, to, envelope)
+		if err == nil {
+			return nil
+		}
+		if !retry {
+			return fmt.Errorf("transport.Mailbox: %w", err)
+		}
+		if !sleep(backoff, c.ctx.Done()) {
+			return ErrClosed
+		}
+		backoff = nextBackoff(backoff, c.maxBackoff)
+	}
+}
+
+// poll fetches the mailbox of this party until Done is called.
+func (c *Mailbox) poll() {
+	defer c.wg.Done()
+	defer close(c.incoming)
+
+	backoff := c.interval
+	for {
+		envelopes, more, err := c.store.fetch(c.ctx)
+		if err != nil {
+			if !sleep(backoff, c.ctx.Done()) {
+				return
+			}
+			backoff = nextBackoff(backoff, c.maxBackoff)
+			continue
+		}
+		backoff = c.interval
+
+		for _, envelope := range envelopes {
+			// envelopes which cannot be opened were not created by a party, and are ignored
+			msg, err := c.open(envelope)
+			if err != nil {
+				continue
+			}
+			select {
+			case c.incoming <- msg:
+			case <-c.ctx.Done():
+				return
+			}
+		}
+
+		// fetch again immediately if only part of the mailbox was returned
+		if !more && !sleep(c.interval, c.ctx.Done()) {
+			return
+		}
+	}
+}
+
+// seal returns the envelope containing data for the party to.
+func (c *Mailbox) seal(to party.ID, data []byte) ([]byte, error) {
+	envelope := make([]byte, envelopeHeaderSize+envelopeNonceSize, envelopeHeaderSize+envelopeNonceSize+len(data)+16)
+	envelope[0] = envelopeVersion
+	copy(envelope[1:], c.id.Bytes())
+	copy(envelope[1+party.IDByteSize:], to.Bytes())
+	nonce := envelope[envelopeHeaderSize:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.sealKeys[to].Seal(envelope, nonce, data, c.additionalData(envelope[:envelopeHeaderSize])), nil
+}
+
+// open decrypts an envelope addressed to this party, and checks that the message it contains
+// was sent by the party who sealed it.
+func (c *Mailbox) open(envelope []byte) (*messages.Message, error) {
+	if len(envelope) < envelopeHeaderSize+envelopeNonceSize || envelope[0] != envelopeVersion {
+		return nil, errors.New("invalid envelope")
+	}
+	from, _ := party.FromBytes(envelope[1:])
+	to, _ := party.FromBytes(envelope[1+party.IDByteSize:])
+	if to != c.id {
+		return nil, errors.New("envelope for another party")
+	}
+	aead, ok := c.openKeys[from]
+	if !ok {
+		return nil, errors.New("envelope from an unknown party")
+	}
+	nonce := envelope[envelopeHeaderSize : envelopeHeaderSize+envelopeNonceSize]
+	ciphertext := envelope[envelopeHeaderSize+envelopeNonceSize:]
+	if len(ciphertext) > c.maxSize+aead.Overhead() {
+		return nil, messages.ErrFrameTooLarge
+	}
+	data, err := aead.Open(nil, nonce, ciphertext, c.additionalData(envelope[:envelopeHeaderSize]))
+	if err != nil {
+		return nil, err
+	}
+
+	var msg messages.Message
+	if err = msg.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	if msg.From != from {
+		return nil, errors.New("message was not sent by the sender of the envelope")
+	}
+	return &msg, nil
+}
+
+func (c *Mailbox) additionalData(header []byte) []byte {
+	ad := make([]byte, 0, messages.SessionIDSize+len(header))
+	ad = append(ad, c.sessionID[:]...)
+	return append(ad, header...)
+}
+
+// Incoming returns the channel on which received messages are delivered.
+// It is closed by Done.
+func (c *Mailbox) Incoming() <-chan *messages.Message {
+	return c.incoming
+}
+
+// Done stops fetching messages, and interrupts pending calls to Send.
+// Messages posted before remain on the relay.
+func (c *Mailbox) Done() {
+	c.closeOnce.Do(func() {
+		c.cancel()
+		c.wg.Wait()
+	})
+}
+
+// Timeout returns the Timeout given in the MailboxConfig.
+func (c *Mailbox) Timeout() time.Duration {
+	return c.timeout
+}
@@ -0,0 +1,176 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+)
+
+// A mailboxStore holds the envelopes posted to the mailboxes of a session.
+type mailboxStore interface {
+	// post adds envelope to the mailbox of the party to.
+	// If an error is returned, retry indicates whether it may succeed later.
+	post(ctx context.Context, to party.ID, envelope []byte) (retry bool, err error)
+
+	// fetch returns the envelopes of the mailbox of this party which were not returned by a previous call.
+	// more is true if some were left out, and fetch should be called again immediately.
+	fetch(ctx context.Context) (envelopes [][]byte, more bool, err error)
+}
+
+// relayStore keeps the mailboxes on a Relay.
+type relayStore struct {
+	client    *http.Client
+	url       string
+	id        party.ID
+	sessionID messages.SessionID
+
+	// next is the index of the next envelope to fetch
+	next uint64
+}
+
+func (r *relayStore) post(ctx context.Context, to party.ID, envelope []byte) (bool, error) {
+	return r.do(ctx, http.MethodPost, r.url+mailboxPath(r.sessionID, to), envelope, nil)
+}
+
+func (r *relayStore) fetch(ctx context.Context) ([][]byte, bool, error) {
+	var response fetchResponse
+	url := r.url + mailboxPath(r.sessionID, r.id) + "?after=" + strconv.FormatUint(r.next, 10)
+	if _, err := r.do(ctx, http.MethodGet, url, nil, &response); err != nil {
+		return nil, false, err
+	}
+	r.next = response.Next
+	return response.Messages, len(response.Messages) >= maxFetch, nil
+}
+
+// do sends a request to the relay, and decodes the JSON response into out if it is not nil.
+// If an error is returned, retry indicates whether the request may succeed later.
+func (r *relayStore) do(ctx context.Context, method, url string, body []byte, out interface{}) (retry bool, err error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		retry = resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retry, fmt.Errorf("relay responded with %s", resp.Status)
+	}
+	if out == nil {
+		return false, nil
+	}
+	if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return true, err
+	}
+	return false, nil
+}
+
+// envelopeExt is the extension of the files containing an envelope in a directoryStore.
+const envelopeExt = ".envelope"
+
+// directoryStore keeps the mailboxes in a shared directory.
+// The envelopes for a party are the files
+//
+//   <directory>/<session ID in hex>/<party ID>/<sender ID>-<random>.envelope
+//
+// Each file is written under a temporary name and then renamed, so that it is never read partially.
+// Since the envelopes are encrypted, the directories and files can be accessed by the group,
+// so that parties running as different users can share the directory.
+type directoryStore struct {
+	path    string
+	id      party.ID
+	maxSize int
+
+	// seen are the names of the files already fetched
+	seen map[string]bool
+}
+
+func newDirectoryStore(directory string, id party.ID, sessionID messages.SessionID, maxSize int) *directoryStore {
+	return &directoryStore{
+		path:    filepath.Join(directory, hex.EncodeToString(sessionID[:])),
+		id:      id,
+		maxSize: maxSize,
+		seen:    map[string]bool{},
+	}
+}
+
+func (d *directoryStore) post(_ context.Context, to party.ID, envelope []byte) (bool, error) {
+	dir := filepath.Join(d.path, to.String())
+	if err := os.MkdirAll(dir, 0770); err != nil {
+		return false, err
+	}
+	var suffix [8]byte
+	if _, err := rand.Read(suffix[:]); err != nil {
+		return false, err
+	}
+	name := fmt.Sprintf("%d-%x%s", d.id, suffix, envelopeExt)
+
+	tmp := filepath.Join(dir, "."+name+".tmp")
+	if err := ioutil.WriteFile(tmp, envelope, 0660); err != nil {
+		_ = os.Remove(tmp)
+		return false, err
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+		_ = os.Remove(tmp)
+		return false, err
+	}
+	return false, nil
+}
+
+func (d *directoryStore) fetch(context.Context) ([][]byte, bool, error) {
+	dir := filepath.Join(d.path, d.id.String())
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var envelopes [][]byte
+	for _, file := range files {
+		name := file.Name()
+		if d.seen[name] || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, envelopeExt) {
+			continue
+		}
+		// the envelope also contains its header, nonce and tag
+		if !file.Mode().IsRegular() || file.Size() > int64(d.maxSize+envelopeHeaderSize+envelopeNonceSize+16) {
+			d.seen[name] = true
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			// the file is tried again on the next fetch
+			continue
+		}
+		d.seen[name] = true
+		envelopes = append(envelopes, data)
+	}
+	return envelopes, false, nil
+}
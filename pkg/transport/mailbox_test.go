@@ -0,0 +1,257 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+var testSession = messages.SessionID{0xfa, 0xce}
+
+// newTestMailboxConfigs returns the configuration of a Mailbox for every party, using the relay at url.
+func newTestMailboxConfigs(partyIDs party.IDSlice, url string) map[party.ID]MailboxConfig {
+	secrets := map[party.ID]*ristretto.Scalar{}
+	publics := map[party.ID]*ristretto.Element{}
+	for _, id := range partyIDs {
+		secrets[id], publics[id] = NewMailboxKey()
+	}
+	configs := map[party.ID]MailboxConfig{}
+	for _, id := range partyIDs {
+		configs[id] = MailboxConfig{
+			ID:           id,
+			SessionID:    testSession,
+			RelayURL:     url,
+			Secret:       secrets[id],
+			Peers:        publics,
+			PollInterval: 10 * time.Millisecond,
+		}
+	}
+	return configs
+}
+
+func TestMailbox_Keygen(t *testing.T) {
+	server := httptest.NewServer(NewRelay(RelayConfig{}))
+	defer server.Close()
+
+	partyIDs := helpers.GenerateSet(3)
+	configs := newTestMailboxConfigs(partyIDs, server.URL)
+
+	outputs := map[party.ID]*keygen.Output{}
+	errs := make(chan error, len(partyIDs))
+	for i, id := range partyIDs {
+		// The last party only comes online once the others have sent their first message.
+		if i == len(partyIDs)-1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		comm, err := NewMailbox(configs[id])
+		require.NoError(t, err)
+		defer comm.Done()
+
+		var s *state.State
+		s, outputs[id], err = frost.NewKeygenState(context.Background(), id, partyIDs, 1, comm.Timeout(),
+			state.WithSessionID(testSession))
+		require.NoError(t, err)
+		go func() {
+			errs <- Run(s, comm)
+		}()
+	}
+	for range partyIDs {
+		require.NoError(t, <-errs)
+	}
+
+	groupKey := outputs[partyIDs[0]].Public.GroupKey
+	for _, id := range partyIDs {
+		assert.True(t, groupKey.Equal(outputs[id].Public.GroupKey))
+	}
+}
+
+func TestMailbox_Directory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mailbox")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	partyIDs := helpers.GenerateSet(3)
+	configs := newTestMailboxConfigs(partyIDs, "")
+
+	outputs := map[party.ID]*keygen.Output{}
+	errs := make(chan error, len(partyIDs))
+	for _, id := range partyIDs {
+		config := configs[id]
+		config.Directory = dir
+		comm, err := NewMailbox(config)
+		require.NoError(t, err)
+		defer comm.Done()
+
+		var s *state.State
+		s, outputs[id], err = frost.NewKeygenState(context.Background(), id, partyIDs, 1, comm.Timeout(),
+			state.WithSessionID(testSession))
+		require.NoError(t, err)
+		go func() {
+			errs <- Run(s, comm)
+		}()
+	}
+	for range partyIDs {
+		require.NoError(t, <-errs)
+	}
+
+	groupKey := outputs[partyIDs[0]].Public.GroupKey
+	for _, id := range partyIDs {
+		assert.True(t, groupKey.Equal(outputs[id].Public.GroupKey))
+	}
+
+	// Each party received one envelope from every other party in each round.
+	files, err := ioutil.ReadDir(filepath.Join(dir, hex.EncodeToString(testSession[:]), partyIDs[0].String()))
+	require.NoError(t, err)
+	assert.Len(t, files, 2*(len(partyIDs)-1))
+}
+
+func TestMailbox_Envelope(t *testing.T) {
+	relay := NewRelay(RelayConfig{})
+	server := httptest.NewServer(relay)
+	defer server.Close()
+
+	partyIDs := helpers.GenerateSet(2)
+	sender, receiver := partyIDs[0], partyIDs[1]
+	configs := newTestMailboxConfigs(partyIDs, server.URL)
+
+	c1, err := NewMailbox(configs[sender])
+	require.NoError(t, err)
+	defer c1.Done()
+	c2, err := NewMailbox(configs[receiver])
+	require.NoError(t, err)
+	defer c2.Done()
+
+	msg := keygenMessage(t, sender, partyIDs)
+	require.NoError(t, c1.Send(msg))
+	select {
+	case received := <-c2.Incoming():
+		assert.Equal(t, encode(t, msg), encode(t, received))
+	case <-time.After(10 * time.Second):
+		t.Fatal("message was not delivered")
+	}
+
+	// The relay cannot read the message.
+	relay.mtx.Lock()
+	box := relay.mailboxes[mailboxKey{testSession, receiver}]
+	require.Len(t, box.entries, 1)
+	envelope := append([]byte{}, box.entries[0].data...)
+	relay.mtx.Unlock()
+	payload, err := msg.Payload.MarshalBinary()
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(envelope, payload[:32]))
+
+	// Envelopes which were modified, or sealed with another key, are ignored.
+	envelope[len(envelope)-1] ^= 1
+	postEnvelope(t, server.URL, receiver, envelope)
+	impostorConfig := newTestMailboxConfigs(partyIDs, server.URL)[sender]
+	impostorConfig.Peers = configs[sender].Peers
+	impostor, err := NewMailbox(impostorConfig)
+	require.NoError(t, err)
+	defer impostor.Done()
+	require.NoError(t, impostor.Send(msg))
+
+	select {
+	case received := <-c2.Incoming():
+		t.Fatalf("forged message from %d was delivered", received.From)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestRelay_Expiry(t *testing.T) {
+	now := time.Now()
+	relay := NewRelay(RelayConfig{Retention: time.Hour, MaxMailboxSize: 2})
+	relay.now = func() time.Time { return now }
+	server := httptest.NewServer(relay)
+	defer server.Close()
+
+	postEnvelope(t, server.URL, 1, []byte{1})
+	now = now.Add(30 * time.Minute)
+	postEnvelope(t, server.URL, 1, []byte{2})
+
+	resp, err := http.Post(server.URL+mailboxPath(testSession, 1), "application/octet-stream", bytes.NewReader([]byte{3}))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "mailbox should be full")
+
+	response := fetch(t, server.URL, 1, 0)
+	assert.Equal(t, [][]byte{{1}, {2}}, response.Messages)
+	assert.Equal(t, uint64(2), response.Next)
+
+	// The first envelope expires, but indices are preserved.
+	now = now.Add(45 * time.Minute)
+	response = fetch(t, server.URL, 1, 0)
+	assert.Equal(t, [][]byte{{2}}, response.Messages)
+	assert.Equal(t, uint64(2), response.Next)
+	postEnvelope(t, server.URL, 1, []byte{3})
+	response = fetch(t, server.URL, 1, 2)
+	assert.Equal(t, [][]byte{{3}}, response.Messages)
+	assert.Equal(t, uint64(3), response.Next)
+
+	// Other mailboxes are empty.
+	response = fetch(t, server.URL, 2, 0)
+	assert.Empty(t, response.Messages)
+	assert.Equal(t, uint64(0), response.Next)
+}
+
+func TestRelay_TotalSize(t *testing.T) {
+	now := time.Now()
+	relay := NewRelay(RelayConfig{Retention: time.Hour, MaxTotalSize: 3})
+	relay.now = func() time.Time { return now }
+	server := httptest.NewServer(relay)
+	defer server.Close()
+
+	postEnvelope(t, server.URL, 1, []byte{1, 1})
+	now = now.Add(30 * time.Minute)
+	postEnvelope(t, server.URL, 2, []byte{2})
+
+	// The limit applies to all mailboxes together, including new ones.
+	for _, id := range []party.ID{1, 3} {
+		resp, err := http.Post(server.URL+mailboxPath(testSession, id), "application/octet-stream", bytes.NewReader([]byte{3}))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "relay should be full")
+	}
+	assert.Len(t, relay.mailboxes, 2)
+
+	// Expired envelopes free their space.
+	now = now.Add(45 * time.Minute)
+	postEnvelope(t, server.URL, 3, []byte{3, 3})
+	assert.Equal(t, [][]byte{{3, 3}}, fetch(t, server.URL, 3, 0).Messages)
+	assert.Equal(t, int64(3), relay.size)
+}
+
+func postEnvelope(t *testing.T, url string, to party.ID, envelope []byte) {
+	resp, err := http.Post(url+mailboxPath(testSession, to), "application/octet-stream", bytes.NewReader(envelope))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func fetch(t *testing.T, url string, id party.ID, after uint64) fetchResponse {
+	resp, err := http.Get(url + mailboxPath(testSession, id) + "?after=" + strconv.FormatUint(after, 10))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var response fetchResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	return response
+}
@@ -0,0 +1,287 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+)
+
+const (
+	// DefaultRetention is the time during which a Relay keeps a message.
+	DefaultRetention = 24 * time.Hour
+
+	// DefaultRelayMessageSize is the size of the largest envelope accepted by a Relay.
+	DefaultRelayMessageSize = 1 << 20
+
+	// DefaultMailboxSize is the number of envelopes a mailbox can hold.
+	DefaultMailboxSize = 4096
+
+	// DefaultRelaySize is the total size of the envelopes a Relay holds in all mailboxes.
+	DefaultRelaySize = 1 << 30
+
+	// maxFetch is the maximum number of envelopes returned by a single fetch.
+	maxFetch = 256
+)
+
+// RelayConfig configures a Relay. The zero value uses the default limits.
+type RelayConfig struct {
+	// Retention is the time after which a message is deleted, whether it was fetched or not.
+	Retention time.Duration
+
+	// MaxMessageSize is the size of the largest envelope which can be posted.
+	MaxMessageSize int
+
+	// MaxMailboxSize is the number of envelopes a mailbox can hold.
+	// Posting to a full mailbox fails with 503 Service Unavailable until older envelopes expire.
+	MaxMailboxSize int
+
+	// MaxTotalSize is the total size in bytes of the envelopes held in all mailboxes,
+	// which bounds the memory used by the Relay, however many mailboxes are created.
+	// Posting fails with 503 Service Unavailable while the Relay is full.
+	MaxTotalSize int64
+}
+
+// Relay is an http.Handler which stores envelopes in the mailbox of their receiver,
+// until the receiver fetches them.
+// This lets parties which are never online at the same time execute a protocol.
+//
+// Mailboxes are identified by a session and a party:
+//
+//	POST /v1/sessions/<session ID in hex>/parties/<party ID>/messages
+//	GET  /v1/sessions/<session ID in hex>/parties/<party ID>/messages?after=<index>
+//
+// A POST request stores its body as an envelope. A GET request returns the envelopes with an index
+// greater or equal to after, as a JSON object {"next": <index>, "messages": [<base64>, ...]}.
+// Envelopes are kept until they expire, so that a party can fetch them again after a restart.
+//
+// The Relay does not authenticate anyone, and handles envelopes as opaque data:
+// the Mailbox client encrypts and authenticates every envelope for its receiver.
+// Therefore, anyone who can reach the Relay and knows a session ID can fill the mailboxes of that session,
+// or the whole Relay, so that posting fails until the envelopes expire.
+// Envelopes are never lost or forged this way, since the Mailbox client keeps retrying and ignores
+// envelopes it cannot authenticate, but the protocol is delayed. Session IDs should therefore be random,
+// and a Relay which is reachable by others than the parties should be put behind an authenticating proxy.
+type Relay struct {
+	retention      time.Duration
+	maxMessageSize int
+	maxMailboxSize int
+	maxTotalSize   int64
+
+	// size is the total size of the envelopes in all mailboxes
+	size int64
+
+	// now returns the current time, and is replaced in tests
+	now func() time.Time
+
+	mailboxes map[mailboxKey]*mailbox
+	lastSweep time.Time
+	mtx       sync.Mutex
+}
+
+type mailboxKey struct {
+	session messages.SessionID
+	id      party.ID
+}
+
+type mailbox struct {
+	// first is the index of entries[0]
+	first   uint64
+	entries []mailboxEntry
+}
+
+type mailboxEntry struct {
+	data    []byte
+	expires time.Time
+}
+
+type fetchResponse struct {
+	Next     uint64   `json:"next"`
+	Messages [][]byte `json:"messages"`
+}
+
+// NewRelay returns an empty Relay.
+func NewRelay(cfg RelayConfig) *Relay {
+	r := &Relay{
+		retention:      cfg.Retention,
+		maxMessageSize: cfg.MaxMessageSize,
+		maxMailboxSize: cfg.MaxMailboxSize,
+		maxTotalSize:   cfg.MaxTotalSize,
+		now:            time.Now,
+		mailboxes:      map[mailboxKey]*mailbox{},
+	}
+	if r.retention <= 0 {
+		r.retention = DefaultRetention
+	}
+	if r.maxMessageSize <= 0 {
+		r.maxMessageSize = DefaultRelayMessageSize
+	}
+	if r.maxMailboxSize <= 0 {
+		r.maxMailboxSize = DefaultMailboxSize
+	}
+	if r.maxTotalSize <= 0 {
+		r.maxTotalSize = DefaultRelaySize
+	}
+	return r
+}
+
+// ServeHTTP implements http.Handler.
+func (r *Relay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	key, err := parseMailboxPath(req.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodPost:
+		r.post(w, req, key)
+	case http.MethodGet:
+		r.fetch(w, req, key)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (r *Relay) post(w http.ResponseWriter, req *http.Request, key mailboxKey) {
+	data, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, int64(r.maxMessageSize)))
+	if err != nil {
+		http.Error(w, "message too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if len(data) == 0 {
+		http.Error(w, "empty message", http.StatusBadRequest)
+		return
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	now := r.now()
+	r.sweep(now)
+
+	box, ok := r.mailboxes[key]
+	if ok {
+		r.size -= box.expire(now)
+		if len(box.entries) >= r.maxMailboxSize {
+			http.Error(w, "mailbox is full", http.StatusServiceUnavailable)
+			return
+		}
+	}
+	if r.size+int64(len(data)) > r.maxTotalSize {
+		http.Error(w, "relay is full", http.StatusServiceUnavailable)
+		return
+	}
+	if !ok {
+		box = &mailbox{}
+		r.mailboxes[key] = box
+	}
+	box.entries = append(box.entries, mailboxEntry{
+		data:    data,
+		expires: now.Add(r.retention),
+	})
+	r.size += int64(len(data))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *Relay) fetch(w http.ResponseWriter, req *http.Request, key mailboxKey) {
+	var after uint64
+	if s := req.URL.Query().Get("after"); s != "" {
+		var err error
+		if after, err = strconv.ParseUint(s, 10, 64); err != nil {
+			http.Error(w, "invalid after parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	r.mtx.Lock()
+	now := r.now()
+	r.sweep(now)
+	response := fetchResponse{
+		Next:     after,
+		Messages: [][]byte{},
+	}
+	if box, ok := r.mailboxes[key]; ok {
+		r.size -= box.expire(now)
+		if after < box.first {
+			after = box.first
+		}
+		end := box.first + uint64(len(box.entries))
+		if after < end {
+			entries := box.entries[after-box.first:]
+			if len(entries) > maxFetch {
+				entries = entries[:maxFetch]
+			}
+			for _, entry := range entries {
+				response.Messages = append(response.Messages, entry.data)
+			}
+			response.Next = after + uint64(len(entries))
+		} else if response.Next > end {
+			response.Next = end
+		}
+	}
+	r.mtx.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// sweep deletes expired envelopes from all mailboxes, and the mailboxes which become empty,
+// at most once per minute. Clients of a deleted mailbox start again from the index returned by the next fetch.
+// r.mtx must be held.
+func (r *Relay) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < time.Minute {
+		return
+	}
+	r.lastSweep = now
+	for key, box := range r.mailboxes {
+		r.size -= box.expire(now)
+		if len(box.entries) == 0 {
+			delete(r.mailboxes, key)
+		}
+	}
+}
+
+// expire removes the envelopes which expired before now, and returns their total size.
+// Since they all have the same retention, they expire in the order they were posted.
+func (b *mailbox) expire(now time.Time) int64 {
+	n := 0
+	var size int64
+	for n < len(b.entries) && !now.Before(b.entries[n].expires) {
+		size += int64(len(b.entries[n].data))
+		b.entries[n].data = nil
+		n++
+	}
+	b.entries = b.entries[n:]
+	b.first += uint64(n)
+	return size
+}
+
+// mailboxPath returns the path of the mailbox of id for session.
+func mailboxPath(session messages.SessionID, id party.ID) string {
+	return fmt.Sprintf("/v1/sessions/%s/parties/%s/messages", session, id)
+}
+
+func parseMailboxPath(path string) (mailboxKey, error) {
+	var key mailboxKey
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 6 || parts[0] != "v1" || parts[1] != "sessions" || parts[3] != "parties" || parts[5] != "messages" {
+		return key, fmt.Errorf("unknown path %q", path)
+	}
+	if err := key.session.UnmarshalText([]byte(parts[2])); err != nil {
+		return key, err
+	}
+	id, err := strconv.ParseUint(parts[4], 10, 16)
+	if err != nil || id == 0 {
+		return key, fmt.Errorf("invalid party ID %q", parts[4])
+	}
+	key.id = party.ID(id)
+	return key, nil
+}
@@ -0,0 +1,374 @@
+// Package simnet simulates a network between the parties of a protocol, for testing.
+//
+// All parties run in the same goroutine, and the network decides when each message is delivered,
+// duplicated or lost, according to its Faults and a pseudo-random generator.
+// Time is simulated, and the same seed always produces the same schedule,
+// so that a failing execution can be reproduced from its seed:
+//
+//	network := simnet.New(seed, simnet.Faults{Latency: 10 * time.Millisecond, Jitter: 20 * time.Millisecond, DropRate: 0.01})
+//	states := map[party.ID]*state.State{}
+//	for _, id := range partyIDs {
+//		states[id], outputs[id], _ = frost.NewKeygenState(ctx, id, partyIDs, threshold, 0)
+//	}
+//	network.Run(states)
+//
+// When no message is left in flight, the parties which have not finished are expired with state.State.Expire,
+// so that their error names the parties they are waiting for, as if their timeout had fired.
+package simnet
+
+import (
+	"container/heap"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+	"github.com/taurusgroup/frost-ed25519/pkg/transport"
+)
+
+// incomingSize is the capacity of the Incoming channel of a Node.
+const incomingSize = 1024
+
+// Faults describes how the network treats messages.
+// Each message to each receiver is treated independently.
+type Faults struct {
+	// Latency is the minimum delay before a message is delivered.
+	Latency time.Duration
+
+	// Jitter is the maximum random delay added to Latency.
+	// Messages sent at the same time are delivered in a random order if it is not 0.
+	Jitter time.Duration
+
+	// DropRate is the probability that a message is lost.
+	DropRate float64
+
+	// DuplicateRate is the probability that a message is delivered a second time, with its own delay.
+	DuplicateRate float64
+}
+
+// Fate is what happened to a message.
+type Fate int
+
+const (
+	// Delivered messages were given to their receiver.
+	Delivered Fate = iota
+	// Dropped messages were lost by the network.
+	Dropped
+	// Partitioned messages were lost because the sender and receiver were in different partitions.
+	Partitioned
+)
+
+// String returns the name of the Fate.
+func (f Fate) String() string {
+	switch f {
+	case Delivered:
+		return "delivered"
+	case Dropped:
+		return "dropped"
+	case Partitioned:
+		return "partitioned"
+	}
+	return "unknown"
+}
+
+// Event records what happened to a message.
+type Event struct {
+	// At is the simulated time at which the message was delivered or lost.
+	At   time.Duration
+	From party.ID
+	To   party.ID
+	Type messages.MessageType
+	Fate Fate
+}
+
+// Network is a simulated network.
+// It is not safe for concurrent use.
+type Network struct {
+	rng    *rand.Rand
+	faults Faults
+	now    time.Duration
+
+	nodes map[party.ID]*Node
+
+	// pending are the messages sent since the last step, which are scheduled in a deterministic order.
+	pending []*pendingMessage
+	queue   eventQueue
+	seq     uint64
+
+	partition map[party.ID]int
+	trace     []Event
+}
+
+// New returns a Network with no nodes, whose random choices are derived from seed.
+func New(seed int64, faults Faults) *Network {
+	return &Network{
+		rng:    rand.New(rand.NewSource(seed)),
+		faults: faults,
+		nodes:  map[party.ID]*Node{},
+	}
+}
+
+// Node returns the Communicator of the party id, and adds it to the network if needed.
+// Broadcast messages are sent to all other nodes.
+func (n *Network) Node(id party.ID) *Node {
+	node, ok := n.nodes[id]
+	if !ok {
+		node = &Node{
+			id:       id,
+			network:  n,
+			incoming: make(chan *messages.Message, incomingSize),
+		}
+		n.nodes[id] = node
+	}
+	return node
+}
+
+// Now returns the current simulated time.
+func (n *Network) Now() time.Duration {
+	return n.now
+}
+
+// Trace returns the Event s recorded so far, in the order in which they happened.
+func (n *Network) Trace() []Event {
+	return append([]Event{}, n.trace...)
+}
+
+// Partition splits the network into groups, such that messages between two groups are lost.
+// Parties which are not in any group form an additional group together.
+func (n *Network) Partition(groups ...party.IDSlice) {
+	n.partition = map[party.ID]int{}
+	for i, group := range groups {
+		for _, id := range group {
+			n.partition[id] = i + 1
+		}
+	}
+}
+
+// Heal removes the partition, so that all parties can communicate again.
+// Messages lost because of the partition are not recovered.
+func (n *Network) Heal() {
+	n.partition = nil
+}
+
+// At schedules f to be called when the simulated time reaches at.
+// Events happening at the same time are processed in the order in which they were scheduled.
+// It can be used to change the Faults, or to create and heal partitions during an execution.
+func (n *Network) At(at time.Duration, f func()) {
+	n.push(&event{at: at, action: f})
+}
+
+// SetFaults replaces the Faults for the messages sent from now on.
+func (n *Network) SetFaults(faults Faults) {
+	n.faults = faults
+}
+
+// Step schedules the messages sent since the last call, and delivers the next one to the Incoming channel of its receiver.
+// It returns false when no message is left in flight.
+func (n *Network) Step() bool {
+	n.flush()
+	for n.queue.Len() > 0 {
+		e := heap.Pop(&n.queue).(*event)
+		n.now = e.at
+		if e.action != nil {
+			e.action()
+			n.flush()
+			continue
+		}
+
+		var msg messages.Message
+		if err := msg.UnmarshalBinary(e.data); err != nil {
+			panic(err)
+		}
+		n.record(msg.From, e.to, msg.Type, Delivered)
+		if node := n.nodes[e.to]; !node.done {
+			node.incoming <- &msg
+		}
+		return true
+	}
+	return false
+}
+
+// Run executes the protocols of all states until no message is left in flight,
+// and then expires the states which have not finished.
+// The nodes of the parties are used as their Communicator.
+func (n *Network) Run(states map[party.ID]*state.State) {
+	ids := make([]party.ID, 0, len(states))
+	for id := range states {
+		ids = append(ids, id)
+	}
+	ids = party.NewIDSlice(ids)
+
+	for _, id := range ids {
+		n.Node(id)
+	}
+	for _, id := range ids {
+		_ = transport.ProcessAll(states[id], n.Node(id))
+	}
+	for n.Step() {
+		for _, id := range ids {
+			node := n.nodes[id]
+			for len(node.incoming) > 0 {
+				_ = states[id].HandleMessage(<-node.incoming)
+				_ = transport.ProcessAll(states[id], node)
+			}
+		}
+	}
+	for _, id := range ids {
+		states[id].Expire()
+	}
+}
+
+// flush decides the fate of the messages sent since the last call.
+// They are first sorted, since the order in which a round generates its messages is not necessarily deterministic.
+func (n *Network) flush() {
+	sort.SliceStable(n.pending, func(i, j int) bool {
+		a, b := n.pending[i], n.pending[j]
+		if a.from != b.from {
+			return a.from < b.from
+		}
+		if a.to != b.to {
+			return a.to < b.to
+		}
+		return a.msgType < b.msgType
+	})
+	for _, p := range n.pending {
+		if n.partition != nil && n.partition[p.from] != n.partition[p.to] {
+			n.record(p.from, p.to, p.msgType, Partitioned)
+			continue
+		}
+		if n.rng.Float64() < n.faults.DropRate {
+			n.record(p.from, p.to, p.msgType, Dropped)
+			continue
+		}
+		copies := 1
+		if n.rng.Float64() < n.faults.DuplicateRate {
+			copies = 2
+		}
+		for i := 0; i < copies; i++ {
+			n.push(&event{
+				at:   n.now + n.delay(),
+				to:   p.to,
+				data: p.data,
+			})
+		}
+	}
+	n.pending = n.pending[:0]
+}
+
+func (n *Network) delay() time.Duration {
+	d := n.faults.Latency
+	if n.faults.Jitter > 0 {
+		d += time.Duration(n.rng.Int63n(int64(n.faults.Jitter)))
+	}
+	return d
+}
+
+func (n *Network) push(e *event) {
+	e.seq = n.seq
+	n.seq++
+	heap.Push(&n.queue, e)
+}
+
+func (n *Network) record(from, to party.ID, msgType messages.MessageType, fate Fate) {
+	n.trace = append(n.trace, Event{
+		At:   n.now,
+		From: from,
+		To:   to,
+		Type: msgType,
+		Fate: fate,
+	})
+}
+
+// Node is the Communicator of a party in a Network.
+type Node struct {
+	id       party.ID
+	network  *Network
+	incoming chan *messages.Message
+	done     bool
+}
+
+// Send gives msg to the network, which decides when it is delivered on the next call to Network.Step.
+func (c *Node) Send(msg *messages.Message) error {
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	n := c.network
+	if msg.IsBroadcast() {
+		for id := range n.nodes {
+			if id != c.id {
+				n.pending = append(n.pending, &pendingMessage{from: c.id, to: id, msgType: msg.Type, data: data})
+			}
+		}
+		return nil
+	}
+	if msg.To == c.id {
+		return nil
+	}
+	if _, ok := n.nodes[msg.To]; !ok {
+		return transport.ErrClosed
+	}
+	n.pending = append(n.pending, &pendingMessage{from: c.id, to: msg.To, msgType: msg.Type, data: data})
+	return nil
+}
+
+// Incoming returns the channel on which the network delivers messages.
+// It is filled by Network.Step, which blocks if it holds more than 1024 messages.
+func (c *Node) Incoming() <-chan *messages.Message {
+	return c.incoming
+}
+
+// Done closes the Incoming channel, and the messages for this node are discarded from now on.
+func (c *Node) Done() {
+	if !c.done {
+		c.done = true
+		close(c.incoming)
+	}
+}
+
+// Timeout returns 0, since timeouts are simulated by expiring the states when the network is idle.
+func (c *Node) Timeout() time.Duration {
+	return 0
+}
+
+type pendingMessage struct {
+	from, to party.ID
+	msgType  messages.MessageType
+	data     []byte
+}
+
+type event struct {
+	at  time.Duration
+	seq uint64
+
+	// either a message to deliver, or an action
+	to     party.ID
+	data   []byte
+	action func()
+}
+
+// eventQueue orders events by time, and then by the order in which they were scheduled.
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x interface{}) { *q = append(*q, x.(*event)) }
+
+func (q *eventQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,237 @@
+package simnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+const seeds = 20
+
+var message = []byte("simulated message")
+
+type faultModel struct {
+	name   string
+	faults Faults
+	// lossy is true if messages can be lost, in which case the protocol may abort.
+	lossy bool
+	// setup is called before the execution
+	setup func(n *Network, partyIDs party.IDSlice)
+}
+
+var faultModels = []faultModel{
+	{name: "perfect"},
+	{name: "latency", faults: Faults{Latency: 50 * time.Millisecond}},
+	{name: "reordering", faults: Faults{Latency: time.Millisecond, Jitter: 100 * time.Millisecond}},
+	{name: "duplication", faults: Faults{Jitter: 10 * time.Millisecond, DuplicateRate: 0.5}},
+	{name: "drops", faults: Faults{Jitter: 10 * time.Millisecond, DropRate: 0.05}, lossy: true},
+	{
+		name:  "partition",
+		lossy: true,
+		setup: func(n *Network, partyIDs party.IDSlice) {
+			n.Partition(partyIDs[:1])
+		},
+	},
+	{
+		name:   "temporary partition",
+		faults: Faults{Latency: 10 * time.Millisecond, Jitter: 10 * time.Millisecond},
+		lossy:  true,
+		setup: func(n *Network, partyIDs party.IDSlice) {
+			// Only some of the messages of the second round are sent during the partition.
+			n.At(5*time.Millisecond, func() { n.Partition(partyIDs[:2], partyIDs[2:]) })
+			n.At(15*time.Millisecond, n.Heal)
+		},
+	},
+}
+
+func newNetwork(model faultModel, seed int64, partyIDs party.IDSlice) *Network {
+	n := New(seed, model.faults)
+	if model.setup != nil {
+		model.setup(n, partyIDs)
+	}
+	return n
+}
+
+func TestKeygen(t *testing.T) {
+	const T = 2
+	partyIDs := helpers.GenerateSet(5)
+	roundTypes := []messages.MessageType{messages.MessageTypeNone, messages.MessageTypeKeyGen1, messages.MessageTypeKeyGen2}
+
+	for _, model := range faultModels {
+		aborted := 0
+		for seed := int64(0); seed < seeds; seed++ {
+			t.Run(fmt.Sprintf("%s/%d", model.name, seed), func(t *testing.T) {
+				n := newNetwork(model, seed, partyIDs)
+				states := map[party.ID]*state.State{}
+				outputs := map[party.ID]*keygen.Output{}
+				for _, id := range partyIDs {
+					var err error
+					states[id], outputs[id], err = frost.NewKeygenState(context.Background(), id, partyIDs, T, 0)
+					require.NoError(t, err)
+				}
+				n.Run(states)
+
+				var succeeded []party.ID
+				for _, id := range partyIDs {
+					if checkOutcome(t, n, model, partyIDs, id, states[id], roundTypes) {
+						succeeded = append(succeeded, id)
+					}
+				}
+				for _, id := range succeeded {
+					assert.True(t, outputs[id].Public.GroupKey.Equal(outputs[succeeded[0]].Public.GroupKey))
+				}
+				if len(succeeded) < len(partyIDs) {
+					aborted++
+				}
+			})
+		}
+		assertAborted(t, model, aborted)
+	}
+}
+
+func TestSign(t *testing.T) {
+	const T = 2
+	partyIDs := helpers.GenerateSet(5)
+	_, secrets := helpers.GenerateSecrets(partyIDs, T)
+	public := helpers.GeneratePublic(T, secrets)
+	signIDs := partyIDs[:T+1]
+	roundTypes := []messages.MessageType{messages.MessageTypeNone, messages.MessageTypeSign1, messages.MessageTypeSign2}
+
+	for _, model := range faultModels {
+		aborted := 0
+		for seed := int64(0); seed < seeds; seed++ {
+			t.Run(fmt.Sprintf("%s/%d", model.name, seed), func(t *testing.T) {
+				n := newNetwork(model, seed, signIDs)
+				states := map[party.ID]*state.State{}
+				outputs := map[party.ID]*sign.Output{}
+				for _, id := range signIDs {
+					var err error
+					states[id], outputs[id], err = frost.NewSignState(context.Background(), signIDs, secrets[id], public, message, 0)
+					require.NoError(t, err)
+				}
+				n.Run(states)
+
+				failed := false
+				for _, id := range signIDs {
+					if checkOutcome(t, n, model, signIDs, id, states[id], roundTypes) {
+						assert.True(t, public.GroupKey.Verify(message, outputs[id].Signature))
+					} else {
+						failed = true
+					}
+				}
+				if failed {
+					aborted++
+				}
+			})
+		}
+		assertAborted(t, model, aborted)
+	}
+}
+
+// assertAborted checks that lossy fault models caused at least one abort, so that the blame was actually tested.
+func assertAborted(t *testing.T, model faultModel, aborted int) {
+	if model.lossy {
+		assert.NotZero(t, aborted, "no execution aborted with %s", model.name)
+	} else {
+		assert.Zero(t, aborted)
+	}
+}
+
+// checkOutcome verifies that the party id either finished successfully,
+// or that it was expired while waiting for messages which were never delivered to it,
+// and that it blamed exactly the parties whose messages are missing.
+// It returns true if the party succeeded.
+func checkOutcome(t *testing.T, n *Network, model faultModel, partyIDs party.IDSlice, id party.ID, s *state.State, roundTypes []messages.MessageType) bool {
+	err := s.Err()
+	if err == nil {
+		return true
+	}
+	require.True(t, model.lossy, "party %d failed without message loss: %v", id, err)
+
+	var timeout *state.TimeoutError
+	require.True(t, errors.As(err, &timeout), "party %d: %v", id, err)
+	require.NotEmpty(t, timeout.Missing)
+
+	expected := roundTypes[timeout.RoundNumber]
+	received := map[party.ID]bool{}
+	for _, e := range n.Trace() {
+		if e.To == id && e.Type == expected && e.Fate == Delivered {
+			received[e.From] = true
+		}
+	}
+	var missing party.IDSlice
+	for _, other := range partyIDs {
+		if other != id && !received[other] {
+			missing = append(missing, other)
+		}
+	}
+	assert.Equal(t, missing, timeout.Missing, "party %d", id)
+	return false
+}
+
+func TestDeterminism(t *testing.T) {
+	partyIDs := helpers.GenerateSet(4)
+	faults := Faults{Latency: time.Millisecond, Jitter: 50 * time.Millisecond, DropRate: 0.05, DuplicateRate: 0.2}
+
+	run := func(seed int64) ([]Event, map[party.ID]bool) {
+		n := New(seed, faults)
+		states := map[party.ID]*state.State{}
+		for _, id := range partyIDs {
+			var err error
+			states[id], _, err = frost.NewKeygenState(context.Background(), id, partyIDs, 2, 0)
+			require.NoError(t, err)
+		}
+		n.Run(states)
+		succeeded := map[party.ID]bool{}
+		for id, s := range states {
+			succeeded[id] = s.Err() == nil
+		}
+		return n.Trace(), succeeded
+	}
+
+	trace1, succeeded1 := run(42)
+	trace2, succeeded2 := run(42)
+	assert.Equal(t, trace1, trace2)
+	assert.Equal(t, succeeded1, succeeded2)
+
+	trace3, _ := run(43)
+	assert.NotEqual(t, trace1, trace3)
+}
+
+func TestPartitionBlame(t *testing.T) {
+	partyIDs := helpers.GenerateSet(4)
+	isolated := partyIDs[0]
+
+	n := New(1, Faults{})
+	n.Partition(party.IDSlice{isolated})
+	states := map[party.ID]*state.State{}
+	for _, id := range partyIDs {
+		var err error
+		states[id], _, err = frost.NewKeygenState(context.Background(), id, partyIDs, 2, 0)
+		require.NoError(t, err)
+	}
+	n.Run(states)
+
+	for _, id := range partyIDs {
+		var timeout *state.TimeoutError
+		require.True(t, errors.As(states[id].Err(), &timeout))
+		assert.Equal(t, 1, timeout.RoundNumber)
+		if id == isolated {
+			assert.Equal(t, partyIDs[1:], timeout.Missing)
+		} else {
+			assert.Equal(t, party.IDSlice{isolated}, timeout.Missing)
+		}
+	}
+}
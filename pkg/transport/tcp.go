@@ -0,0 +1,467 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+)
+
+const (
+	// DefaultQueueSize is the number of messages buffered for each peer, and for incoming messages.
+	DefaultQueueSize = 64
+
+	// DefaultMinBackoff and DefaultMaxBackoff bound the delay between two connection attempts to a peer.
+	DefaultMinBackoff = 50 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+
+	// handshakeTimeout bounds the time taken to establish a connection.
+	handshakeTimeout = 10 * time.Second
+)
+
+// Peer is an entry in the directory of a TCP communicator.
+type Peer struct {
+	// Address is the host:port on which the peer accepts connections.
+	Address string
+
+	// Name is the name that the certificate of the peer must be valid for,
+	// as checked by x509.Certificate.VerifyHostname.
+	// It must be unique in the directory.
+	Name string
+}
+
+// Config configures a TCP communicator.
+type Config struct {
+	// ID is the party.ID of this party.
+	ID party.ID
+
+	// Peers is the directory of all parties, which may include this party.
+	Peers map[party.ID]Peer
+
+	// TLS must contain the certificate of this party, and the CAs which issued the certificates
+	// of the peers in RootCAs and ClientCAs.
+	// It is used both for accepting connections, where the client certificate is required,
+	// and for connecting to peers, where ServerName is set to the Name of the peer.
+	TLS *tls.Config
+
+	// Listener accepts the connections from peers.
+	// If it is nil, then the communicator listens on the Address of its own entry in Peers.
+	Listener net.Listener
+
+	// MaxMessageSize is the size of the largest message that can be sent or received,
+	// see messages.MaxMessageSize. If it is 0, then messages.MaxFrameSize is used.
+	MaxMessageSize int
+
+	// QueueSize is the number of messages buffered for each peer.
+	// When the queue of a peer is full, Send blocks until the peer catches up.
+	// If it is 0, then DefaultQueueSize is used.
+	QueueSize int
+
+	// MinBackoff and MaxBackoff bound the exponential backoff between connection attempts.
+	// If they are 0, then DefaultMinBackoff and DefaultMaxBackoff are used.
+	MinBackoff, MaxBackoff time.Duration
+
+	// Timeout is returned by TCP.Timeout.
+	Timeout time.Duration
+}
+
+// TCP is a Communicator which exchanges messages over TLS connections with mutual authentication.
+//
+// Each party connects to every other party to send its messages, and accepts their connections to receive theirs.
+// A received message is only delivered if its From field is the party whose certificate was presented for the connection.
+// Connections are reestablished when they fail, and a message is sent again if it could not be written.
+// A message written to a connection which fails before the peer reads it is lost,
+// and it is up to the protocol timeout to detect it.
+type TCP struct {
+	id      party.ID
+	peers   map[party.ID]Peer
+	maxSize int
+	timeout time.Duration
+
+	minBackoff, maxBackoff time.Duration
+
+	serverTLS *tls.Config
+	clientTLS map[party.ID]*tls.Config
+	listener  net.Listener
+
+	queues   map[party.ID]chan []byte
+	incoming chan *messages.Message
+
+	closed    chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+
+	// conns are all open connections, which are closed by Done
+	conns map[net.Conn]struct{}
+	mtx   sync.Mutex
+}
+
+// NewTCPCommunicator starts accepting connections from the peers in cfg.Peers,
+// and returns a TCP communicator which connects to them when the first message is sent.
+func NewTCPCommunicator(cfg Config) (*TCP, error) {
+	if cfg.TLS == nil || len(cfg.TLS.Certificates) == 0 {
+		return nil, errors.New("transport.NewTCPCommunicator: a TLS certificate is required")
+	}
+
+	names := make(map[string]party.ID, len(cfg.Peers))
+	peers := make(map[party.ID]Peer, len(cfg.Peers))
+	for id, peer := range cfg.Peers {
+		if id == 0 {
+			return nil, errors.New("transport.NewTCPCommunicator: peer with ID 0")
+		}
+		if id == cfg.ID {
+			continue
+		}
+		if peer.Name == "" || peer.Address == "" {
+			return nil, fmt.Errorf("transport.NewTCPCommunicator: peer %d must have a name and an address", id)
+		}
+		if other, ok := names[peer.Name]; ok {
+			return nil, fmt.Errorf("transport.NewTCPCommunicator: peers %d and %d have the same name", other, id)
+		}
+		names[peer.Name] = id
+		peers[id] = peer
+	}
+
+	listener := cfg.Listener
+	if listener == nil {
+		self, ok := cfg.Peers[cfg.ID]
+		if !ok {
+			return nil, errors.New("transport.NewTCPCommunicator: no listener and no address for this party")
+		}
+		var err error
+		if listener, err = net.Listen("tcp", self.Address); err != nil {
+			return nil, fmt.Errorf("transport.NewTCPCommunicator: %w", err)
+		}
+	}
+
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+
+	c := &TCP{
+		id:         cfg.ID,
+		peers:      peers,
+		maxSize:    cfg.MaxMessageSize,
+		timeout:    cfg.Timeout,
+		minBackoff: cfg.MinBackoff,
+		maxBackoff: cfg.MaxBackoff,
+		clientTLS:  make(map[party.ID]*tls.Config, len(peers)),
+		listener:   listener,
+		queues:     make(map[party.ID]chan []byte, len(peers)),
+		incoming:   make(chan *messages.Message, queueSize),
+		closed:     make(chan struct{}),
+		conns:      map[net.Conn]struct{}{},
+	}
+	if c.minBackoff <= 0 {
+		c.minBackoff = DefaultMinBackoff
+	}
+	if c.maxBackoff <= 0 {
+		c.maxBackoff = DefaultMaxBackoff
+	}
+
+	c.serverTLS = cfg.TLS.Clone()
+	c.serverTLS.ClientAuth = tls.RequireAndVerifyClientCert
+	if c.serverTLS.MinVersion < tls.VersionTLS12 {
+		c.serverTLS.MinVersion = tls.VersionTLS12
+	}
+	for id, peer := range peers {
+		clientTLS := c.serverTLS.Clone()
+		clientTLS.ServerName = peer.Name
+		c.clientTLS[id] = clientTLS
+
+		queue := make(chan []byte, queueSize)
+		c.queues[id] = queue
+		c.wg.Add(1)
+		go c.send(id, queue)
+	}
+
+	c.wg.Add(1)
+	go c.accept()
+
+	return c, nil
+}
+
+// Addr returns the address on which the communicator accepts connections.
+func (c *TCP) Addr() net.Addr {
+	return c.listener.Addr()
+}
+
+// Send queues msg for its receiver, or for all peers if it is a broadcast.
+// It blocks while the queue of a receiver is full, and returns ErrClosed if Done was called.
+func (c *TCP) Send(msg *messages.Message) error {
+	var buf bytes.Buffer
+	if err := messages.NewEncoder(&buf, c.maxSize).Encode(msg); err != nil {
+		return fmt.Errorf("transport.TCP: %w", err)
+	}
+	frame := buf.Bytes()
+
+	if msg.IsBroadcast() {
+		for _, queue := range c.queues {
+			if err := c.enqueue(queue, frame); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if msg.To == c.id {
+		return nil
+	}
+	queue, ok := c.queues[msg.To]
+	if !ok {
+		return fmt.Errorf("transport.TCP: party %d is not in the directory", msg.To)
+	}
+	return c.enqueue(queue, frame)
+}
+
+func (c *TCP) enqueue(queue chan<- []byte, frame []byte) error {
+	select {
+	case <-c.closed:
+		return ErrClosed
+	default:
+	}
+	select {
+	case queue <- frame:
+		return nil
+	case <-c.closed:
+		return ErrClosed
+	}
+}
+
+// Incoming returns the channel on which received messages are delivered.
+// It is closed by Done.
+func (c *TCP) Incoming() <-chan *messages.Message {
+	return c.incoming
+}
+
+// Done closes all connections and stops accepting new ones.
+// Messages which were not yet sent are discarded.
+func (c *TCP) Done() {
+	c.closeOnce.Do(func() {
+		c.mtx.Lock()
+		close(c.closed)
+		for conn := range c.conns {
+			_ = conn.Close()
+		}
+		c.mtx.Unlock()
+		_ = c.listener.Close()
+
+		c.wg.Wait()
+		close(c.incoming)
+	})
+}
+
+// Timeout returns the Timeout given in the Config.
+func (c *TCP) Timeout() time.Duration {
+	return c.timeout
+}
+
+// track registers conn so that it is closed by Done.
+// It returns false, after closing conn, if Done was already called.
+func (c *TCP) track(conn net.Conn) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	select {
+	case <-c.closed:
+		_ = conn.Close()
+		return false
+	default:
+	}
+	c.conns[conn] = struct{}{}
+	return true
+}
+
+// release closes conn and forgets it.
+func (c *TCP) release(conn net.Conn) {
+	c.mtx.Lock()
+	delete(c.conns, conn)
+	c.mtx.Unlock()
+	_ = conn.Close()
+}
+
+//
+// Receiving
+//
+
+func (c *TCP) accept() {
+	defer c.wg.Done()
+	backoff := c.minBackoff
+	for {
+		conn, err := c.listener.Accept()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Temporary() {
+				if !sleep(backoff, c.closed) {
+					return
+				}
+				backoff = nextBackoff(backoff, c.maxBackoff)
+				continue
+			}
+			return
+		}
+		backoff = c.minBackoff
+		if !c.track(conn) {
+			return
+		}
+		c.wg.Add(1)
+		go c.receive(conn)
+	}
+}
+
+// receive authenticates the peer which opened conn, and delivers its messages.
+// The connection is closed if the peer sends a message on behalf of another party,
+// or a message which cannot be decoded.
+func (c *TCP) receive(conn net.Conn) {
+	defer c.wg.Done()
+	defer c.release(conn)
+
+	tlsConn := tls.Server(conn, c.serverTLS)
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	if err := tlsConn.Handshake(); err != nil {
+		return
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	from, ok := c.identify(tlsConn.ConnectionState())
+	if !ok {
+		return
+	}
+
+	dec := messages.NewDecoder(tlsConn, c.maxSize)
+	for {
+		msg, err := dec.Decode()
+		if err != nil {
+			return
+		}
+		if msg.From != from {
+			return
+		}
+		select {
+		case c.incoming <- msg:
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+// identify returns the party whose Name the verified client certificate is valid for.
+// A certificate which is valid for the names of several parties, such as a wildcard certificate,
+// does not identify any of them, and is rejected.
+func (c *TCP) identify(cs tls.ConnectionState) (party.ID, bool) {
+	if len(cs.VerifiedChains) == 0 || len(cs.VerifiedChains[0]) == 0 {
+		return 0, false
+	}
+	cert := cs.VerifiedChains[0][0]
+	var from party.ID
+	matches := 0
+	for id, peer := range c.peers {
+		if cert.VerifyHostname(peer.Name) == nil {
+			from = id
+			matches++
+		}
+	}
+	if matches != 1 {
+		return 0, false
+	}
+	return from, true
+}
+
+//
+// Sending
+//
+
+// send writes the frames in queue to the peer id, and reconnects whenever a write fails.
+func (c *TCP) send(id party.ID, queue <-chan []byte) {
+	defer c.wg.Done()
+
+	var conn net.Conn
+	defer func() {
+		if conn != nil {
+			c.release(conn)
+		}
+	}()
+
+	for {
+		var frame []byte
+		select {
+		case frame = <-queue:
+		case <-c.closed:
+			return
+		}
+
+		for {
+			if conn == nil {
+				if conn = c.dial(id); conn == nil {
+					return
+				}
+			}
+			if _, err := conn.Write(frame); err == nil {
+				break
+			}
+			c.release(conn)
+			conn = nil
+		}
+	}
+}
+
+// dial connects to the peer id, retrying with an exponential backoff.
+// It returns nil if Done is called before a connection could be established.
+func (c *TCP) dial(id party.ID) net.Conn {
+	backoff := c.minBackoff
+	for {
+		conn, err := c.dialOnce(id)
+		if err == nil {
+			if !c.track(conn) {
+				return nil
+			}
+			return conn
+		}
+		if !sleep(backoff, c.closed) {
+			return nil
+		}
+		backoff = nextBackoff(backoff, c.maxBackoff)
+	}
+}
+
+func (c *TCP) dialOnce(id party.ID) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: handshakeTimeout}
+	conn, err := dialer.Dial("tcp", c.peers[id].Address)
+	if err != nil {
+		return nil, err
+	}
+	tlsConn := tls.Client(conn, c.clientTLS[id])
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	if err = tlsConn.Handshake(); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return tlsConn, nil
+}
+
+// sleep waits for d, and returns false if closed was closed in the meantime.
+func sleep(d time.Duration, closed <-chan struct{}) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-closed:
+		return false
+	}
+}
+
+// nextBackoff doubles backoff, up to max.
+func nextBackoff(backoff, max time.Duration) time.Duration {
+	backoff *= 2
+	if backoff > max {
+		return max
+	}
+	return backoff
+}
@@ -0,0 +1,281 @@
+package transport
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+// config returns a TLS configuration with a certificate for names, issued by ca.
+func (ca *testCA) config(t *testing.T, names ...string) *tls.Config {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: names[0]},
+		DNSNames:     names,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		RootCAs:      ca.pool,
+		ClientCAs:    ca.pool,
+	}
+}
+
+func peerName(id party.ID) string {
+	return fmt.Sprintf("party-%d.frost.test", id)
+}
+
+// newTestCommunicators returns communicators for all partyIDs, connected to each other.
+func newTestCommunicators(t *testing.T, partyIDs party.IDSlice) map[party.ID]*TCP {
+	ca := newTestCA(t)
+	listeners := map[party.ID]net.Listener{}
+	peers := map[party.ID]Peer{}
+	for _, id := range partyIDs {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		listeners[id] = l
+		peers[id] = Peer{Address: l.Addr().String(), Name: peerName(id)}
+	}
+
+	comms := map[party.ID]*TCP{}
+	for _, id := range partyIDs {
+		c, err := NewTCPCommunicator(Config{
+			ID:       id,
+			Peers:    peers,
+			TLS:      ca.config(t, peerName(id)),
+			Listener: listeners[id],
+			Timeout:  10 * time.Second,
+		})
+		require.NoError(t, err)
+		comms[id] = c
+	}
+	t.Cleanup(func() {
+		for _, c := range comms {
+			c.Done()
+		}
+	})
+	return comms
+}
+
+func TestTCP_Keygen(t *testing.T) {
+	partyIDs := helpers.GenerateSet(4)
+	comms := newTestCommunicators(t, partyIDs)
+
+	outputs := map[party.ID]*keygen.Output{}
+	errs := make(chan error, len(partyIDs))
+	for _, id := range partyIDs {
+		var s *state.State
+		var err error
+		s, outputs[id], err = frost.NewKeygenState(context.Background(), id, partyIDs, 2, comms[id].Timeout())
+		require.NoError(t, err)
+		go func(s *state.State, comm Communicator) {
+			errs <- Run(s, comm)
+		}(s, comms[id])
+	}
+	for range partyIDs {
+		require.NoError(t, <-errs)
+	}
+
+	groupKey := outputs[partyIDs[0]].Public.GroupKey
+	for _, id := range partyIDs {
+		assert.True(t, groupKey.Equal(outputs[id].Public.GroupKey))
+	}
+}
+
+func TestTCP_Reconnect(t *testing.T) {
+	partyIDs := helpers.GenerateSet(2)
+	sender, receiver := partyIDs[0], partyIDs[1]
+	ca := newTestCA(t)
+
+	// Reserve an address for the receiver, which does not listen yet.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	peers := map[party.ID]Peer{
+		sender:   {Address: "127.0.0.1:0", Name: peerName(sender)},
+		receiver: {Address: address, Name: peerName(receiver)},
+	}
+	c1, err := NewTCPCommunicator(Config{ID: sender, Peers: peers, TLS: ca.config(t, peerName(sender))})
+	require.NoError(t, err)
+	defer c1.Done()
+
+	msg := keygenMessage(t, sender, partyIDs)
+	require.NoError(t, c1.Send(msg))
+
+	time.Sleep(3 * DefaultMinBackoff)
+	c2, err := NewTCPCommunicator(Config{ID: receiver, Peers: peers, TLS: ca.config(t, peerName(receiver))})
+	require.NoError(t, err)
+	defer c2.Done()
+
+	select {
+	case received := <-c2.Incoming():
+		assert.Equal(t, sender, received.From)
+		assert.Equal(t, encode(t, msg), encode(t, received))
+	case <-time.After(10 * time.Second):
+		t.Fatal("message was not delivered after reconnecting")
+	}
+}
+
+func TestTCP_Authentication(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	comms := newTestCommunicators(t, partyIDs)
+	a, b, c := partyIDs[0], partyIDs[1], partyIDs[2]
+
+	// a cannot send a message on behalf of c.
+	forged := keygenMessage(t, c, partyIDs)
+	require.NoError(t, comms[a].Send(forged))
+	// a party with a certificate from another CA cannot connect.
+	impostor, err := NewTCPCommunicator(Config{
+		ID:       a,
+		Peers:    map[party.ID]Peer{b: {Address: comms[b].Addr().String(), Name: peerName(b)}},
+		TLS:      newTestCA(t).config(t, peerName(a)),
+		Listener: newListener(t),
+	})
+	require.NoError(t, err)
+	defer impostor.Done()
+	require.NoError(t, impostor.Send(keygenMessage(t, a, partyIDs)))
+
+	select {
+	case msg := <-comms[b].Incoming():
+		t.Fatalf("unauthenticated message from %d was delivered", msg.From)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	// c can still send its own message.
+	msg := keygenMessage(t, c, partyIDs)
+	require.NoError(t, comms[c].Send(msg))
+	select {
+	case received := <-comms[b].Incoming():
+		assert.Equal(t, encode(t, msg), encode(t, received))
+	case <-time.After(10 * time.Second):
+		t.Fatal("message was not delivered")
+	}
+}
+
+func TestTCP_AmbiguousCertificate(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	a, b, c := partyIDs[0], partyIDs[1], partyIDs[2]
+	ca := newTestCA(t)
+
+	l := newListener(t)
+	peers := map[party.ID]Peer{
+		a: {Address: "127.0.0.1:0", Name: peerName(a)},
+		b: {Address: l.Addr().String(), Name: peerName(b)},
+		c: {Address: "127.0.0.1:0", Name: peerName(c)},
+	}
+	receiver, err := NewTCPCommunicator(Config{ID: b, Peers: peers, TLS: ca.config(t, peerName(b)), Listener: l})
+	require.NoError(t, err)
+	defer receiver.Done()
+
+	// a's certificate is also valid for c, so a cannot be told apart from c.
+	ambiguous, err := NewTCPCommunicator(Config{ID: a, Peers: peers, TLS: ca.config(t, peerName(a), peerName(c)), Listener: newListener(t)})
+	require.NoError(t, err)
+	defer ambiguous.Done()
+	require.NoError(t, ambiguous.Send(keygenMessage(t, a, partyIDs)))
+	require.NoError(t, ambiguous.Send(keygenMessage(t, c, partyIDs)))
+
+	select {
+	case msg := <-receiver.Incoming():
+		t.Fatalf("message from %d with an ambiguous certificate was delivered", msg.From)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	// A certificate for a single party is accepted.
+	sender, err := NewTCPCommunicator(Config{ID: c, Peers: peers, TLS: ca.config(t, peerName(c)), Listener: newListener(t)})
+	require.NoError(t, err)
+	defer sender.Done()
+	msg := keygenMessage(t, c, partyIDs)
+	require.NoError(t, sender.Send(msg))
+	select {
+	case received := <-receiver.Incoming():
+		assert.Equal(t, encode(t, msg), encode(t, received))
+	case <-time.After(10 * time.Second):
+		t.Fatal("message was not delivered")
+	}
+}
+
+func TestTCP_Done(t *testing.T) {
+	partyIDs := helpers.GenerateSet(2)
+	comms := newTestCommunicators(t, partyIDs)
+	c := comms[partyIDs[0]]
+	c.Done()
+
+	_, ok := <-c.Incoming()
+	assert.False(t, ok)
+	assert.Equal(t, ErrClosed, c.Send(keygenMessage(t, partyIDs[0], partyIDs)))
+}
+
+// keygenMessage returns the first message of a keygen execution by id.
+func keygenMessage(t *testing.T, id party.ID, partyIDs party.IDSlice) *messages.Message {
+	s, _, err := frost.NewKeygenState(context.Background(), id, partyIDs, 1, 0)
+	require.NoError(t, err)
+	out := s.ProcessAll()
+	require.Len(t, out, 1)
+	return out[0]
+}
+
+func newListener(t *testing.T) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	return l
+}
+
+func encode(t *testing.T, msg *messages.Message) []byte {
+	data, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	return data
+}
@@ -0,0 +1,91 @@
+// Package transport delivers protocol messages between parties.
+//
+// A Communicator sends the messages produced by a state.State to the other parties,
+// and delivers the messages it receives from them.
+// Run connects both, and executes a protocol until it finishes:
+//
+//	comm, err := transport.NewTCPCommunicator(config)
+//	s, output, err := frost.NewKeygenState(ctx, config.ID, partyIDs, threshold, comm.Timeout())
+//	err = transport.Run(s, comm)
+//	comm.Done()
+//
+// TCP connects all parties directly, and requires them to be online at the same time.
+// Mailbox exchanges messages through a Relay or a shared directory, so that parties can come and go during the protocol.
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// ErrClosed is returned by Communicator.Send after Communicator.Done was called.
+var ErrClosed = errors.New("transport: communicator is closed")
+
+// Communicator routes messages between the parties of a protocol.
+type Communicator interface {
+	// Send delivers msg to its receiver, or to all other parties if msg is a broadcast.
+	Send(msg *messages.Message) error
+
+	// Incoming returns the channel on which received messages are delivered.
+	Incoming() <-chan *messages.Message
+
+	// Done releases all resources held by the Communicator.
+	Done()
+
+	// Timeout is the timeout that should be given to the State using this Communicator.
+	Timeout() time.Duration
+}
+
+// Run executes the protocol managed by s until it finishes, by sending the messages it produces with comm,
+// and handing it the messages received by comm.
+// It returns the error of the protocol, which is nil if it finished successfully.
+//
+// Messages rejected by s are ignored, since they do not necessarily require an abort.
+// If a message cannot be sent, Run returns the error immediately, without waiting for the protocol to finish.
+// If comm stops delivering messages, Run only returns when s times out or its context is done.
+func Run(s *state.State, comm Communicator) error {
+	if err := ProcessAll(s, comm); err != nil {
+		return err
+	}
+
+	incoming := comm.Incoming()
+	for {
+		select {
+		case msg, ok := <-incoming:
+			if !ok {
+				incoming = nil
+				continue
+			}
+			if msg == nil {
+				continue
+			}
+			_ = s.HandleMessage(msg)
+			if err := ProcessAll(s, comm); err != nil {
+				return err
+			}
+		case <-s.Done():
+			return s.Err()
+		}
+	}
+}
+
+// ProcessAll advances s as far as possible, and sends the resulting messages with comm.
+// Several rounds may be processed, since messages for the next round may have been received in advance.
+// It is called by Run after every received message, and can be used to drive a State without Run.
+func ProcessAll(s *state.State, comm Communicator) error {
+	for {
+		roundNumber := s.RoundNumber()
+		for _, msg := range s.ProcessAll() {
+			if err := comm.Send(msg); err != nil {
+				return fmt.Errorf("transport.ProcessAll: %w", err)
+			}
+		}
+		if s.IsFinished() || s.RoundNumber() == roundNumber {
+			return nil
+		}
+	}
+}
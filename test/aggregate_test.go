@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func unmarshalMessages(t *testing.T, in [][]byte) []*messages.Message {
+	msgs := make([]*messages.Message, 0, len(in))
+	for _, data := range in {
+		var msg messages.Message
+		require.NoError(t, msg.UnmarshalBinary(data))
+		msgs = append(msgs, &msg)
+	}
+	return msgs
+}
+
+func TestAggregate(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+
+	_, signSet, secretShares, publicShares := setupParties(T, N)
+
+	states := map[party.ID]*state.State{}
+	outputs := map[party.ID]*sign.Output{}
+	for _, id := range signSet {
+		var err error
+		states[id], outputs[id], err = frost.NewSignState(context.Background(), signSet, secretShares[id], publicShares, MESSAGE, 0)
+		require.NoError(t, err)
+	}
+
+	var msgsOut1, msgsOut2 [][]byte
+	for _, s := range states {
+		msgs1, err := helpers.PartyRoutine(nil, s)
+		require.NoError(t, err)
+		msgsOut1 = append(msgsOut1, msgs1...)
+	}
+	for _, s := range states {
+		msgs2, err := helpers.PartyRoutine(msgsOut1, s)
+		require.NoError(t, err)
+		msgsOut2 = append(msgsOut2, msgs2...)
+	}
+	for _, s := range states {
+		_, err := helpers.PartyRoutine(msgsOut2, s)
+		require.NoError(t, err)
+	}
+
+	commitments := unmarshalMessages(t, msgsOut1)
+	shares := unmarshalMessages(t, msgsOut2)
+
+	sig, err := sign.Aggregate(signSet, publicShares, MESSAGE, commitments, shares)
+	require.NoError(t, err)
+	assert.True(t, ed25519.Verify(publicShares.GroupKey.ToEd25519(), MESSAGE, sig.ToEd25519()))
+	assert.Equal(t, outputs[signSet[0]].Signature.ToEd25519(), sig.ToEd25519(), "the signature should be the same as computed by the signers")
+
+	_, err = sign.Aggregate(signSet, publicShares, []byte("other message"), commitments, shares)
+	assert.Error(t, err, "shares for a different message should be rejected")
+
+	_, err = sign.Aggregate(signSet, publicShares, MESSAGE, commitments, shares[1:])
+	assert.Error(t, err, "a missing share should be rejected")
+
+	_, err = sign.Aggregate(signSet, publicShares, MESSAGE, commitments, append(shares, shares[0]))
+	assert.Error(t, err, "a duplicate share should be rejected")
+
+	_, err = sign.Aggregate(signSet, publicShares, MESSAGE, shares, commitments)
+	assert.Error(t, err, "messages of the wrong type should be rejected")
+
+	// One signer sends the share of another signer
+	culprit := shares[0].From
+	shares[0] = messages.NewSign2(culprit, &shares[1].Payload.(*messages.Sign2).Zi)
+	_, err = sign.Aggregate(signSet, publicShares, MESSAGE, commitments, shares)
+	var stateErr *state.Error
+	require.True(t, errors.As(err, &stateErr))
+	assert.Equal(t, culprit, stateErr.PartyID, "the party which sent the invalid share should be blamed")
+	assert.True(t, errors.Is(err, sign.ErrValidateSigShare))
+}
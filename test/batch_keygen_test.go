@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func TestBatchKeygen(t *testing.T) {
+	N := party.Size(10)
+	T := N / 2
+	K := 8
+
+	partyIDs := helpers.GenerateSet(N)
+
+	states := map[party.ID]*state.State{}
+	outputs := map[party.ID][]*keygen.Output{}
+
+	for _, id := range partyIDs {
+		var err error
+		states[id], outputs[id], err = frost.NewBatchKeygenState(context.Background(), id, partyIDs, T, K, 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	msgsOut1 := make([][]byte, 0, N)
+	msgsOut2 := make([][]byte, 0, N*(N-1)/2)
+
+	for _, s := range states {
+		msgs1, err := helpers.PartyRoutine(nil, s)
+		if err != nil {
+			t.Error(err)
+		}
+		msgsOut1 = append(msgsOut1, msgs1...)
+	}
+
+	for _, s := range states {
+		msgs2, err := helpers.PartyRoutine(msgsOut1, s)
+		if err != nil {
+			t.Error(err)
+		}
+		msgsOut2 = append(msgsOut2, msgs2...)
+	}
+
+	// The number of messages does not depend on the number of keys generated
+	if len(msgsOut1) != int(N) || len(msgsOut2) != int(N*(N-1)) {
+		t.Errorf("unexpected number of messages: %d, %d", len(msgsOut1), len(msgsOut2))
+	}
+
+	for _, s := range states {
+		_, err := helpers.PartyRoutine(msgsOut2, s)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	for _, id := range partyIDs {
+		if err := states[id].WaitForError(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	id1 := partyIDs[0]
+	for k := 0; k < K; k++ {
+		groupKey1 := outputs[id1][k].Public.GroupKey
+		publicShares1 := outputs[id1][k].Public
+		secrets := map[party.ID]*eddsa.SecretShare{}
+		for _, id2 := range partyIDs {
+			groupKey2 := outputs[id2][k].Public.GroupKey
+			publicShares2 := outputs[id2][k].Public
+			secrets[id2] = outputs[id2][k].SecretKey
+			if err := CompareOutput(groupKey1, groupKey2, publicShares1, publicShares2); err != nil {
+				t.Error(err)
+			}
+		}
+
+		if err := ValidateSecrets(secrets, groupKey1, publicShares1); err != nil {
+			t.Error(err)
+		}
+
+		// All keys should be independent
+		for l := 0; l < k; l++ {
+			if groupKey1.Equal(outputs[id1][l].Public.GroupKey) {
+				t.Errorf("keys %d and %d are the same", k, l)
+			}
+		}
+	}
+}
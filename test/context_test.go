@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func TestSign_Cancel(t *testing.T) {
+	N := party.Size(5)
+	T := N - 1
+
+	_, signSet, secretShares, publicShares := setupParties(T, N)
+	id := signSet[0]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s, output, err := frost.NewSignState(ctx, signSet, secretShares[id], publicShares, MESSAGE, 0)
+	require.NoError(t, err)
+
+	_, err = helpers.PartyRoutine(nil, s)
+	require.NoError(t, err)
+
+	cancel()
+
+	select {
+	case <-s.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Done() was not closed after cancellation")
+	}
+	err = s.WaitForError()
+	assert.True(t, errors.Is(err, context.Canceled), "error should wrap context.Canceled, got %v", err)
+
+	var stateErr *state.Error
+	require.True(t, errors.As(err, &stateErr))
+	assert.Equal(t, party.ID(0), stateErr.PartyID)
+	assert.Equal(t, 1, stateErr.RoundNumber)
+	assert.Nil(t, output.Signature)
+}
+
+func TestKeygen_Deadline(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	s, _, err := frost.NewKeygenState(ctx, partyIDs[0], partyIDs, 1, 0)
+	require.NoError(t, err)
+
+	err = s.WaitForError()
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "error should wrap context.DeadlineExceeded, got %v", err)
+	assert.True(t, s.IsFinished())
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// The sum protocol is a minimal protocol defined outside of pkg/messages,
+// where every party broadcasts a number, and the output is the sum of all numbers.
+
+const messageTypeSum = messages.MessageTypeFirstCustom
+
+type sumPayload struct {
+	Value uint64
+}
+
+func init() {
+	messages.Register(messageTypeSum, messages.TypeInfo{
+		Name:       "sum",
+		Broadcast:  true,
+		NewPayload: func() messages.FROSTMarshaler { return new(sumPayload) },
+	})
+}
+
+func (m *sumPayload) BytesAppend(existing []byte) ([]byte, error) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], m.Value)
+	return append(existing, buf[:]...), nil
+}
+
+func (m *sumPayload) MarshalBinary() ([]byte, error) {
+	return m.BytesAppend(nil)
+}
+
+func (m *sumPayload) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return messages.ErrInvalidMessage
+	}
+	m.Value = binary.BigEndian.Uint64(data)
+	return nil
+}
+
+func (m *sumPayload) Size() int {
+	return 8
+}
+
+func (m *sumPayload) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*sumPayload)
+	return ok && m.Value == otherMsg.Value
+}
+
+type sumRound0 struct {
+	*state.BaseRound
+	Value uint64
+	Sum   *uint64
+}
+
+type sumRound1 struct {
+	*sumRound0
+}
+
+func (r *sumRound0) Reset() {}
+
+func (r *sumRound0) AcceptedMessageTypes() []messages.MessageType {
+	return []messages.MessageType{messages.MessageTypeNone, messageTypeSum}
+}
+
+func (r *sumRound0) GenerateMessages() ([]*messages.Message, *state.Error) {
+	*r.Sum = r.Value
+	return []*messages.Message{{
+		Header:  messages.Header{Type: messageTypeSum, From: r.SelfID()},
+		Payload: &sumPayload{Value: r.Value},
+	}}, nil
+}
+
+func (r *sumRound0) NextRound() state.Round {
+	return &sumRound1{r}
+}
+
+func (r *sumRound1) ProcessMessage(msg *messages.Message) *state.Error {
+	body, ok := msg.Payload.(*sumPayload)
+	if !ok {
+		return state.NewError(msg.From, errors.New("invalid message payload"))
+	}
+	*r.Sum += body.Value
+	return nil
+}
+
+func (r *sumRound1) GenerateMessages() ([]*messages.Message, *state.Error) {
+	return nil, nil
+}
+
+func (r *sumRound1) NextRound() state.Round {
+	return nil
+}
+
+func TestCustomProtocol(t *testing.T) {
+	partyIDs := helpers.GenerateSet(4)
+
+	states := map[party.ID]*state.State{}
+	sums := map[party.ID]*uint64{}
+	for _, id := range partyIDs {
+		base, err := state.NewBaseRound(id, partyIDs)
+		require.NoError(t, err)
+		sums[id] = new(uint64)
+		states[id], err = state.NewBaseState(context.Background(), &sumRound0{
+			BaseRound: base,
+			Value:     uint64(id),
+			Sum:       sums[id],
+		}, 0)
+		require.NoError(t, err)
+	}
+
+	var msgs [][]byte
+	for _, s := range states {
+		out, err := helpers.PartyRoutine(nil, s)
+		require.NoError(t, err)
+		msgs = append(msgs, out...)
+	}
+	for _, s := range states {
+		_, err := helpers.PartyRoutine(msgs, s)
+		require.NoError(t, err)
+	}
+
+	var expected uint64
+	for _, id := range partyIDs {
+		expected += uint64(id)
+	}
+	for id, s := range states {
+		require.NoError(t, s.WaitForError())
+		assert.Equal(t, expected, *sums[id])
+	}
+}
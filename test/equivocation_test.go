@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func TestRetransmission(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+
+	states := map[party.ID]*state.State{}
+	for _, id := range partyIDs {
+		var err error
+		states[id], _, err = frost.NewKeygenState(context.Background(), id, partyIDs, 1, 0)
+		require.NoError(t, err)
+	}
+
+	var msgs1, msgs2 [][]byte
+	for _, s := range states {
+		out, err := helpers.PartyRoutine(nil, s)
+		require.NoError(t, err)
+		msgs1 = append(msgs1, out...)
+	}
+
+	// Every message is delivered twice.
+	for _, s := range states {
+		out, err := helpers.PartyRoutine(append(msgs1, msgs1...), s)
+		require.NoError(t, err)
+		msgs2 = append(msgs2, out...)
+	}
+
+	// Messages from the previous round are delivered again.
+	for _, s := range states {
+		_, err := helpers.PartyRoutine(append(append(msgs1, msgs2...), msgs2...), s)
+		require.NoError(t, err)
+		require.NoError(t, s.WaitForError())
+	}
+}
+
+func TestEquivocation(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	culprit, victim := partyIDs[0], partyIDs[1]
+
+	honest, _, err := frost.NewKeygenState(context.Background(), culprit, partyIDs, 1, 0)
+	require.NoError(t, err)
+	dishonest, _, err := frost.NewKeygenState(context.Background(), culprit, partyIDs, 1, 0)
+	require.NoError(t, err)
+	s, _, err := frost.NewKeygenState(context.Background(), victim, partyIDs, 1, 0)
+	require.NoError(t, err)
+
+	first := honest.ProcessAll()[0]
+	second := dishonest.ProcessAll()[0]
+	require.NotNil(t, s.ProcessAll())
+
+	require.NoError(t, s.HandleMessage(first))
+	err = s.HandleMessage(second)
+	require.Error(t, err)
+	assert.True(t, s.IsFinished())
+	assert.Equal(t, err, s.Err())
+
+	var stateErr *state.Error
+	require.True(t, errors.As(err, &stateErr))
+	assert.Equal(t, culprit, stateErr.PartyID)
+
+	var equivocation *state.EquivocationError
+	require.True(t, errors.As(err, &equivocation))
+	assert.Equal(t, culprit, equivocation.PartyID)
+	assert.Equal(t, encode(t, first), encode(t, equivocation.First))
+	assert.Equal(t, encode(t, second), encode(t, equivocation.Second))
+}
+
+func encode(t *testing.T, msg *messages.Message) []byte {
+	data, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	return data
+}
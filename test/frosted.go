@@ -34,7 +34,7 @@ func DoKeygen(N, T party.Size, keygenIDs []party.ID, keygenComm map[party.ID]com
 	var public *eddsa.Public
 	secrets := map[party.ID]*eddsa.SecretShare{}
 	for id, h := range keygenHandlers {
-		if err = h.State.WaitForError(); err != nil {
+		if err = h.Err(); err != nil {
 			return nil, nil, err
 		}
 		public = h.Out.Public
@@ -57,7 +57,7 @@ func DoSign(T party.Size, signIDs []party.ID, shares *eddsa.Public, secrets map[
 	failures := 0
 
 	for _, h := range signHandlers {
-		err = h.State.WaitForError()
+		err = h.Err()
 		if err != nil {
 			failures++
 		} else if s := h.Out.Signature; s != nil {
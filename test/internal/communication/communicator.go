@@ -1,15 +1,7 @@
 package communication
 
 import (
-	"time"
-
-	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/transport"
 )
 
-type Communicator interface {
-	Send(msg *messages.Message) error
-
-	Incoming() <-chan *messages.Message
-	Done()
-	Timeout() time.Duration
-}
+type Communicator = transport.Communicator
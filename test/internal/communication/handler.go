@@ -1,7 +1,7 @@
 package communication
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost"
@@ -9,6 +9,7 @@ import (
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
 	"github.com/taurusgroup/frost-ed25519/pkg/state"
+	"github.com/taurusgroup/frost-ed25519/pkg/transport"
 )
 
 // Handler holds the information for a certain Round by a participant.
@@ -16,6 +17,9 @@ import (
 type Handler struct {
 	State *state.State
 	Comm  Communicator
+
+	done chan struct{}
+	err  error
 }
 
 type (
@@ -30,50 +34,29 @@ type (
 	}
 )
 
-// HandleMessage is a blocking function that exits
+// HandleMessage is a blocking function that exits when the protocol is done.
+// Its error is returned by Err.
 func (h *Handler) HandleMessage() {
-	h.ProcessAll()
-
-	for {
-		select {
-		case msg := <-h.Comm.Incoming():
-			if msg == nil {
-				continue
-			}
-			if err := h.State.HandleMessage(msg); err != nil {
-				//fmt.Println("handle message", err)
-			}
-			h.ProcessAll()
-		case <-h.State.Done():
-			err := h.State.Err()
-			if err != nil {
-				//fmt.Println("done with err: ", err)
-			}
-			return
-		}
-	}
+	h.err = transport.Run(h.State, h.Comm)
+	close(h.done)
 }
 
-func (h *Handler) ProcessAll() {
-	msgsOut := h.State.ProcessAll()
-
-	for _, msg := range msgsOut {
-		err := h.Comm.Send(msg)
-		if err != nil {
-			fmt.Println("process all", err)
-		}
-	}
+// Err waits for HandleMessage to return, and returns the error of the protocol.
+func (h *Handler) Err() error {
+	<-h.done
+	return h.err
 }
 
 func NewKeyGenHandler(comm Communicator, ID party.ID, IDs []party.ID, T party.Size) (*KeyGenHandler, error) {
 	set := party.NewIDSlice(IDs)
-	s, out, err := frost.NewKeygenState(ID, set, T, comm.Timeout())
+	s, out, err := frost.NewKeygenState(context.Background(), ID, set, T, comm.Timeout())
 	if err != nil {
 		return nil, err
 	}
 	h := &Handler{
 		State: s,
 		Comm:  comm,
+		done:  make(chan struct{}),
 	}
 	go h.HandleMessage()
 	return &KeyGenHandler{
@@ -84,13 +67,14 @@ func NewKeyGenHandler(comm Communicator, ID party.ID, IDs []party.ID, T party.Si
 
 func NewSignHandler(comm Communicator, IDs []party.ID, secret *eddsa.SecretShare, public *eddsa.Public, message []byte) (*SignHandler, error) {
 	set := party.NewIDSlice(IDs)
-	s, out, err := frost.NewSignState(set, secret, public, message, comm.Timeout())
+	s, out, err := frost.NewSignState(context.Background(), set, secret, public, message, comm.Timeout())
 	if err != nil {
 		return nil, err
 	}
 	h := &Handler{
 		State: s,
 		Comm:  comm,
+		done:  make(chan struct{}),
 	}
 	go h.HandleMessage()
 	return &SignHandler{
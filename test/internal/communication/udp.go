@@ -12,6 +12,9 @@ import (
 	"github.com/taurusgroup/frost-ed25519/pkg/messages"
 )
 
+// maxDatagramSize is the largest payload of a UDP datagram over IPv4.
+const maxDatagramSize = 65507
+
 type UDP struct {
 	peers    map[party.ID]*net.UDPAddr
 	incoming chan *messages.Message
@@ -48,7 +51,8 @@ func (c *UDP) Start() {
 			}
 		}()
 
-		initialBuffer := make([]byte, (len(c.peers)+2)*64)
+		// Each datagram contains exactly one message, so the buffer only needs to fit the largest datagram.
+		initialBuffer := make([]byte, maxDatagramSize)
 
 		for {
 			n, _, err := c.conn.ReadFromUDP(initialBuffer)
@@ -63,6 +67,7 @@ func (c *UDP) Start() {
 			err = msg.UnmarshalBinary(initialBuffer[:n])
 			if err != nil {
 				fmt.Println("read error:", err)
+				continue
 			}
 			c.incoming <- &msg
 		}
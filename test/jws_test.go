@@ -0,0 +1,47 @@
+package main
+
+import (
+	"crypto"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/jws"
+)
+
+func TestJWS_Threshold(t *testing.T) {
+	_, signIDs, secretShares, publicShares := setupParties(2, 5)
+	now := time.Now()
+	claims := jws.Claims{
+		Issuer:   "https://id.example.com",
+		Subject:  "alice",
+		Audience: jws.Audience{"api"},
+		Expiry:   now.Add(time.Hour).Unix(),
+		IssuedAt: now.Unix(),
+	}
+
+	tokens := signWithSigners(t, signIDs, secretShares, publicShares, func(cs crypto.Signer) ([]byte, error) {
+		token, err := jws.SignClaims(cs, claims)
+		return []byte(token), err
+	})
+
+	// Relying parties fetch the JWKS of the issuer
+	data, err := json.Marshal(jws.NewJWKS(publicShares.GroupKey))
+	require.NoError(t, err)
+	var set jws.JWKS
+	require.NoError(t, json.Unmarshal(data, &set))
+
+	token := string(tokens[signIDs[0]])
+	for id, other := range tokens {
+		assert.Equal(t, token, string(other), "party %d", id)
+	}
+	header, payload, err := set.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, jws.KeyID(publicShares.GroupKey), header.KeyID)
+	var decoded jws.Claims
+	require.NoError(t, json.Unmarshal(payload, &decoded))
+	assert.Equal(t, claims, decoded)
+	assert.NoError(t, decoded.Validate(now, time.Minute, "api"))
+}
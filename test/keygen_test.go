@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -24,7 +25,7 @@ func TestKeygen(t *testing.T) {
 
 	for _, id := range partyIDs {
 		var err error
-		states[id], outputs[id], err = frost.NewKeygenState(id, partyIDs, T, 0)
+		states[id], outputs[id], err = frost.NewKeygenState(context.Background(), id, partyIDs, T, 0)
 		if err != nil {
 			t.Error(err)
 			return
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/metrics"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+type recordingObserver struct {
+	mtx      sync.Mutex
+	accepted int
+	rejected []error
+	rounds   []int
+	timeouts []int
+	finished []error
+}
+
+func (r *recordingObserver) MessageAccepted(int, *messages.Message) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.accepted++
+}
+
+func (r *recordingObserver) MessageRejected(_ int, _ *messages.Message, reason error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.rejected = append(r.rejected, reason)
+}
+
+func (r *recordingObserver) RoundCompleted(round int, _ time.Duration) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.rounds = append(r.rounds, round)
+}
+
+func (r *recordingObserver) TimedOut(round int) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.timeouts = append(r.timeouts, round)
+}
+
+func (r *recordingObserver) Finished(err error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.finished = append(r.finished, err)
+}
+
+func TestObserver_Sign(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+
+	partyIDs, signSet, secretShares, publicShares := setupParties(T, N)
+
+	collector := metrics.NewCollector()
+	recorders := map[party.ID]*recordingObserver{}
+	states := map[party.ID]*state.State{}
+	for _, id := range signSet {
+		recorders[id] = &recordingObserver{}
+		var err error
+		states[id], _, err = frost.NewSignState(context.Background(), signSet, secretShares[id], publicShares, MESSAGE, 0,
+			state.WithObserver(multiObserver{recorders[id], collector.Observer("sign")}))
+		require.NoError(t, err)
+	}
+
+	var msgs1, msgs2 [][]byte
+	for _, s := range states {
+		out, err := helpers.PartyRoutine(nil, s)
+		require.NoError(t, err)
+		msgs1 = append(msgs1, out...)
+	}
+
+	// A duplicate message is ignored, and a message from a party outside the sign set
+	// is rejected without aborting the protocol.
+	var duplicate messages.Message
+	require.NoError(t, duplicate.UnmarshalBinary(msgs1[0]))
+	receiver := signSet[0]
+	if duplicate.From == receiver {
+		receiver = signSet[1]
+	}
+	require.NoError(t, states[receiver].HandleMessage(&duplicate))
+	require.NoError(t, states[receiver].HandleMessage(&duplicate))
+	outsider := duplicate
+	outsider.From = partyIDs[N-1]
+	require.Error(t, states[receiver].HandleMessage(&outsider))
+
+	for id, s := range states {
+		var out [][]byte
+		var err error
+		if id == receiver {
+			out, err = helpers.PartyRoutine(msgs1[1:], s)
+		} else {
+			out, err = helpers.PartyRoutine(msgs1, s)
+		}
+		require.NoError(t, err)
+		msgs2 = append(msgs2, out...)
+	}
+	for _, s := range states {
+		_, err := helpers.PartyRoutine(msgs2, s)
+		require.NoError(t, err)
+		require.NoError(t, s.WaitForError())
+	}
+
+	for id, r := range recorders {
+		r.mtx.Lock()
+		assert.Equal(t, []int{0, 1, 2}, r.rounds, "party %d", id)
+		assert.Equal(t, 2*int(T), r.accepted, "party %d", id)
+		assert.Empty(t, r.timeouts)
+		assert.Equal(t, []error{nil}, r.finished)
+		if id == receiver {
+			assert.Len(t, r.rejected, 1)
+		} else {
+			assert.Empty(t, r.rejected)
+		}
+		r.mtx.Unlock()
+	}
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4"))
+	assert.Contains(t, body, `frost_messages_accepted_total{protocol="sign",type="sign1"} 6`)
+	assert.Contains(t, body, `frost_messages_accepted_total{protocol="sign",type="sign2"} 6`)
+	assert.Contains(t, body, `frost_messages_rejected_total{protocol="sign",type="sign1"} 1`)
+	assert.Contains(t, body, `frost_sessions_finished_total{protocol="sign",result="success"} 3`)
+	assert.Contains(t, body, `frost_sessions_active{protocol="sign"} 0`)
+	assert.Contains(t, body, `frost_round_duration_seconds_count{protocol="sign",round="1"} 3`)
+	assert.Contains(t, body, `frost_session_duration_seconds_count{protocol="sign"} 3`)
+}
+
+func TestObserver_Timeout(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+
+	collector := metrics.NewCollector()
+	recorder := &recordingObserver{}
+	s, _, err := frost.NewKeygenState(context.Background(), partyIDs[0], partyIDs, 1, 20*time.Millisecond,
+		state.WithObserver(multiObserver{recorder, collector.Observer("keygen")}))
+	require.NoError(t, err)
+	_, err = helpers.PartyRoutine(nil, s)
+	require.NoError(t, err)
+
+	require.Error(t, s.WaitForError())
+	recorder.mtx.Lock()
+	assert.Equal(t, []int{1}, recorder.timeouts)
+	require.Len(t, recorder.finished, 1)
+	assert.Error(t, recorder.finished[0])
+	recorder.mtx.Unlock()
+
+	var b strings.Builder
+	_, err = collector.WriteTo(&b)
+	require.NoError(t, err)
+	assert.Contains(t, b.String(), `frost_round_timeouts_total{protocol="keygen",round="1"} 1`)
+	assert.Contains(t, b.String(), `frost_sessions_finished_total{protocol="keygen",result="timeout"} 1`)
+}
+
+// multiObserver forwards all events to each of its Observer s.
+type multiObserver []state.Observer
+
+func (m multiObserver) MessageAccepted(round int, msg *messages.Message) {
+	for _, o := range m {
+		o.MessageAccepted(round, msg)
+	}
+}
+
+func (m multiObserver) MessageRejected(round int, msg *messages.Message, reason error) {
+	for _, o := range m {
+		o.MessageRejected(round, msg, reason)
+	}
+}
+
+func (m multiObserver) RoundCompleted(round int, duration time.Duration) {
+	for _, o := range m {
+		o.RoundCompleted(round, duration)
+	}
+}
+
+func (m multiObserver) TimedOut(round int) {
+	for _, o := range m {
+		o.TimedOut(round)
+	}
+}
+
+func (m multiObserver) Finished(err error) {
+	for _, o := range m {
+		o.Finished(err)
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func newSnapshotKey(t *testing.T) []byte {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func TestSign_SuspendResume(t *testing.T) {
+	N := party.Size(5)
+	T := N - 1
+
+	_, signSet, secretShares, publicShares := setupParties(T, N)
+
+	states := map[party.ID]*state.State{}
+	outputs := map[party.ID]*sign.Output{}
+	for _, id := range signSet {
+		var err error
+		states[id], outputs[id], err = frost.NewSignState(context.Background(), signSet, secretShares[id], publicShares, MESSAGE, 0)
+		require.NoError(t, err)
+	}
+
+	msgsOut1 := make([][]byte, 0, N)
+	for _, s := range states {
+		msgs1, err := helpers.PartyRoutine(nil, s)
+		require.NoError(t, err)
+		msgsOut1 = append(msgsOut1, msgs1...)
+	}
+
+	// The party restarts after having sent its nonce commitments
+	restartID := signSet[0]
+	key := newSnapshotKey(t)
+	guard := state.NewMemoryGuard()
+	snapshot, err := states[restartID].Suspend(key)
+	require.NoError(t, err)
+	assert.True(t, errors.Is(states[restartID].WaitForError(), state.ErrSuspended))
+
+	_, _, err = frost.ResumeSignState(context.Background(), snapshot, key, nil, 0)
+	assert.Error(t, err, "resuming a sign snapshot without guard should fail")
+
+	_, _, err = frost.ResumeSignState(context.Background(), snapshot, newSnapshotKey(t), guard, 0)
+	assert.True(t, errors.Is(err, state.ErrSnapshotInvalid), "resuming with the wrong key should fail")
+
+	states[restartID], outputs[restartID], err = frost.ResumeSignState(context.Background(), snapshot, key, guard, 0)
+	require.NoError(t, err)
+
+	_, _, err = frost.ResumeSignState(context.Background(), snapshot, key, guard, 0)
+	assert.True(t, errors.Is(err, state.ErrAlreadyResumed), "a sign snapshot must only be resumed once")
+
+	msgsOut2 := make([][]byte, 0, N)
+	for _, s := range states {
+		msgs2, err := helpers.PartyRoutine(msgsOut1, s)
+		require.NoError(t, err)
+		msgsOut2 = append(msgsOut2, msgs2...)
+	}
+
+	// Suspend again, this time with the messages of the last round already received
+	require.NoError(t, helpers.HandleMessages(msgsOut2, states[restartID]))
+	snapshot, err = states[restartID].Suspend(key)
+	require.NoError(t, err)
+	states[restartID], outputs[restartID], err = frost.ResumeSignState(context.Background(), snapshot, key, guard, 0)
+	require.NoError(t, err)
+
+	for id, s := range states {
+		if id == restartID {
+			_, err = helpers.PartyRoutine(nil, s)
+		} else {
+			_, err = helpers.PartyRoutine(msgsOut2, s)
+		}
+		require.NoError(t, err)
+	}
+
+	pk := publicShares.GroupKey
+	for id, s := range states {
+		require.NoError(t, s.WaitForError())
+		sig := outputs[id].Signature
+		require.NotNil(t, sig)
+		assert.True(t, ed25519.Verify(pk.ToEd25519(), MESSAGE, sig.ToEd25519()))
+	}
+}
+
+func TestKeygen_SuspendResume(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+
+	partyIDs := helpers.GenerateSet(N)
+
+	states := map[party.ID]*state.State{}
+	outputs := map[party.ID]*keygen.Output{}
+	for _, id := range partyIDs {
+		var err error
+		states[id], outputs[id], err = frost.NewKeygenState(context.Background(), id, partyIDs, T, 0)
+		require.NoError(t, err)
+	}
+
+	msgsOut1 := make([][]byte, 0, N)
+	for _, s := range states {
+		msgs1, err := helpers.PartyRoutine(nil, s)
+		require.NoError(t, err)
+		msgsOut1 = append(msgsOut1, msgs1...)
+	}
+
+	// Suspend with the messages of the next round already queued
+	restartID := partyIDs[1]
+	key := newSnapshotKey(t)
+	require.NoError(t, helpers.HandleMessages(msgsOut1, states[restartID]))
+	snapshot, err := states[restartID].Suspend(key)
+	require.NoError(t, err)
+	states[restartID], outputs[restartID], err = frost.ResumeKeygenState(context.Background(), snapshot, key, nil, 0)
+	require.NoError(t, err)
+
+	msgsOut2 := make([][]byte, 0, N*(N-1))
+	for id, s := range states {
+		var msgs2 [][]byte
+		if id == restartID {
+			msgs2, err = helpers.PartyRoutine(nil, s)
+		} else {
+			msgs2, err = helpers.PartyRoutine(msgsOut1, s)
+		}
+		require.NoError(t, err)
+		msgsOut2 = append(msgsOut2, msgs2...)
+	}
+	for _, s := range states {
+		_, err = helpers.PartyRoutine(msgsOut2, s)
+		require.NoError(t, err)
+	}
+
+	secrets := map[party.ID]*eddsa.SecretShare{}
+	public := outputs[partyIDs[0]].Public
+	for id, s := range states {
+		require.NoError(t, s.WaitForError())
+		require.NoError(t, CompareOutput(public.GroupKey, outputs[id].Public.GroupKey, public, outputs[id].Public))
+		secrets[id] = outputs[id].SecretKey
+	}
+	require.NoError(t, ValidateSecrets(secrets, public.GroupKey, public))
+}
+
+func TestKeygen_ResumeRoundTimeout(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	self := partyIDs[0]
+
+	s, _, err := frost.NewKeygenState(context.Background(), self, partyIDs, 1, 0)
+	require.NoError(t, err)
+	_, err = helpers.PartyRoutine(nil, s)
+	require.NoError(t, err)
+
+	key := newSnapshotKey(t)
+	snapshot, err := s.Suspend(key)
+	require.NoError(t, err)
+
+	// The round timer of a resumed State applies to the round it was suspended in.
+	s, _, err = frost.ResumeKeygenState(context.Background(), snapshot, key, nil, 0,
+		state.WithRoundTimeout(100*time.Millisecond))
+	require.NoError(t, err)
+	select {
+	case <-s.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the round timeout did not abort the resumed State")
+	}
+
+	var timeoutErr *state.TimeoutError
+	require.True(t, errors.As(s.WaitForError(), &timeoutErr), "expected a TimeoutError")
+	assert.Equal(t, 1, timeoutErr.RoundNumber)
+	assert.Equal(t, partyIDs[1:], timeoutErr.Missing)
+}
+
+func TestKeygen_ResumeHistory(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	self, other := partyIDs[0], partyIDs[1]
+
+	states := map[party.ID]*state.State{}
+	for _, id := range partyIDs {
+		var err error
+		states[id], _, err = frost.NewKeygenState(context.Background(), id, partyIDs, 1, 0)
+		require.NoError(t, err)
+	}
+	var msgsOut1, msgsOther [][]byte
+	for id, s := range states {
+		msgs1, err := helpers.PartyRoutine(nil, s)
+		require.NoError(t, err)
+		if id != self {
+			msgsOut1 = append(msgsOut1, msgs1...)
+		}
+		if id == other {
+			msgsOther = msgs1
+		}
+	}
+	s := states[self]
+	_, err := helpers.PartyRoutine(msgsOut1, s)
+	require.NoError(t, err)
+
+	// self is now in round 2, and suspends
+	key := newSnapshotKey(t)
+	snapshot, err := s.Suspend(key)
+	require.NoError(t, err)
+	s, _, err = frost.ResumeKeygenState(context.Background(), snapshot, key, nil, 0)
+	require.NoError(t, err)
+
+	// Retransmissions of round 1 messages are still ignored
+	require.NoError(t, helpers.HandleMessages(msgsOut1, s))
+
+	// A different round 1 message from the same party is an equivocation
+	equivocator, _, err := frost.NewKeygenState(context.Background(), other, partyIDs, 1, 0)
+	require.NoError(t, err)
+	msgsEquivocation, err := helpers.PartyRoutine(nil, equivocator)
+	require.NoError(t, err)
+	require.NotEqual(t, msgsOther, msgsEquivocation)
+	require.Error(t, helpers.HandleMessages(msgsEquivocation, s))
+
+	var equivocationErr *state.EquivocationError
+	require.True(t, errors.As(s.WaitForError(), &equivocationErr), "expected an EquivocationError")
+	assert.Equal(t, other, equivocationErr.PartyID)
+}
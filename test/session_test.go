@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func TestSession(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	session := messages.SessionID{1, 2, 3, 4}
+	other := messages.SessionID{5, 6, 7, 8}
+
+	states := map[party.ID]*state.State{}
+	for i, id := range partyIDs {
+		sessionID := session
+		if i == 2 {
+			sessionID = other
+		}
+		var err error
+		states[id], _, err = frost.NewKeygenState(context.Background(), id, partyIDs, 1, 0, state.WithSessionID(sessionID))
+		require.NoError(t, err)
+	}
+
+	msgs := map[party.ID]*messages.Message{}
+	for id, s := range states {
+		out := s.ProcessAll()
+		require.Len(t, out, 1)
+		msgs[id] = out[0]
+	}
+	assert.Equal(t, session, msgs[partyIDs[0]].SessionID)
+	assert.Equal(t, other, msgs[partyIDs[2]].SessionID)
+
+	// The session ID survives encoding.
+	data, err := msgs[partyIDs[0]].MarshalBinary()
+	require.NoError(t, err)
+	var decoded messages.Message
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, session, decoded.SessionID)
+
+	s := states[partyIDs[1]]
+	assert.NoError(t, s.HandleMessage(&decoded))
+	assert.Error(t, s.HandleMessage(msgs[partyIDs[2]]), "message from another session must be rejected")
+	assert.Equal(t, party.IDSlice{partyIDs[2]}, s.MissingParties())
+	assert.False(t, s.IsFinished())
+}
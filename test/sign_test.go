@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"crypto/ed25519"
 	"fmt"
 	"testing"
@@ -28,7 +29,7 @@ func TestSign(t *testing.T) {
 
 	for _, id := range signSet {
 		var err error
-		states[id], outputs[id], err = frost.NewSignState(signSet, secretShares[id], publicShares, MESSAGE, 0)
+		states[id], outputs[id], err = frost.NewSignState(context.Background(), signSet, secretShares[id], publicShares, MESSAGE, 0)
 		if err != nil {
 			t.Error(err)
 		}
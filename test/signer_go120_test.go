@@ -0,0 +1,51 @@
+//go:build go1.20
+// +build go1.20
+
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/signer"
+	"github.com/taurusgroup/frost-ed25519/test/internal/communication"
+)
+
+func TestSigner_Options(t *testing.T) {
+	_, signIDs, secretShares, publicShares := setupParties(2, 5)
+	groupKey := publicShares.GroupKey.ToEd25519()
+	digest := sha512.Sum512(MESSAGE)
+
+	tests := []struct {
+		name    string
+		message []byte
+		opts    *ed25519.Options
+	}{
+		{"Ed25519", MESSAGE, &ed25519.Options{}},
+		{"Ed25519ctx", MESSAGE, &ed25519.Options{Context: "frost test"}},
+		{"Ed25519ph", digest[:], &ed25519.Options{Hash: crypto.SHA512}},
+		{"Ed25519ph with context", digest[:], &ed25519.Options{Hash: crypto.SHA512, Context: "frost test"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sigs := signWithSigners(t, signIDs, secretShares, publicShares, func(cs crypto.Signer) ([]byte, error) {
+				return cs.Sign(rand.Reader, tt.message, tt.opts)
+			})
+			for id, sig := range sigs {
+				assert.NoError(t, ed25519.VerifyWithOptions(groupKey, tt.message, sig, tt.opts), "party %d", id)
+			}
+		})
+	}
+
+	id := signIDs[0]
+	comms := communication.NewChannelCommunicatorMap(signIDs)
+	cs, err := signer.New(signIDs, secretShares[id], publicShares, comms[id])
+	require.NoError(t, err)
+	_, err = cs.Sign(rand.Reader, MESSAGE, &ed25519.Options{Context: string(make([]byte, 256))})
+	assert.Error(t, err, "context too long")
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/signer"
+	"github.com/taurusgroup/frost-ed25519/test/internal/communication"
+)
+
+// signWithSigners runs sign on a frost.Signer for every party in signIDs,
+// and returns the signatures they produced.
+func signWithSigners(t *testing.T, signIDs party.IDSlice, secretShares map[party.ID]*eddsa.SecretShare, publicShares *eddsa.Public,
+	sign func(cs crypto.Signer) ([]byte, error)) map[party.ID][]byte {
+	comms := communication.NewChannelCommunicatorMap(signIDs)
+	defer func() {
+		for _, comm := range comms {
+			comm.Done()
+		}
+	}()
+
+	type result struct {
+		id  party.ID
+		sig []byte
+		err error
+	}
+	results := make(chan result, len(signIDs))
+	for _, id := range signIDs {
+		cs, err := signer.New(signIDs, secretShares[id], publicShares, comms[id])
+		require.NoError(t, err)
+		assert.Equal(t, crypto.PublicKey(publicShares.GroupKey.ToEd25519()), cs.Public())
+		go func(id party.ID) {
+			sig, err := sign(cs)
+			results <- result{id, sig, err}
+		}(id)
+	}
+
+	sigs := make(map[party.ID][]byte, len(signIDs))
+	for range signIDs {
+		r := <-results
+		require.NoError(t, r.err, "party %d", r.id)
+		sigs[r.id] = r.sig
+	}
+	return sigs
+}
+
+func TestSigner(t *testing.T) {
+	_, signIDs, secretShares, publicShares := setupParties(2, 5)
+	groupKey := publicShares.GroupKey.ToEd25519()
+
+	sigs := signWithSigners(t, signIDs, secretShares, publicShares, func(cs crypto.Signer) ([]byte, error) {
+		return cs.Sign(rand.Reader, MESSAGE, crypto.Hash(0))
+	})
+	for id, sig := range sigs {
+		assert.True(t, ed25519.Verify(groupKey, MESSAGE, sig), "party %d", id)
+	}
+
+	// Ed25519ph is selected by crypto.SHA512, and signs the digest of the message
+	digest := sha512.Sum512(MESSAGE)
+	sigs = signWithSigners(t, signIDs, secretShares, publicShares, func(cs crypto.Signer) ([]byte, error) {
+		return cs.Sign(rand.Reader, digest[:], crypto.SHA512)
+	})
+	for id, sig := range sigs {
+		assert.False(t, ed25519.Verify(groupKey, digest[:], sig), "party %d", id)
+		signature, err := eddsa.NewSignatureFromEd25519(sig)
+		require.NoError(t, err)
+		assert.True(t, publicShares.GroupKey.VerifyWithDomain(digest[:], signature, eddsa.Domain{Prehashed: true}), "party %d", id)
+	}
+}
+
+func TestSigner_InvalidOptions(t *testing.T) {
+	partyIDs, signIDs, secretShares, publicShares := setupParties(2, 5)
+	comms := communication.NewChannelCommunicatorMap(partyIDs)
+	id := signIDs[0]
+
+	_, err := signer.New(signIDs[:2], secretShares[id], publicShares, comms[id])
+	assert.Error(t, err, "not enough signers")
+	_, err = signer.New(signIDs[1:], secretShares[id], publicShares, comms[id])
+	assert.Error(t, err, "signer not in partyIDs")
+
+	cs, err := signer.New(signIDs, secretShares[id], publicShares, comms[id])
+	require.NoError(t, err)
+	_, err = cs.Sign(rand.Reader, MESSAGE, crypto.SHA256)
+	assert.Error(t, err, "unsupported hash")
+	_, err = cs.Sign(rand.Reader, MESSAGE, crypto.SHA512)
+	assert.Error(t, err, "message is not a SHA-512 digest")
+}
+
+func TestSigner_Certificate(t *testing.T) {
+	_, signIDs, secretShares, publicShares := setupParties(1, 3)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "FROST CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	certs := signWithSigners(t, signIDs, secretShares, publicShares, func(cs crypto.Signer) ([]byte, error) {
+		return x509.CreateCertificate(rand.Reader, template, template, cs.Public(), cs)
+	})
+	for id, der := range certs {
+		cert, err := x509.ParseCertificate(der)
+		require.NoError(t, err, "party %d", id)
+		assert.NoError(t, cert.CheckSignatureFrom(cert), "party %d", id)
+	}
+}
+
+func TestSigner_Repeated(t *testing.T) {
+	_, signIDs, secretShares, publicShares := setupParties(2, 5)
+	groupKey := publicShares.GroupKey.ToEd25519()
+
+	// The Signers are used several times with the same communicators,
+	// so that the messages of a signature may arrive while another party is still busy with the previous one.
+	msgs := [][]byte{MESSAGE, []byte("second message"), MESSAGE}
+	sigs := signWithSigners(t, signIDs, secretShares, publicShares, func(cs crypto.Signer) ([]byte, error) {
+		var sigs []byte
+		for _, msg := range msgs {
+			sig, err := cs.Sign(rand.Reader, msg, crypto.Hash(0))
+			if err != nil {
+				return nil, err
+			}
+			sigs = append(sigs, sig...)
+		}
+		return sigs, nil
+	})
+	for id, sig := range sigs {
+		require.Len(t, sig, len(msgs)*ed25519.SignatureSize, "party %d", id)
+		for i, msg := range msgs {
+			assert.True(t, ed25519.Verify(groupKey, msg, sig[i*ed25519.SignatureSize:(i+1)*ed25519.SignatureSize]), "party %d, signature %d", id, i)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func TestTimeout_MissingParties(t *testing.T) {
+	N := party.Size(4)
+	T := N - 1
+
+	_, signSet, secretShares, publicShares := setupParties(T, N)
+	silent := signSet[2]
+
+	states := map[party.ID]*state.State{}
+	for _, id := range signSet {
+		var err error
+		states[id], _, err = frost.NewSignState(context.Background(), signSet, secretShares[id], publicShares, MESSAGE, 100*time.Millisecond)
+		require.NoError(t, err)
+	}
+
+	var msgs1 [][]byte
+	for id, s := range states {
+		out, err := helpers.PartyRoutine(nil, s)
+		require.NoError(t, err)
+		if id != silent {
+			msgs1 = append(msgs1, out...)
+		}
+	}
+	for id, s := range states {
+		if id == silent {
+			continue
+		}
+		_, err := helpers.PartyRoutine(msgs1, s)
+		require.NoError(t, err)
+		assert.Equal(t, party.IDSlice{silent}, s.MissingParties())
+	}
+
+	for id, s := range states {
+		err := s.WaitForError()
+		require.Error(t, err)
+
+		var timeoutErr *state.TimeoutError
+		require.True(t, errors.As(err, &timeoutErr), "party %d: expected a TimeoutError, got %v", id, err)
+		assert.Equal(t, 1, timeoutErr.RoundNumber)
+
+		var stateErr *state.Error
+		require.True(t, errors.As(err, &stateErr))
+		assert.Equal(t, party.ID(0), stateErr.PartyID)
+		assert.Equal(t, 1, stateErr.RoundNumber)
+
+		if id == silent {
+			// the silent party did not receive anything
+			assert.Len(t, timeoutErr.Missing, len(signSet)-1)
+			assert.NotContains(t, timeoutErr.Missing, silent)
+		} else {
+			assert.Equal(t, party.IDSlice{silent}, timeoutErr.Missing)
+		}
+		assert.Nil(t, s.MissingParties())
+	}
+}
+
+func TestTimeout_RoundTimeout(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	self := partyIDs[0]
+
+	states := map[party.ID]*state.State{}
+	for _, id := range partyIDs {
+		var err error
+		if id == self {
+			// The idle timeout is long, but the round must complete quickly.
+			states[id], _, err = frost.NewKeygenState(context.Background(), id, partyIDs, 1, time.Minute,
+				state.WithRoundTimeout(150*time.Millisecond))
+		} else {
+			states[id], _, err = frost.NewKeygenState(context.Background(), id, partyIDs, 1, 0)
+		}
+		require.NoError(t, err)
+	}
+
+	var msgs1 []*messages.Message
+	for id, s := range states {
+		out := s.ProcessAll()
+		if id != self {
+			msgs1 = append(msgs1, out...)
+		}
+	}
+
+	// Deliver a single message, which does not extend the round deadline.
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, states[self].HandleMessage(msgs1[0]))
+
+	err := states[self].WaitForError()
+	var timeoutErr *state.TimeoutError
+	require.True(t, errors.As(err, &timeoutErr), "expected a TimeoutError, got %v", err)
+	assert.Equal(t, 1, timeoutErr.RoundNumber)
+	require.Len(t, timeoutErr.Missing, 1)
+	assert.NotEqual(t, msgs1[0].From, timeoutErr.Missing[0])
+}
+
+func TestTimeout_Expire(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	s, _, err := frost.NewKeygenState(context.Background(), partyIDs[0], partyIDs, 1, 0)
+	require.NoError(t, err)
+	_, err = helpers.PartyRoutine(nil, s)
+	require.NoError(t, err)
+
+	s.Expire()
+	var timeoutErr *state.TimeoutError
+	require.True(t, errors.As(s.WaitForError(), &timeoutErr))
+	assert.Equal(t, partyIDs[1:], timeoutErr.Missing)
+	assert.EqualError(t, timeoutErr, "timeout in round 1: no message from parties "+partyIDs[1].String()+", "+partyIDs[2].String())
+
+	// Expiring a finished State does nothing.
+	s.Expire()
+	assert.True(t, errors.As(s.WaitForError(), &timeoutErr))
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// The transcripts in testdata/ contain every message of a keygen and a sign execution,
+// where each party draws its randomness from helpers.NewDeterministicReader.
+// They change whenever the messages or the computations of the protocols change, and can be regenerated with
+//
+//	go test ./test -run TestTranscript -update
+var update = flag.Bool("update", false, "update the transcripts in testdata/")
+
+var transcriptSession = messages.SessionID{0x46, 0x52, 0x4f, 0x53, 0x54, 0x2d, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x43, 0x52, 0x49, 0x50, 0x54}
+
+// transcriptRandom returns the source of randomness of the party id in the protocol named name.
+func transcriptRandom(name string, id party.ID) state.Option {
+	var idBytes [4]byte
+	binary.BigEndian.PutUint32(idBytes[:], uint32(id))
+	return state.WithRandom(helpers.NewDeterministicReader([]byte("frost-ed25519 transcript"), []byte(name), idBytes[:]))
+}
+
+// runTranscript executes the protocol between the parties in states, in order of their IDs,
+// and returns the list of all messages sent, one per line.
+func runTranscript(t *testing.T, partyIDs party.IDSlice, states map[party.ID]*state.State) string {
+	var b strings.Builder
+	for round := 0; !states[partyIDs[0]].IsFinished(); round++ {
+		require.Less(t, round, 10, "protocol did not finish")
+		var out []*messages.Message
+		for _, id := range partyIDs {
+			out = append(out, states[id].ProcessAll()...)
+		}
+		for _, msg := range out {
+			data, err := msg.MarshalBinary()
+			require.NoError(t, err)
+			to := "*"
+			if !msg.IsBroadcast() {
+				to = fmt.Sprint(msg.To)
+			}
+			fmt.Fprintf(&b, "%d %s %d -> %s %x\n", round, msg.Type, msg.From, to, data)
+
+			for _, id := range partyIDs {
+				if id == msg.From || !(msg.IsBroadcast() || msg.To == id) {
+					continue
+				}
+				var received messages.Message
+				require.NoError(t, received.UnmarshalBinary(data))
+				require.NoError(t, states[id].HandleMessage(&received))
+			}
+		}
+	}
+	for _, id := range partyIDs {
+		require.NoError(t, states[id].WaitForError())
+	}
+	return b.String()
+}
+
+// checkTranscript compares transcript with the golden file testdata/name.transcript.
+func checkTranscript(t *testing.T, name, transcript string) {
+	path := filepath.Join("testdata", name+".transcript")
+	if *update {
+		require.NoError(t, ioutil.WriteFile(path, []byte(transcript), 0644))
+	}
+	expected, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), transcript, "transcript of %s changed", name)
+}
+
+func TestTranscript(t *testing.T) {
+	const T = 1
+	partyIDs := helpers.GenerateSet(3)
+	signIDs := partyIDs[1:]
+
+	run := func() (string, map[party.ID]*keygen.Output, *sign.Output) {
+		states := map[party.ID]*state.State{}
+		keygenOutputs := map[party.ID]*keygen.Output{}
+		for _, id := range partyIDs {
+			var err error
+			states[id], keygenOutputs[id], err = frost.NewKeygenState(context.Background(), id, partyIDs, T, 0,
+				state.WithSessionID(transcriptSession), transcriptRandom("keygen", id))
+			require.NoError(t, err)
+		}
+		transcript := runTranscript(t, partyIDs, states)
+
+		public := keygenOutputs[partyIDs[0]].Public
+		signOutputs := map[party.ID]*sign.Output{}
+		for _, id := range signIDs {
+			var err error
+			states[id], signOutputs[id], err = frost.NewSignState(context.Background(), signIDs, keygenOutputs[id].SecretKey, public, MESSAGE, 0,
+				state.WithSessionID(transcriptSession), transcriptRandom("sign", id))
+			require.NoError(t, err)
+		}
+		transcript += runTranscript(t, signIDs, states)
+		return transcript, keygenOutputs, signOutputs[signIDs[0]]
+	}
+
+	transcript, keygenOutputs, signOutput := run()
+	public := keygenOutputs[partyIDs[0]].Public
+	require.True(t, public.GroupKey.Verify(MESSAGE, signOutput.Signature))
+
+	var b strings.Builder
+	b.WriteString(transcript)
+	fmt.Fprintf(&b, "group key %x\n", public.GroupKey.ToEd25519())
+	for _, id := range partyIDs {
+		fmt.Fprintf(&b, "public share %d %x\n", id, public.Shares[id].Bytes())
+	}
+	fmt.Fprintf(&b, "signature %x\n", signOutput.Signature.ToEd25519())
+	checkTranscript(t, "frost", b.String())
+
+	// A second execution with the same randomness produces the same messages.
+	transcript2, _, _ := run()
+	assert.Equal(t, transcript, transcript2)
+}
+
+func TestWithRandom_Unsupported(t *testing.T) {
+	_, err := state.NewBaseState(context.Background(), unrandomizedRound{}, 0, state.WithRandom(helpers.NewDeterministicReader()))
+	assert.Error(t, err)
+}
+
+// unrandomizedRound does not embed state.BaseRound, and therefore does not support state.WithRandom.
+type unrandomizedRound struct{}
+
+func (unrandomizedRound) ProcessMessage(*messages.Message) *state.Error {
+	return nil
+}
+
+func (unrandomizedRound) GenerateMessages() ([]*messages.Message, *state.Error) {
+	return nil, nil
+}
+
+func (unrandomizedRound) NextRound() state.Round {
+	return nil
+}
+
+func (unrandomizedRound) AcceptedMessageTypes() []messages.MessageType {
+	return []messages.MessageType{messages.MessageTypeNone}
+}
+
+func (unrandomizedRound) Reset() {}
+
+func (unrandomizedRound) SelfID() party.ID {
+	return 1
+}
+
+func (unrandomizedRound) PartyIDs() party.IDSlice {
+	return party.IDSlice{1, 2}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+)
+
+// TestVerifyModes checks that threshold signatures are accepted by all verification rules.
+func TestVerifyModes(t *testing.T) {
+	_, signIDs, secretShares, publicShares := setupParties(2, 5)
+	groupKey := publicShares.GroupKey.ToEd25519()
+
+	sigs := signWithSigners(t, signIDs, secretShares, publicShares, func(cs crypto.Signer) ([]byte, error) {
+		return cs.Sign(rand.Reader, MESSAGE, crypto.Hash(0))
+	})
+	digest := sha512.Sum512(MESSAGE)
+	sigsPh := signWithSigners(t, signIDs, secretShares, publicShares, func(cs crypto.Signer) ([]byte, error) {
+		return cs.Sign(rand.Reader, digest[:], crypto.SHA512)
+	})
+
+	for _, m := range eddsa.VerifyModes {
+		for id, sig := range sigs {
+			assert.True(t, m.Verify(groupKey, MESSAGE, sig), "%v: party %d", m, id)
+		}
+		for id, sig := range sigsPh {
+			assert.True(t, m.VerifyWithDomain(groupKey, digest[:], sig, eddsa.Domain{Prehashed: true}), "%v: party %d", m, id)
+			assert.False(t, m.Verify(groupKey, digest[:], sig), "%v: party %d", m, id)
+		}
+	}
+}